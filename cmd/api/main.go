@@ -2,17 +2,20 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	app "github.com/mohammadpnp/user-import/internal/application/user"
 	"github.com/mohammadpnp/user-import/internal/bootstrap"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 	infrafile "github.com/mohammadpnp/user-import/internal/infrastructure/file"
 	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
 	"gorm.io/driver/postgres"
@@ -46,13 +49,35 @@ func main() {
 	defer stopWorkers()
 
 	importJobRepo := repository.NewImportJobRepository(db)
-	userImporter := repository.NewUserBulkImportRepository(pool)
+	stagingTables, err := repository.ParseStagingTables(os.Getenv("IMPORT_STAGING_TABLES"))
+	if err != nil {
+		log.Fatalf("invalid IMPORT_STAGING_TABLES: %v", err)
+	}
+	userIDVersion, err := parseUserIDVersion(os.Getenv("IMPORT_USER_ID_VERSION"))
+	if err != nil {
+		log.Fatalf("invalid IMPORT_USER_ID_VERSION: %v", err)
+	}
+	userImporter := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{
+		GenerateUUIDv7: userIDVersion == 7,
+		StagingTables:  stagingTables,
+	})
+	emailRules, err := domain.ParseEmailProviderRules(os.Getenv("IMPORT_EMAIL_PROVIDER_RULES"))
+	if err != nil {
+		log.Fatalf("invalid IMPORT_EMAIL_PROVIDER_RULES: %v", err)
+	}
+	fieldLimits, err := repository.UserFieldLimits()
+	if err != nil {
+		log.Fatalf("failed to derive user field limits: %v", err)
+	}
 	sourceReader := infrafile.NewLocalSource(getEnv("IMPORT_BASE_DIR", "."))
 
 	worker := app.NewImportWorker(importJobRepo, sourceReader, userImporter, app.ImportWorkerConfig{
-		Workers:       parseWorkerCount(),
-		ChunkSize:     parseIntEnv("IMPORT_CHUNK_SIZE", 10000),
-		LeaseDuration: time.Duration(parseIntEnv("IMPORT_JOB_LEASE_SECONDS", 60)) * time.Second,
+		Workers:          parseWorkerCount(),
+		ChunkSize:        parseIntEnv("IMPORT_CHUNK_SIZE", 10000),
+		ChunkConcurrency: parseIntEnv("IMPORT_CHUNK_CONCURRENCY", 1),
+		LeaseDuration:    time.Duration(parseIntEnv("IMPORT_JOB_LEASE_SECONDS", 60)) * time.Second,
+		EmailNormalizer:  domain.NewEmailNormalizer(emailRules...),
+		FieldLimits:      fieldLimits,
 	})
 	worker.Start(workerCtx)
 
@@ -87,6 +112,17 @@ func parseWorkerCount() int {
 	return workers
 }
 
+func parseUserIDVersion(raw string) (int, error) {
+	switch raw = strings.TrimSpace(raw); raw {
+	case "", "4":
+		return 4, nil
+	case "7":
+		return 7, nil
+	default:
+		return 0, fmt.Errorf("%q is not a supported UUID version (4 or 7)", raw)
+	}
+}
+
 func parseIntEnv(key string, fallback int) int {
 	raw := os.Getenv(key)
 	if raw == "" {
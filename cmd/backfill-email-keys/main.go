@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+)
+
+// backfill-email-keys recomputes users.email_key with the same
+// IMPORT_EMAIL_PROVIDER_RULES as the API. It exits with status 1 when users
+// are left without a key because another user holds it.
+func main() {
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL is required")
+	}
+	emailRules, err := domain.ParseEmailProviderRules(os.Getenv("IMPORT_EMAIL_PROVIDER_RULES"))
+	if err != nil {
+		log.Fatalf("invalid IMPORT_EMAIL_PROVIDER_RULES: %v", err)
+	}
+
+	pool, err := pgxpool.New(context.Background(), databaseURL)
+	if err != nil {
+		log.Fatalf("failed to create pgx pool: %v", err)
+	}
+	defer pool.Close()
+
+	result, err := repository.NewEmailKeyBackfill(pool).Run(context.Background(), domain.NewEmailNormalizer(emailRules...))
+	if err != nil {
+		log.Fatalf("email key backfill failed: %v", err)
+	}
+
+	log.Printf("checked %d users, set %d email keys", result.Users, result.Rekeyed)
+	for _, id := range result.InvalidUserIDs {
+		log.Printf("user %s: email is not valid, email key left unchanged", id)
+	}
+	for _, collision := range result.Collisions {
+		log.Printf("email key %s: kept by user %s, users %s left without a key (emails %s)",
+			collision.EmailKey, collision.UserIDs[0], strings.Join(collision.UserIDs[1:], ", "), strings.Join(collision.Emails, ", "))
+	}
+	if len(result.Collisions) > 0 {
+		pool.Close()
+		log.Fatalf("%d email key collision(s) need review, see user_email_key_collisions", len(result.Collisions))
+	}
+}
@@ -0,0 +1,57 @@
+package user
+
+import (
+	"runtime/metrics"
+	"unsafe"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+// userFootprint estimates the bytes a validated user keeps alive until its
+// chunk is written.
+func userFootprint(user domain.User) int64 {
+	size := int64(unsafe.Sizeof(user)) +
+		int64(len(user.ID)+len(user.Name)+len(user.Email)+len(user.EmailKey)+len(user.PhoneNumber)+len(user.PhoneNumberRaw))
+	for _, address := range user.Addresses {
+		size += int64(unsafe.Sizeof(address)) +
+			int64(len(address.Street)+len(address.City)+len(address.State)+len(address.ZipCode)+len(address.Country)+len(address.Type))
+	}
+	return size + valueFootprint(user.Attributes)
+}
+
+func valueFootprint(value any) int64 {
+	const header = 16
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		return header + int64(len(v))
+	case map[string]any:
+		size := int64(header)
+		for key, item := range v {
+			size += header + int64(len(key)) + valueFootprint(item)
+		}
+		return size
+	case []any:
+		size := int64(header)
+		for _, item := range v {
+			size += valueFootprint(item)
+		}
+		return size
+	default:
+		return header
+	}
+}
+
+// heapBytes reports the bytes held by live and not yet swept heap objects; it
+// does not stop the world.
+func heapBytes() int64 {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return int64(sample[0].Value.Uint64())
+}
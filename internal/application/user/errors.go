@@ -3,9 +3,23 @@ package user
 import "errors"
 
 var (
-	ErrInvalidImportSource = errors.New("invalid import source")
-	ErrEnqueueImportJob    = errors.New("failed to enqueue import job")
-	ErrInvalidUserID       = errors.New("invalid user id")
-	ErrUserNotFound        = errors.New("user not found")
-	ErrGetUserByID         = errors.New("failed to get user by id")
+	ErrInvalidImportSource  = errors.New("invalid import source")
+	ErrInvalidImportOptions = errors.New("invalid import options")
+	ErrEnqueueImportJob     = errors.New("failed to enqueue import job")
+	ErrInvalidUserID        = errors.New("invalid user id")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrGetUserByID          = errors.New("failed to get user by id")
+	ErrInvalidUserFilter    = errors.New("invalid user filter")
+	ErrListUsers            = errors.New("failed to list users")
+	ErrInvalidImportJobID   = errors.New("invalid import job id")
+	ErrImportJobNotFound    = errors.New("import job not found")
+	ErrGetImportJob         = errors.New("failed to get import job")
+
+	ErrInvalidImportProfile  = errors.New("invalid import profile")
+	ErrImportProfileNotFound = errors.New("import profile not found")
+	ErrImportProfileExists   = errors.New("import profile already exists")
+	ErrGetImportProfile      = errors.New("failed to get import profile")
+	ErrSaveImportProfile     = errors.New("failed to save import profile")
+
+	ErrInvalidSourceTimestamp = errors.New("invalid source timestamp")
 )
@@ -0,0 +1,340 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const (
+	defaultConflictPageSize = 100
+	maxConflictPageSize     = 1000
+)
+
+type GetImportJobInput struct {
+	ID string
+}
+
+type GetImportJobOutput struct {
+	ID             string           `json:"id"`
+	ParentID       string           `json:"parent_id,omitempty"`
+	Partition      *PartitionOutput `json:"partition,omitempty"`
+	SourcePath     string           `json:"source_path"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	MaxAttempts    int              `json:"max_attempts"`
+	ProcessedCount int64            `json:"processed_count"`
+	ImportedCount  int64            `json:"imported_count"`
+	UpdatedCount   int64            `json:"updated_count"`
+	SkippedCount   int64            `json:"skipped_count"`
+	FailedCount    int64            `json:"failed_count"`
+	WarningCount   int64            `json:"warning_count"`
+	UnknownFields  map[string]int64 `json:"unknown_fields,omitempty"`
+	Metadata       map[string]any   `json:"metadata,omitempty"`
+	ExpectedCount  *int64           `json:"expected_count,omitempty"`
+	CountMismatch  bool             `json:"count_mismatch,omitempty"`
+	ErrorMessage   string           `json:"error_message,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	StartedAt      *time.Time       `json:"started_at,omitempty"`
+	FinishedAt     *time.Time       `json:"finished_at,omitempty"`
+
+	ChunkStats *ChunkStatsOutput       `json:"chunk_stats,omitempty"`
+	Partitions []ImportPartitionOutput `json:"partitions,omitempty"`
+	Failures   []ImportFailureOutput   `json:"failures,omitempty"`
+	Skipped    []ImportSkipOutput      `json:"skipped,omitempty"`
+	Warnings   []ImportWarningOutput   `json:"warnings,omitempty"`
+}
+
+type RecordLocatorOutput struct {
+	Row    int64 `json:"row"`
+	Line   int64 `json:"line,omitempty"`
+	Offset int64 `json:"offset,omitempty"`
+}
+
+type ImportFailureOutput struct {
+	RowIndex int64               `json:"row_index"`
+	Locator  RecordLocatorOutput `json:"locator"`
+	Reason   string              `json:"reason"`
+	RuleID   string              `json:"rule_id,omitempty"`
+	Field    string              `json:"field,omitempty"`
+}
+
+type ImportSkipOutput struct {
+	RowIndex int64               `json:"row_index"`
+	Locator  RecordLocatorOutput `json:"locator"`
+	Reason   string              `json:"reason"`
+}
+
+type ImportWarningOutput struct {
+	RowIndex int64               `json:"row_index"`
+	Locator  RecordLocatorOutput `json:"locator"`
+	Reason   string              `json:"reason"`
+	Field    string              `json:"field,omitempty"`
+}
+
+type ChunkStatsOutput struct {
+	Chunks        int64   `json:"chunks"`
+	Rows          int64   `json:"rows"`
+	Bytes         int64   `json:"bytes"`
+	AvgChunkBytes int64   `json:"avg_chunk_bytes"`
+	MaxChunkRows  int64   `json:"max_chunk_rows"`
+	MaxChunkBytes int64   `json:"max_chunk_bytes"`
+	WriteMillis   int64   `json:"write_ms"`
+	RowsPerSecond float64 `json:"rows_per_second"`
+	MaxHeapBytes  int64   `json:"max_heap_bytes"`
+}
+
+type PartitionOutput struct {
+	Index int   `json:"index"`
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+type ImportPartitionOutput struct {
+	ID             string `json:"id"`
+	Index          int    `json:"index"`
+	Start          int64  `json:"start"`
+	End            int64  `json:"end"`
+	Status         string `json:"status"`
+	Attempts       int    `json:"attempts"`
+	ProcessedCount int64  `json:"processed_count"`
+	FailedCount    int64  `json:"failed_count"`
+	ErrorMessage   string `json:"error_message,omitempty"`
+}
+
+type ListImportConflictsInput struct {
+	JobID  string
+	Limit  int
+	Offset int
+}
+
+type ImportConflictOutput struct {
+	RowIndex    int64               `json:"row_index"`
+	Locator     RecordLocatorOutput `json:"locator"`
+	ExternalID  string              `json:"external_id"`
+	IDUserID    string              `json:"id_user_id"`
+	EmailUserID string              `json:"email_user_id"`
+	Resolution  string              `json:"resolution"`
+}
+
+type ListImportConflictsOutput struct {
+	Conflicts []ImportConflictOutput `json:"conflicts"`
+	Limit     int                    `json:"limit"`
+	Offset    int                    `json:"offset"`
+}
+
+type GetImportJob interface {
+	Execute(ctx context.Context, in GetImportJobInput) (GetImportJobOutput, error)
+}
+
+type ListImportConflicts interface {
+	Execute(ctx context.Context, in ListImportConflictsInput) (ListImportConflictsOutput, error)
+}
+
+type importJobReader interface {
+	GetByID(ctx context.Context, jobID string) (*domain.ImportJob, error)
+	ListPartitions(ctx context.Context, jobID string) ([]domain.ImportJob, error)
+	ListConflicts(ctx context.Context, jobID string, limit, offset int) ([]domain.ImportConflict, error)
+	ListFailures(ctx context.Context, jobID string) ([]domain.ImportFailure, error)
+	ListSkipped(ctx context.Context, jobID string) ([]domain.ImportSkip, error)
+	ListWarnings(ctx context.Context, jobID string) ([]domain.ImportWarning, error)
+}
+
+type getImportJob struct {
+	repo importJobReader
+}
+
+func NewGetImportJob(repo importJobReader) GetImportJob {
+	return &getImportJob{repo: repo}
+}
+
+func (uc *getImportJob) Execute(ctx context.Context, in GetImportJobInput) (GetImportJobOutput, error) {
+	job, err := loadImportJob(ctx, uc.repo, in.ID)
+	if err != nil {
+		return GetImportJobOutput{}, err
+	}
+
+	out := GetImportJobOutput{
+		ID:             job.ID,
+		ParentID:       job.ParentID,
+		SourcePath:     job.SourcePath,
+		Status:         job.Status,
+		Attempts:       job.Attempts,
+		MaxAttempts:    job.MaxAttempts,
+		ProcessedCount: job.Progress.ProcessedCount,
+		ImportedCount:  job.Progress.ImportedCount,
+		UpdatedCount:   job.Progress.UpdatedCount,
+		SkippedCount:   job.Progress.SkippedCount,
+		FailedCount:    job.Progress.FailedCount,
+		WarningCount:   job.Progress.WarningCount,
+		UnknownFields:  job.UnknownFields,
+		Metadata:       job.Metadata,
+		ExpectedCount:  job.ExpectedCount,
+		CountMismatch:  job.ExpectedCount != nil && *job.ExpectedCount != job.Progress.ProcessedCount,
+		ErrorMessage:   job.ErrorMessage,
+		CreatedAt:      job.CreatedAt,
+		StartedAt:      job.StartedAt,
+		FinishedAt:     job.FinishedAt,
+	}
+	if stats := job.Progress.ChunkStats; stats.Chunks > 0 {
+		out.ChunkStats = &ChunkStatsOutput{
+			Chunks:        stats.Chunks,
+			Rows:          stats.Rows,
+			Bytes:         stats.Bytes,
+			AvgChunkBytes: stats.Bytes / stats.Chunks,
+			MaxChunkRows:  stats.MaxChunkRows,
+			MaxChunkBytes: stats.MaxChunkBytes,
+			WriteMillis:   stats.WriteDuration.Milliseconds(),
+			RowsPerSecond: math.Round(stats.RowsPerSecond()*10) / 10,
+			MaxHeapBytes:  stats.MaxHeapBytes,
+		}
+	}
+	if job.Partition != nil {
+		out.Partition = &PartitionOutput{Index: job.Partition.Index, Start: job.Partition.Start, End: job.Partition.End}
+	}
+	if err := uc.loadFailures(ctx, job.ID, &out); err != nil {
+		return GetImportJobOutput{}, fmt.Errorf("%w: %v", ErrGetImportJob, err)
+	}
+	if job.Status != domain.ImportJobStatusWaiting && job.Options.Partitions < 2 {
+		return out, nil
+	}
+
+	partitions, err := uc.repo.ListPartitions(ctx, job.ID)
+	if err != nil {
+		return GetImportJobOutput{}, fmt.Errorf("%w: %v", ErrGetImportJob, err)
+	}
+	for _, partition := range partitions {
+		item := ImportPartitionOutput{
+			ID:             partition.ID,
+			Status:         partition.Status,
+			Attempts:       partition.Attempts,
+			ProcessedCount: partition.Progress.ProcessedCount,
+			FailedCount:    partition.Progress.FailedCount,
+			ErrorMessage:   partition.ErrorMessage,
+		}
+		if partition.Partition != nil {
+			item.Index = partition.Partition.Index
+			item.Start = partition.Partition.Start
+			item.End = partition.Partition.End
+		}
+		out.Partitions = append(out.Partitions, item)
+	}
+	return out, nil
+}
+
+func (uc *getImportJob) loadFailures(ctx context.Context, jobID string, out *GetImportJobOutput) error {
+	failures, err := uc.repo.ListFailures(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	for _, failure := range failures {
+		out.Failures = append(out.Failures, ImportFailureOutput{
+			RowIndex: failure.RowIndex,
+			Locator:  locatorOutput(failure.Locator),
+			Reason:   failure.Reason,
+			RuleID:   failure.RuleID,
+			Field:    failure.Field,
+		})
+	}
+
+	skipped, err := uc.repo.ListSkipped(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	for _, skip := range skipped {
+		out.Skipped = append(out.Skipped, ImportSkipOutput{
+			RowIndex: skip.RowIndex,
+			Locator:  locatorOutput(skip.Locator),
+			Reason:   skip.Reason,
+		})
+	}
+
+	warnings, err := uc.repo.ListWarnings(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	for _, warning := range warnings {
+		out.Warnings = append(out.Warnings, ImportWarningOutput{
+			RowIndex: warning.RowIndex,
+			Locator:  locatorOutput(warning.Locator),
+			Reason:   warning.Reason,
+			Field:    warning.Field,
+		})
+	}
+	return nil
+}
+
+func locatorOutput(locator domain.RecordLocator) RecordLocatorOutput {
+	return RecordLocatorOutput{Row: locator.Row, Line: locator.Line, Offset: locator.Offset}
+}
+
+type listImportConflicts struct {
+	repo importJobReader
+}
+
+func NewListImportConflicts(repo importJobReader) ListImportConflicts {
+	return &listImportConflicts{repo: repo}
+}
+
+func (uc *listImportConflicts) Execute(ctx context.Context, in ListImportConflictsInput) (ListImportConflictsOutput, error) {
+	job, err := loadImportJob(ctx, uc.repo, in.JobID)
+	if err != nil {
+		return ListImportConflictsOutput{}, err
+	}
+
+	limit := in.Limit
+	if limit <= 0 {
+		limit = defaultConflictPageSize
+	}
+	if limit > maxConflictPageSize {
+		limit = maxConflictPageSize
+	}
+	offset := in.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	conflicts, err := uc.repo.ListConflicts(ctx, job.ID, limit, offset)
+	if err != nil {
+		return ListImportConflictsOutput{}, fmt.Errorf("%w: %v", ErrGetImportJob, err)
+	}
+
+	out := ListImportConflictsOutput{
+		Conflicts: make([]ImportConflictOutput, 0, len(conflicts)),
+		Limit:     limit,
+		Offset:    offset,
+	}
+	for _, conflict := range conflicts {
+		out.Conflicts = append(out.Conflicts, ImportConflictOutput{
+			RowIndex:    conflict.RowIndex,
+			Locator:     locatorOutput(conflict.Locator),
+			ExternalID:  conflict.ExternalID,
+			IDUserID:    conflict.IDUserID,
+			EmailUserID: conflict.EmailUserID,
+			Resolution:  string(conflict.Resolution),
+		})
+	}
+
+	return out, nil
+}
+
+func loadImportJob(ctx context.Context, repo importJobReader, rawID string) (*domain.ImportJob, error) {
+	jobID, err := domain.ParseUUID(rawID)
+	if err != nil {
+		return nil, ErrInvalidImportJobID
+	}
+
+	job, err := repo.GetByID(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportJobNotFound) {
+			return nil, ErrImportJobNotFound
+		}
+		return nil, fmt.Errorf("%w: %v", ErrGetImportJob, err)
+	}
+
+	return job, nil
+}
@@ -0,0 +1,289 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+type fakeImportJobReader struct {
+	job          *domain.ImportJob
+	conflicts    []domain.ImportConflict
+	failures     []domain.ImportFailure
+	skipped      []domain.ImportSkip
+	warnings     []domain.ImportWarning
+	partitions   []domain.ImportJob
+	returnErr    error
+	gotLimit     int
+	gotOffset    int
+	listedJobIDs []string
+}
+
+func (f *fakeImportJobReader) GetByID(ctx context.Context, jobID string) (*domain.ImportJob, error) {
+	if f.returnErr != nil {
+		return nil, f.returnErr
+	}
+	return f.job, nil
+}
+
+func (f *fakeImportJobReader) ListPartitions(ctx context.Context, jobID string) ([]domain.ImportJob, error) {
+	return f.partitions, nil
+}
+
+func (f *fakeImportJobReader) ListConflicts(ctx context.Context, jobID string, limit, offset int) ([]domain.ImportConflict, error) {
+	f.listedJobIDs = append(f.listedJobIDs, jobID)
+	f.gotLimit = limit
+	f.gotOffset = offset
+	return f.conflicts, nil
+}
+
+func (f *fakeImportJobReader) ListFailures(ctx context.Context, jobID string) ([]domain.ImportFailure, error) {
+	return f.failures, nil
+}
+
+func (f *fakeImportJobReader) ListSkipped(ctx context.Context, jobID string) ([]domain.ImportSkip, error) {
+	return f.skipped, nil
+}
+
+func (f *fakeImportJobReader) ListWarnings(ctx context.Context, jobID string) ([]domain.ImportWarning, error) {
+	return f.warnings, nil
+}
+
+func TestGetImportJobSuccess(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobReader{job: &domain.ImportJob{
+		ID:         "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+		SourcePath: "users_data.json",
+		Status:     "succeeded",
+		Progress:   domain.ImportProgress{ProcessedCount: 10, ImportedCount: 7, FailedCount: 3},
+	}}
+
+	out, err := app.NewGetImportJob(repo).Execute(context.Background(), app.GetImportJobInput{ID: "4955eb4d-c7f2-42f6-80ca-33838ce37c31"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Status != "succeeded" || out.ProcessedCount != 10 || out.FailedCount != 3 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if out.ChunkStats != nil {
+		t.Fatalf("expected no chunk stats before a chunk is written, got %+v", out.ChunkStats)
+	}
+}
+
+func TestGetImportJobReturnsStoredFailures(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobReader{
+		job: &domain.ImportJob{
+			ID:       "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+			Status:   "succeeded",
+			Progress: domain.ImportProgress{ProcessedCount: 3, FailedCount: 1, SkippedCount: 2},
+		},
+		failures: []domain.ImportFailure{{
+			RowIndex: 0,
+			Locator:  domain.RecordLocator{Row: 1, Line: 2, Offset: 4},
+			Reason:   domain.FailureReasonInvalidPhoneNumber,
+		}},
+		skipped: []domain.ImportSkip{{
+			RowIndex: 2,
+			Locator:  domain.RecordLocator{Row: 3, Line: 9, Offset: 120},
+			Reason:   domain.SkipReasonStaleSourceTimestamp,
+		}},
+		warnings: []domain.ImportWarning{{
+			RowIndex: 1,
+			Locator:  domain.RecordLocator{Row: 2, Line: 5, Offset: 60},
+			Reason:   domain.WarningReasonValueTruncated,
+			Field:    "addresses[0].street",
+		}},
+	}
+
+	out, err := app.NewGetImportJob(repo).Execute(context.Background(), app.GetImportJobInput{ID: "4955eb4d-c7f2-42f6-80ca-33838ce37c31"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	wantFailure := app.ImportFailureOutput{
+		RowIndex: 0,
+		Locator:  app.RecordLocatorOutput{Row: 1, Line: 2, Offset: 4},
+		Reason:   domain.FailureReasonInvalidPhoneNumber,
+	}
+	if len(out.Failures) != 1 || out.Failures[0] != wantFailure {
+		t.Fatalf("unexpected failures: %+v", out.Failures)
+	}
+	wantSkip := app.ImportSkipOutput{
+		RowIndex: 2,
+		Locator:  app.RecordLocatorOutput{Row: 3, Line: 9, Offset: 120},
+		Reason:   domain.SkipReasonStaleSourceTimestamp,
+	}
+	if len(out.Skipped) != 1 || out.Skipped[0] != wantSkip {
+		t.Fatalf("unexpected skipped rows: %+v", out.Skipped)
+	}
+	wantWarning := app.ImportWarningOutput{
+		RowIndex: 1,
+		Locator:  app.RecordLocatorOutput{Row: 2, Line: 5, Offset: 60},
+		Reason:   domain.WarningReasonValueTruncated,
+		Field:    "addresses[0].street",
+	}
+	if len(out.Warnings) != 1 || out.Warnings[0] != wantWarning {
+		t.Fatalf("unexpected warnings: %+v", out.Warnings)
+	}
+}
+
+func TestGetImportJobReportsChunkStats(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobReader{job: &domain.ImportJob{
+		ID:     "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+		Status: "running",
+		Progress: domain.ImportProgress{
+			ProcessedCount: 3000,
+			ChunkStats: domain.ImportChunkStats{
+				Chunks:        3,
+				Rows:          3000,
+				Bytes:         1_500_000,
+				MaxChunkRows:  1000,
+				MaxChunkBytes: 600_000,
+				WriteDuration: 1500 * time.Millisecond,
+				MaxHeapBytes:  64 << 20,
+			},
+		},
+	}}
+
+	out, err := app.NewGetImportJob(repo).Execute(context.Background(), app.GetImportJobInput{ID: "4955eb4d-c7f2-42f6-80ca-33838ce37c31"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := &app.ChunkStatsOutput{
+		Chunks:        3,
+		Rows:          3000,
+		Bytes:         1_500_000,
+		AvgChunkBytes: 500_000,
+		MaxChunkRows:  1000,
+		MaxChunkBytes: 600_000,
+		WriteMillis:   1500,
+		RowsPerSecond: 2000,
+		MaxHeapBytes:  64 << 20,
+	}
+	if out.ChunkStats == nil || *out.ChunkStats != *want {
+		t.Fatalf("expected chunk stats %+v, got %+v", want, out.ChunkStats)
+	}
+}
+
+func TestGetImportJobErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		id   string
+		repo *fakeImportJobReader
+		want error
+	}{
+		{name: "invalid id", id: "job-1", repo: &fakeImportJobReader{}, want: app.ErrInvalidImportJobID},
+		{name: "not found", id: "4955eb4d-c7f2-42f6-80ca-33838ce37c31", repo: &fakeImportJobReader{returnErr: domain.ErrImportJobNotFound}, want: app.ErrImportJobNotFound},
+		{name: "repository error", id: "4955eb4d-c7f2-42f6-80ca-33838ce37c31", repo: &fakeImportJobReader{returnErr: errors.New("db down")}, want: app.ErrGetImportJob},
+	}
+	for _, tc := range cases {
+		_, err := app.NewGetImportJob(tc.repo).Execute(context.Background(), app.GetImportJobInput{ID: tc.id})
+		if !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestListImportConflicts(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobReader{
+		job: &domain.ImportJob{ID: "4955eb4d-c7f2-42f6-80ca-33838ce37c31"},
+		conflicts: []domain.ImportConflict{{
+			RowIndex:    4,
+			ExternalID:  "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+			IDUserID:    "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+			EmailUserID: "d5987b5f-506d-4d84-934f-d5b5535a64e8",
+			Resolution:  domain.IdentityConflictMerge,
+		}},
+	}
+
+	out, err := app.NewListImportConflicts(repo).Execute(context.Background(), app.ListImportConflictsInput{
+		JobID: "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+		Limit: 5000,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotLimit != 1000 {
+		t.Fatalf("expected limit to be clamped to 1000, got %d", repo.gotLimit)
+	}
+	if len(out.Conflicts) != 1 || out.Conflicts[0].Resolution != "merge" || out.Conflicts[0].RowIndex != 4 {
+		t.Fatalf("unexpected conflicts: %+v", out.Conflicts)
+	}
+}
+
+func TestGetImportJobReportsCountMismatch(t *testing.T) {
+	t.Parallel()
+
+	expected := int64(12)
+	repo := &fakeImportJobReader{job: &domain.ImportJob{
+		ID:            "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+		Status:        "succeeded",
+		Progress:      domain.ImportProgress{ProcessedCount: 10},
+		Metadata:      map[string]any{"record_count": 12},
+		ExpectedCount: &expected,
+	}}
+
+	out, err := app.NewGetImportJob(repo).Execute(context.Background(), app.GetImportJobInput{ID: "4955eb4d-c7f2-42f6-80ca-33838ce37c31"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !out.CountMismatch || out.ExpectedCount == nil || *out.ExpectedCount != 12 || out.Metadata["record_count"] != 12 {
+		t.Fatalf("expected count mismatch to be reported, got %+v", out)
+	}
+}
+
+func TestGetImportJobListsPartitions(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobReader{
+		job: &domain.ImportJob{
+			ID:       "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+			Status:   domain.ImportJobStatusWaiting,
+			Progress: domain.ImportProgress{ProcessedCount: 10},
+			Options:  domain.ImportOptions{Format: domain.ImportFormatNDJSON, Partitions: 2},
+		},
+		partitions: []domain.ImportJob{
+			{
+				ID:        "26a700f4-6765-4dce-b1a7-3a18f2fd4f56",
+				ParentID:  "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+				Status:    domain.ImportJobStatusSucceeded,
+				Attempts:  1,
+				Progress:  domain.ImportProgress{ProcessedCount: 10},
+				Partition: &domain.ImportPartition{Index: 0, Start: 0, End: 512},
+			},
+			{
+				ID:        "5b0c7a0e-0a9e-4f0e-8d0b-6c1c1f5e2a01",
+				ParentID:  "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+				Status:    domain.ImportJobStatusQueued,
+				Partition: &domain.ImportPartition{Index: 1, Start: 512, End: 1024},
+			},
+		},
+	}
+
+	out, err := app.NewGetImportJob(repo).Execute(context.Background(), app.GetImportJobInput{ID: "4955eb4d-c7f2-42f6-80ca-33838ce37c31"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(out.Partitions) != 2 {
+		t.Fatalf("expected two partitions, got %+v", out.Partitions)
+	}
+	first, second := out.Partitions[0], out.Partitions[1]
+	if first.Status != domain.ImportJobStatusSucceeded || first.ProcessedCount != 10 || first.End != 512 {
+		t.Fatalf("unexpected first partition: %+v", first)
+	}
+	if second.Index != 1 || second.Start != 512 || second.End != 1024 || second.Status != domain.ImportJobStatusQueued {
+		t.Fatalf("unexpected second partition: %+v", second)
+	}
+}
@@ -4,13 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"regexp"
 
 	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 )
 
-var userIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-5][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`)
-
 type GetUserByIDInput struct {
 	ID string
 }
@@ -21,6 +18,9 @@ type GetUserAddressOutput struct {
 	State   string `json:"state"`
 	ZipCode string `json:"zip_code"`
 	Country string `json:"country"`
+
+	Type      string `json:"type"`
+	IsDefault bool   `json:"is_default"`
 }
 
 type GetUserByIDOutput struct {
@@ -29,6 +29,7 @@ type GetUserByIDOutput struct {
 	Email       string                 `json:"email"`
 	PhoneNumber string                 `json:"phone_number"`
 	Addresses   []GetUserAddressOutput `json:"addresses"`
+	Attributes  map[string]any         `json:"attributes"`
 }
 
 type GetUserByID interface {
@@ -44,11 +45,12 @@ func NewGetUserByID(repo domain.UserQueryRepository) GetUserByID {
 }
 
 func (uc *getUserByID) Execute(ctx context.Context, in GetUserByIDInput) (GetUserByIDOutput, error) {
-	if !userIDPattern.MatchString(in.ID) {
+	userID, err := domain.ParseUUID(in.ID)
+	if err != nil {
 		return GetUserByIDOutput{}, ErrInvalidUserID
 	}
 
-	userAggregate, err := uc.repo.GetByID(ctx, in.ID)
+	userAggregate, err := uc.repo.GetByID(ctx, userID)
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
 			return GetUserByIDOutput{}, ErrUserNotFound
@@ -56,6 +58,10 @@ func (uc *getUserByID) Execute(ctx context.Context, in GetUserByIDInput) (GetUse
 		return GetUserByIDOutput{}, fmt.Errorf("%w: %v", ErrGetUserByID, err)
 	}
 
+	return toUserOutput(*userAggregate), nil
+}
+
+func toUserOutput(userAggregate domain.User) GetUserByIDOutput {
 	addresses := make([]GetUserAddressOutput, 0, len(userAggregate.Addresses))
 	for _, address := range userAggregate.Addresses {
 		addresses = append(addresses, GetUserAddressOutput{
@@ -64,14 +70,23 @@ func (uc *getUserByID) Execute(ctx context.Context, in GetUserByIDInput) (GetUse
 			State:   address.State,
 			ZipCode: address.ZipCode,
 			Country: address.Country,
+
+			Type:      string(address.Type),
+			IsDefault: address.IsDefault,
 		})
 	}
 
+	attributes := userAggregate.Attributes
+	if attributes == nil {
+		attributes = map[string]any{}
+	}
+
 	return GetUserByIDOutput{
 		ID:          userAggregate.ID,
 		Name:        userAggregate.Name,
 		Email:       userAggregate.Email,
 		PhoneNumber: userAggregate.PhoneNumber,
 		Addresses:   addresses,
-	}, nil
+		Attributes:  attributes,
+	}
 }
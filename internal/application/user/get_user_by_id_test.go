@@ -11,10 +11,22 @@ import (
 
 type fakeUserQueryRepo struct {
 	user      *domain.User
+	users     []domain.User
 	returnErr error
+	gotID     string
+	gotFilter domain.UserFilter
+}
+
+func (f *fakeUserQueryRepo) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	f.gotFilter = filter
+	if f.returnErr != nil {
+		return nil, f.returnErr
+	}
+	return f.users, nil
 }
 
 func (f *fakeUserQueryRepo) GetByID(ctx context.Context, userID string) (*domain.User, error) {
+	f.gotID = userID
 	if f.returnErr != nil {
 		return nil, f.returnErr
 	}
@@ -35,6 +47,9 @@ func TestGetUserByIDSuccess(t *testing.T) {
 			State:   "TX",
 			ZipCode: "78701",
 			Country: "USA",
+
+			Type:      domain.AddressTypeBilling,
+			IsDefault: true,
 		}},
 	}}
 
@@ -50,6 +65,9 @@ func TestGetUserByIDSuccess(t *testing.T) {
 	if len(out.Addresses) != 1 {
 		t.Fatalf("expected 1 address, got %d", len(out.Addresses))
 	}
+	if out.Addresses[0].Type != "billing" || !out.Addresses[0].IsDefault {
+		t.Fatalf("unexpected address output: %+v", out.Addresses[0])
+	}
 }
 
 func TestGetUserByIDInvalidID(t *testing.T) {
@@ -66,6 +84,25 @@ func TestGetUserByIDInvalidID(t *testing.T) {
 	}
 }
 
+func TestGetUserByIDAcceptsRFC9562Versions(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeUserQueryRepo{user: &domain.User{ID: "0190f2a4-7c3b-7d2e-9f1a-2b3c4d5e6f70"}}
+	uc := app.NewGetUserByID(repo)
+
+	for _, id := range []string{
+		"0190F2A4-7C3B-7D2E-9F1A-2B3C4D5E6F70",
+		"1ef21d2f-1207-6660-8c4f-419efbd44d48",
+	} {
+		if _, err := uc.Execute(context.Background(), app.GetUserByIDInput{ID: id}); err != nil {
+			t.Fatalf("id %s: expected no error, got %v", id, err)
+		}
+	}
+	if repo.gotID != "1ef21d2f-1207-6660-8c4f-419efbd44d48" {
+		t.Fatalf("unexpected id passed to repository: %s", repo.gotID)
+	}
+}
+
 func TestGetUserByIDNotFound(t *testing.T) {
 	t.Parallel()
 
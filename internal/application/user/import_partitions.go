@@ -0,0 +1,140 @@
+package user
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+var errRangeSourceRequired = errors.New("import source does not support byte ranges")
+
+// ImportRangeSource is implemented by sources that can serve byte ranges of a
+// file, which partitioned imports need.
+type ImportRangeSource interface {
+	ImportSource
+	Size(ctx context.Context, sourcePath string) (int64, error)
+	OpenRange(ctx context.Context, sourcePath string, start, end int64) (io.ReadCloser, error)
+}
+
+func (w *ImportWorker) openSource(ctx context.Context, job domain.ImportJob) (io.ReadCloser, error) {
+	if job.Partition == nil {
+		return w.source.Open(ctx, job.SourcePath)
+	}
+
+	source, ok := w.source.(ImportRangeSource)
+	if !ok {
+		return nil, errRangeSourceRequired
+	}
+	return source.OpenRange(ctx, job.SourcePath, job.Partition.Start, job.Partition.End)
+}
+
+// partitionJob splits a line-delimited source into child jobs. It reports false
+// when the job should be imported as a whole instead.
+func (w *ImportWorker) partitionJob(ctx context.Context, job domain.ImportJob, options domain.ImportOptions) (bool, error) {
+	source, ok := w.source.(ImportRangeSource)
+	if !ok {
+		return false, nil
+	}
+
+	size, err := source.Size(ctx, job.SourcePath)
+	if err != nil {
+		return false, fmt.Errorf("stat import source: %w", err)
+	}
+	if options.Encoding == domain.ImportEncodingAuto {
+		if err := rejectUTF16Source(ctx, source, job.SourcePath, size); err != nil {
+			return false, err
+		}
+	}
+
+	partitions, err := planPartitions(ctx, source, job.SourcePath, size, options.Partitions)
+	if err != nil {
+		return false, err
+	}
+	if len(partitions) < 2 {
+		return false, nil
+	}
+
+	if err := w.repo.Partition(ctx, job.ID, partitions); err != nil {
+		return false, fmt.Errorf("enqueue partitions: %w", err)
+	}
+	return true, nil
+}
+
+func planPartitions(ctx context.Context, source ImportRangeSource, sourcePath string, size int64, count int) ([]domain.ImportPartition, error) {
+	boundaries := []int64{0}
+	for i := 1; i < count; i++ {
+		cut := size * int64(i) / int64(count)
+		if cut <= boundaries[len(boundaries)-1] {
+			continue
+		}
+
+		next, err := nextLineStart(ctx, source, sourcePath, cut, size)
+		if err != nil {
+			return nil, err
+		}
+		if next > boundaries[len(boundaries)-1] && next < size {
+			boundaries = append(boundaries, next)
+		}
+	}
+	boundaries = append(boundaries, size)
+
+	partitions := make([]domain.ImportPartition, 0, len(boundaries)-1)
+	for i := 0; i+1 < len(boundaries); i++ {
+		partitions = append(partitions, domain.ImportPartition{
+			Index: i,
+			Start: boundaries[i],
+			End:   boundaries[i+1],
+		})
+	}
+	return partitions, nil
+}
+
+// nextLineStart returns the offset of the first line that starts at or after cut.
+func nextLineStart(ctx context.Context, source ImportRangeSource, sourcePath string, cut, size int64) (int64, error) {
+	r, err := source.OpenRange(ctx, sourcePath, cut-1, size)
+	if err != nil {
+		return 0, fmt.Errorf("open import source range: %w", err)
+	}
+	defer r.Close()
+
+	src := bufio.NewReader(r)
+	offset := cut - 1
+	for {
+		b, err := src.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return 0, fmt.Errorf("find line boundary: %w", err)
+		}
+		offset++
+		if b == '\n' {
+			return offset, nil
+		}
+	}
+}
+
+func rejectUTF16Source(ctx context.Context, source ImportRangeSource, sourcePath string, size int64) error {
+	r, err := source.OpenRange(ctx, sourcePath, 0, min(size, 3))
+	if err != nil {
+		return fmt.Errorf("open import source range: %w", err)
+	}
+	defer r.Close()
+
+	head, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read byte order mark: %w", err)
+	}
+	detected, _ := detectByteOrderMark(head)
+	if detected == "" {
+		detected = sniffEncoding(head)
+	}
+	if detected == domain.ImportEncodingUTF16LE || detected == domain.ImportEncodingUTF16BE {
+		return fmt.Errorf("%s sources cannot be partitioned", detected)
+	}
+	return nil
+}
@@ -0,0 +1,292 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const maxBufferedRecords = 1024
+
+type importBatch struct {
+	seq      int
+	users    []domain.User
+	rows     []int64
+	locators []domain.RecordLocator
+	stats    domain.ImportSummary
+	result   ImportChunkResult
+
+	bytes         int64
+	writeDuration time.Duration
+}
+
+func (b *importBatch) fail(failure domain.ImportFailure) {
+	if len(b.stats.Failures) < maxStoredFailures {
+		b.stats.Failures = append(b.stats.Failures, failure)
+	}
+}
+
+func (b *importBatch) warn(warning domain.ImportWarning) {
+	b.stats.WarningCount++
+	if len(b.stats.Warnings) < maxStoredFailures {
+		b.stats.Warnings = append(b.stats.Warnings, warning)
+	}
+}
+
+func (b *importBatch) rowIndex(index int64) int64 {
+	if index >= 0 && index < int64(len(b.rows)) {
+		return b.rows[index]
+	}
+	return index
+}
+
+func (b *importBatch) locator(index int64) domain.RecordLocator {
+	if index >= 0 && index < int64(len(b.locators)) {
+		return b.locators[index]
+	}
+	return domain.RecordLocator{}
+}
+
+// importPipeline runs reading, validation and chunk writes concurrently. Stages
+// stop on the first error, which Err reports once every stage has exited.
+type importPipeline struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	stages sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+func newImportPipeline(ctx context.Context) *importPipeline {
+	runCtx, cancel := context.WithCancel(ctx)
+	return &importPipeline{ctx: runCtx, cancel: cancel}
+}
+
+func (p *importPipeline) Go(stage func() error) {
+	p.stages.Add(1)
+	go func() {
+		defer p.stages.Done()
+		if err := stage(); err != nil {
+			p.errOnce.Do(func() { p.err = err })
+			p.cancel()
+		}
+	}()
+}
+
+func (p *importPipeline) Stop() {
+	p.cancel()
+	p.stages.Wait()
+}
+
+func (p *importPipeline) Err() error {
+	p.stages.Wait()
+	return p.err
+}
+
+func (p *importPipeline) readRecords(reader RecordReader, partition *domain.ImportPartition, records chan<- Record) error {
+	defer close(records)
+
+	var rowIndex int64
+	for {
+		record, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read record at index %d: %w", rowIndex, err)
+		}
+		if partition != nil {
+			record.Locator.Offset += partition.Start
+		}
+
+		select {
+		case records <- record:
+		case <-p.ctx.Done():
+			return nil
+		}
+		rowIndex++
+	}
+}
+
+func (p *importPipeline) batchRecords(validator recordValidator, chunkSize int, records <-chan Record, batches chan<- *importBatch, slots chan struct{}) error {
+	defer close(batches)
+
+	dispatch := func(batch *importBatch) bool {
+		select {
+		case slots <- struct{}{}:
+		case <-p.ctx.Done():
+			return false
+		}
+		select {
+		case batches <- batch:
+			return true
+		case <-p.ctx.Done():
+			return false
+		}
+	}
+
+	batch := &importBatch{}
+	var rowIndex int64
+	for record := range records {
+		validator.apply(batch, record, rowIndex)
+		rowIndex++
+
+		if len(batch.users) >= chunkSize {
+			if !dispatch(batch) {
+				return nil
+			}
+			batch = &importBatch{seq: batch.seq + 1}
+		}
+	}
+
+	if p.ctx.Err() == nil {
+		dispatch(batch)
+	}
+	return nil
+}
+
+func (p *importPipeline) writeChunks(importer importChunker, jobID string, options domain.ImportOptions, batches <-chan *importBatch, results chan<- *importBatch) error {
+	for batch := range batches {
+		if len(batch.users) > 0 {
+			started := time.Now()
+			result, err := importer.ImportChunk(p.ctx, jobID, options, batch.users)
+			if err != nil {
+				return fmt.Errorf("flush chunk: %w", err)
+			}
+			batch.result = result
+			batch.writeDuration = time.Since(started)
+		}
+
+		select {
+		case results <- batch:
+		case <-p.ctx.Done():
+			return nil
+		}
+	}
+	return nil
+}
+
+type recordValidator struct {
+	mapper          domain.FieldMapper
+	rules           domain.RuleSet
+	options         domain.ImportOptions
+	emailNormalizer domain.EmailNormalizer
+	fieldLimits     domain.FieldLimits
+}
+
+func (v recordValidator) apply(batch *importBatch, record Record, rowIndex int64) {
+	stats := &batch.stats
+	var raw rawUser
+	err := record.Err
+	if err == nil {
+		raw, err = decodeRecord(record, v.mapper)
+	}
+	if err != nil {
+		stats.ProcessedCount++
+		stats.FailedCount++
+		stats.SkippedCount++
+		batch.fail(domain.ImportFailure{
+			RowIndex: rowIndex,
+			Locator:  record.Locator,
+			Reason:   recordErrorReason(err),
+			Field:    recordErrorField(err),
+		})
+		return
+	}
+
+	stats.ProcessedCount++
+
+	if unknownFields := raw.unknownFields(v.options.AttributeFields); len(unknownFields) > 0 {
+		if stats.UnknownFields == nil {
+			stats.UnknownFields = make(map[string]int64)
+		}
+		for _, field := range unknownFields {
+			stats.UnknownFields[field.Key]++
+		}
+
+		if v.options.SchemaMode == domain.SchemaModeStrict {
+			stats.FailedCount++
+			stats.SkippedCount++
+			for _, field := range unknownFields {
+				batch.fail(domain.ImportFailure{
+					RowIndex: rowIndex,
+					Locator:  record.Locator,
+					Reason:   domain.FailureReasonUnknownField,
+					Field:    field.Path,
+				})
+			}
+			return
+		}
+	}
+
+	userAggregate, issues, validationErr := raw.toDomain(domain.UserOptions{EmailNormalizer: v.emailNormalizer, PhoneRegion: v.options.PhoneRegion}, v.options.AddressValidation, v.options.AttributeFields)
+	if validationErr != nil {
+		stats.FailedCount++
+		stats.SkippedCount++
+		batch.fail(domain.ImportFailure{
+			RowIndex: rowIndex,
+			Locator:  record.Locator,
+			Reason:   failureReason(validationErr),
+		})
+		return
+	}
+
+	userAggregate, oversize, limitErr := v.fieldLimits.Enforce(userAggregate, v.options.OversizePolicy)
+	if limitErr != nil {
+		stats.FailedCount++
+		stats.SkippedCount++
+		for _, value := range oversize {
+			batch.fail(domain.ImportFailure{
+				RowIndex: rowIndex,
+				Locator:  record.Locator,
+				Reason:   domain.FailureReasonValueTooLong,
+				Field:    value.Field,
+			})
+		}
+		return
+	}
+
+	if violations := v.rules.Evaluate(userAggregate); len(violations) > 0 {
+		stats.FailedCount++
+		stats.SkippedCount++
+		for _, violation := range violations {
+			batch.fail(domain.ImportFailure{
+				RowIndex: rowIndex,
+				Locator:  record.Locator,
+				Reason:   domain.FailureReasonRuleViolation,
+				RuleID:   violation.RuleID,
+				Field:    violation.Field,
+			})
+		}
+		return
+	}
+
+	for _, issue := range issues {
+		batch.warn(domain.ImportWarning{
+			RowIndex: rowIndex,
+			Locator:  record.Locator,
+			Reason:   issue.Reason,
+			Field:    fmt.Sprintf("addresses[%d]", issue.AddressIndex),
+		})
+	}
+	for _, value := range oversize {
+		batch.warn(domain.ImportWarning{
+			RowIndex: rowIndex,
+			Locator:  record.Locator,
+			Reason:   domain.WarningReasonValueTruncated,
+			Field:    value.Field,
+		})
+	}
+
+	userAggregate.SourcePosition = record.Locator.Position(v.options.Format)
+	batch.users = append(batch.users, userAggregate)
+	batch.bytes += userFootprint(userAggregate)
+	batch.rows = append(batch.rows, rowIndex)
+	batch.locators = append(batch.locators, record.Locator)
+}
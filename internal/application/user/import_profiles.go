@@ -0,0 +1,479 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const (
+	defaultImportProfilePageSize = 100
+	maxImportProfilePageSize     = 1000
+)
+
+type RuleConditionInput struct {
+	Field  string
+	Equals []string
+}
+
+type ValidationRuleInput struct {
+	ID      string
+	Type    string
+	Field   string
+	Pattern string
+	Min     *int
+	Max     *int
+	Values  []string
+	When    *RuleConditionInput
+}
+
+type ImportProfileInput struct {
+	Name             string
+	Rules            []ValidationRuleInput
+	Format           string
+	Encoding         string
+	Sheet            string
+	XML              XMLMappingInput
+	FieldMapping     map[string]string
+	Transforms       []FieldTransformInput
+	RecordsPointer   string
+	Metadata         map[string]string
+	ChunkSize        int
+	ChunkConcurrency int
+	Partitions       int
+	MaxAttempts      int
+	AddressStrategy  string
+	UpdatePolicies   FieldUpdatePoliciesInput
+	Source           string
+	ConflictPolicy   string
+
+	AddressValidation AddressValidationInput
+	OversizePolicy    string
+	PhoneRegion       string
+	AttributeStrategy string
+	AttributeFields   []string
+	SchemaMode        string
+}
+
+type GetImportProfileInput struct {
+	Name string
+}
+
+type ListImportProfilesInput struct {
+	Limit  int
+	Offset int
+}
+
+type DeleteImportProfileInput struct {
+	Name string
+}
+
+type RuleConditionOutput struct {
+	Field  string   `json:"field"`
+	Equals []string `json:"equals"`
+}
+
+type ValidationRuleOutput struct {
+	ID      string               `json:"id"`
+	Type    string               `json:"type"`
+	Field   string               `json:"field"`
+	Pattern string               `json:"pattern,omitempty"`
+	Min     *int                 `json:"min,omitempty"`
+	Max     *int                 `json:"max,omitempty"`
+	Values  []string             `json:"values,omitempty"`
+	When    *RuleConditionOutput `json:"when,omitempty"`
+}
+
+type FieldTransformOutput struct {
+	Target    string            `json:"target"`
+	Source    string            `json:"source,omitempty"`
+	Sources   []string          `json:"sources,omitempty"`
+	Separator string            `json:"separator,omitempty"`
+	Split     string            `json:"split,omitempty"`
+	Index     int               `json:"index,omitempty"`
+	Trim      bool              `json:"trim,omitempty"`
+	Case      string            `json:"case,omitempty"`
+	Lookup    map[string]string `json:"lookup,omitempty"`
+	Default   *string           `json:"default,omitempty"`
+}
+
+type FieldUpdatePoliciesOutput struct {
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+type AddressValidationOutput struct {
+	Country     string `json:"country"`
+	PostalCode  string `json:"postal_code"`
+	Subdivision string `json:"subdivision"`
+	Normalize   bool   `json:"normalize"`
+}
+
+type XMLMappingOutput struct {
+	RecordPath    string            `json:"record_path,omitempty"`
+	Fields        map[string]string `json:"fields,omitempty"`
+	AddressPath   string            `json:"address_path,omitempty"`
+	AddressFields map[string]string `json:"address_fields,omitempty"`
+	Namespaces    map[string]string `json:"namespaces,omitempty"`
+}
+
+type ImportProfileOutput struct {
+	ID                string                    `json:"id"`
+	Name              string                    `json:"name"`
+	Rules             []ValidationRuleOutput    `json:"rules"`
+	Format            string                    `json:"format"`
+	Encoding          string                    `json:"encoding"`
+	Sheet             string                    `json:"sheet,omitempty"`
+	XML               *XMLMappingOutput         `json:"xml,omitempty"`
+	FieldMapping      map[string]string         `json:"field_mapping,omitempty"`
+	Transforms        []FieldTransformOutput    `json:"field_transforms,omitempty"`
+	RecordsPointer    string                    `json:"records_pointer,omitempty"`
+	Metadata          map[string]string         `json:"metadata,omitempty"`
+	ChunkSize         int                       `json:"chunk_size,omitempty"`
+	ChunkConcurrency  int                       `json:"chunk_concurrency,omitempty"`
+	Partitions        int                       `json:"partitions,omitempty"`
+	MaxAttempts       int                       `json:"max_attempts,omitempty"`
+	AddressStrategy   string                    `json:"address_strategy"`
+	UpdatePolicies    FieldUpdatePoliciesOutput `json:"update_policies"`
+	Source            string                    `json:"source,omitempty"`
+	ConflictPolicy    string                    `json:"conflict_policy"`
+	AddressValidation AddressValidationOutput   `json:"address_validation"`
+	OversizePolicy    string                    `json:"oversize_policy"`
+	PhoneRegion       string                    `json:"phone_region,omitempty"`
+	AttributeStrategy string                    `json:"attribute_strategy"`
+	AttributeFields   []string                  `json:"attribute_fields,omitempty"`
+	SchemaMode        string                    `json:"schema_mode"`
+	CreatedAt         time.Time                 `json:"created_at"`
+	UpdatedAt         time.Time                 `json:"updated_at"`
+}
+
+type ListImportProfilesOutput struct {
+	Profiles []ImportProfileOutput `json:"profiles"`
+	Limit    int                   `json:"limit"`
+	Offset   int                   `json:"offset"`
+}
+
+type CreateImportProfile interface {
+	Execute(ctx context.Context, in ImportProfileInput) (ImportProfileOutput, error)
+}
+
+type GetImportProfile interface {
+	Execute(ctx context.Context, in GetImportProfileInput) (ImportProfileOutput, error)
+}
+
+type ListImportProfiles interface {
+	Execute(ctx context.Context, in ListImportProfilesInput) (ListImportProfilesOutput, error)
+}
+
+type UpdateImportProfile interface {
+	Execute(ctx context.Context, in ImportProfileInput) (ImportProfileOutput, error)
+}
+
+type DeleteImportProfile interface {
+	Execute(ctx context.Context, in DeleteImportProfileInput) error
+}
+
+type importProfileStore interface {
+	Create(ctx context.Context, profile domain.ImportProfile) (*domain.ImportProfile, error)
+	GetByName(ctx context.Context, name string) (*domain.ImportProfile, error)
+	List(ctx context.Context, limit, offset int) ([]domain.ImportProfile, error)
+	Update(ctx context.Context, profile domain.ImportProfile) (*domain.ImportProfile, error)
+	Delete(ctx context.Context, name string) error
+}
+
+type createImportProfile struct {
+	repo importProfileStore
+}
+
+func NewCreateImportProfile(repo importProfileStore) CreateImportProfile {
+	return &createImportProfile{repo: repo}
+}
+
+func (uc *createImportProfile) Execute(ctx context.Context, in ImportProfileInput) (ImportProfileOutput, error) {
+	profile, err := buildImportProfile(in)
+	if err != nil {
+		return ImportProfileOutput{}, err
+	}
+
+	created, err := uc.repo.Create(ctx, profile)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportProfileExists) {
+			return ImportProfileOutput{}, ErrImportProfileExists
+		}
+		return ImportProfileOutput{}, fmt.Errorf("%w: %v", ErrSaveImportProfile, err)
+	}
+
+	return toImportProfileOutput(*created), nil
+}
+
+type getImportProfile struct {
+	repo importProfileStore
+}
+
+func NewGetImportProfile(repo importProfileStore) GetImportProfile {
+	return &getImportProfile{repo: repo}
+}
+
+func (uc *getImportProfile) Execute(ctx context.Context, in GetImportProfileInput) (ImportProfileOutput, error) {
+	name, err := parseImportProfileName(in.Name)
+	if err != nil {
+		return ImportProfileOutput{}, err
+	}
+
+	profile, err := uc.repo.GetByName(ctx, name)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportProfileNotFound) {
+			return ImportProfileOutput{}, ErrImportProfileNotFound
+		}
+		return ImportProfileOutput{}, fmt.Errorf("%w: %v", ErrGetImportProfile, err)
+	}
+
+	return toImportProfileOutput(*profile), nil
+}
+
+type listImportProfiles struct {
+	repo importProfileStore
+}
+
+func NewListImportProfiles(repo importProfileStore) ListImportProfiles {
+	return &listImportProfiles{repo: repo}
+}
+
+func (uc *listImportProfiles) Execute(ctx context.Context, in ListImportProfilesInput) (ListImportProfilesOutput, error) {
+	limit := in.Limit
+	if limit <= 0 {
+		limit = defaultImportProfilePageSize
+	}
+	if limit > maxImportProfilePageSize {
+		limit = maxImportProfilePageSize
+	}
+	offset := in.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	profiles, err := uc.repo.List(ctx, limit, offset)
+	if err != nil {
+		return ListImportProfilesOutput{}, fmt.Errorf("%w: %v", ErrGetImportProfile, err)
+	}
+
+	out := ListImportProfilesOutput{
+		Profiles: make([]ImportProfileOutput, 0, len(profiles)),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	for _, profile := range profiles {
+		out.Profiles = append(out.Profiles, toImportProfileOutput(profile))
+	}
+	return out, nil
+}
+
+type updateImportProfile struct {
+	repo importProfileStore
+}
+
+func NewUpdateImportProfile(repo importProfileStore) UpdateImportProfile {
+	return &updateImportProfile{repo: repo}
+}
+
+func (uc *updateImportProfile) Execute(ctx context.Context, in ImportProfileInput) (ImportProfileOutput, error) {
+	profile, err := buildImportProfile(in)
+	if err != nil {
+		return ImportProfileOutput{}, err
+	}
+
+	updated, err := uc.repo.Update(ctx, profile)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportProfileNotFound) {
+			return ImportProfileOutput{}, ErrImportProfileNotFound
+		}
+		return ImportProfileOutput{}, fmt.Errorf("%w: %v", ErrSaveImportProfile, err)
+	}
+
+	return toImportProfileOutput(*updated), nil
+}
+
+type deleteImportProfile struct {
+	repo importProfileStore
+}
+
+func NewDeleteImportProfile(repo importProfileStore) DeleteImportProfile {
+	return &deleteImportProfile{repo: repo}
+}
+
+func (uc *deleteImportProfile) Execute(ctx context.Context, in DeleteImportProfileInput) error {
+	name, err := parseImportProfileName(in.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, name); err != nil {
+		if errors.Is(err, domain.ErrImportProfileNotFound) {
+			return ErrImportProfileNotFound
+		}
+		return fmt.Errorf("%w: %v", ErrSaveImportProfile, err)
+	}
+	return nil
+}
+
+func parseImportProfileName(value string) (string, error) {
+	name, err := domain.ParseProfileName(value)
+	if err != nil {
+		return "", fmt.Errorf("%w: name: %v", ErrInvalidImportProfile, err)
+	}
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidImportProfile)
+	}
+	return name, nil
+}
+
+func buildImportProfile(in ImportProfileInput) (domain.ImportProfile, error) {
+	name, err := parseImportProfileName(in.Name)
+	if err != nil {
+		return domain.ImportProfile{}, err
+	}
+
+	options, err := buildImportOptions(StartImportUsersFromJSONInput{
+		AddressStrategy:   in.AddressStrategy,
+		UpdatePolicies:    in.UpdatePolicies,
+		Source:            in.Source,
+		ConflictPolicy:    in.ConflictPolicy,
+		AddressValidation: in.AddressValidation,
+		OversizePolicy:    in.OversizePolicy,
+		PhoneRegion:       in.PhoneRegion,
+		AttributeStrategy: in.AttributeStrategy,
+		AttributeFields:   in.AttributeFields,
+		SchemaMode:        in.SchemaMode,
+		Format:            in.Format,
+		Encoding:          in.Encoding,
+		Sheet:             in.Sheet,
+		XML:               in.XML,
+		FieldMapping:      in.FieldMapping,
+		Transforms:        in.Transforms,
+		RecordsPointer:    in.RecordsPointer,
+		Metadata:          in.Metadata,
+		ChunkSize:         in.ChunkSize,
+		ChunkConcurrency:  in.ChunkConcurrency,
+		Partitions:        in.Partitions,
+		MaxAttempts:       in.MaxAttempts,
+	})
+	if err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("%w: %v", ErrInvalidImportProfile, err)
+	}
+
+	rules := make([]domain.ValidationRule, 0, len(in.Rules))
+	for _, rule := range in.Rules {
+		var when *domain.RuleCondition
+		if rule.When != nil {
+			when = &domain.RuleCondition{Field: rule.When.Field, Equals: rule.When.Equals}
+		}
+		rules = append(rules, domain.ValidationRule{
+			ID:      rule.ID,
+			Type:    domain.ValidationRuleType(rule.Type),
+			Field:   rule.Field,
+			Pattern: rule.Pattern,
+			Min:     rule.Min,
+			Max:     rule.Max,
+			Values:  rule.Values,
+			When:    when,
+		})
+	}
+	if _, err := domain.NewRuleSet(rules); err != nil {
+		return domain.ImportProfile{}, fmt.Errorf("%w: rules: %v", ErrInvalidImportProfile, err)
+	}
+
+	return domain.ImportProfile{
+		Name:    name,
+		Options: options,
+		Rules:   rules,
+	}, nil
+}
+
+func toImportProfileOutput(profile domain.ImportProfile) ImportProfileOutput {
+	options := profile.Options.WithDefaults()
+
+	rules := make([]ValidationRuleOutput, 0, len(profile.Rules))
+	for _, rule := range profile.Rules {
+		var when *RuleConditionOutput
+		if rule.When != nil {
+			when = &RuleConditionOutput{Field: rule.When.Field, Equals: rule.When.Equals}
+		}
+		rules = append(rules, ValidationRuleOutput{
+			ID:      rule.ID,
+			Type:    string(rule.Type),
+			Field:   rule.Field,
+			Pattern: rule.Pattern,
+			Min:     rule.Min,
+			Max:     rule.Max,
+			Values:  rule.Values,
+			When:    when,
+		})
+	}
+
+	var transforms []FieldTransformOutput
+	for _, transform := range options.Transforms {
+		transforms = append(transforms, FieldTransformOutput{
+			Target:    transform.Target,
+			Source:    transform.Source,
+			Sources:   transform.Sources,
+			Separator: transform.Separator,
+			Split:     transform.Split,
+			Index:     transform.Index,
+			Trim:      transform.Trim,
+			Case:      string(transform.Case),
+			Lookup:    transform.Lookup,
+			Default:   transform.Default,
+		})
+	}
+
+	return ImportProfileOutput{
+		ID:               profile.ID,
+		Name:             profile.Name,
+		Rules:            rules,
+		Format:           string(options.Format),
+		Encoding:         string(options.Encoding),
+		Sheet:            options.Sheet,
+		XML:              toXMLMappingOutput(options.XML),
+		FieldMapping:     options.FieldMapping,
+		Transforms:       transforms,
+		RecordsPointer:   options.RecordsPointer,
+		Metadata:         options.Metadata,
+		ChunkSize:        options.ChunkSize,
+		ChunkConcurrency: options.ChunkConcurrency,
+		Partitions:       options.Partitions,
+		MaxAttempts:      options.MaxAttempts,
+		AddressStrategy:  string(options.AddressStrategy),
+		UpdatePolicies: FieldUpdatePoliciesOutput{
+			Name:        string(options.UpdatePolicies.Name),
+			Email:       string(options.UpdatePolicies.Email),
+			PhoneNumber: string(options.UpdatePolicies.PhoneNumber),
+		},
+		Source:         options.Source,
+		ConflictPolicy: string(options.ConflictPolicy),
+		AddressValidation: AddressValidationOutput{
+			Country:     string(options.AddressValidation.Country),
+			PostalCode:  string(options.AddressValidation.PostalCode),
+			Subdivision: string(options.AddressValidation.Subdivision),
+			Normalize:   options.AddressValidation.Normalize,
+		},
+		OversizePolicy:    string(options.OversizePolicy),
+		PhoneRegion:       options.PhoneRegion,
+		AttributeStrategy: string(options.AttributeStrategy),
+		AttributeFields:   options.AttributeFields,
+		SchemaMode:        string(options.SchemaMode),
+		CreatedAt:         profile.CreatedAt,
+		UpdatedAt:         profile.UpdatedAt,
+	}
+}
+
+func toXMLMappingOutput(mapping domain.XMLMapping) *XMLMappingOutput {
+	if mapping.Empty() {
+		return nil
+	}
+	out := XMLMappingOutput(mapping)
+	return &out
+}
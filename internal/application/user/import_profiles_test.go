@@ -0,0 +1,188 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+type fakeImportProfileStore struct {
+	profiles  map[string]domain.ImportProfile
+	returnErr error
+	gotLimit  int
+	gotOffset int
+}
+
+func (f *fakeImportProfileStore) Create(ctx context.Context, profile domain.ImportProfile) (*domain.ImportProfile, error) {
+	if f.returnErr != nil {
+		return nil, f.returnErr
+	}
+	if _, exists := f.profiles[profile.Name]; exists {
+		return nil, domain.ErrImportProfileExists
+	}
+	if f.profiles == nil {
+		f.profiles = make(map[string]domain.ImportProfile)
+	}
+	f.profiles[profile.Name] = profile
+	return &profile, nil
+}
+
+func (f *fakeImportProfileStore) GetByName(ctx context.Context, name string) (*domain.ImportProfile, error) {
+	if f.returnErr != nil {
+		return nil, f.returnErr
+	}
+	profile, ok := f.profiles[name]
+	if !ok {
+		return nil, domain.ErrImportProfileNotFound
+	}
+	return &profile, nil
+}
+
+func (f *fakeImportProfileStore) List(ctx context.Context, limit, offset int) ([]domain.ImportProfile, error) {
+	f.gotLimit = limit
+	f.gotOffset = offset
+	if f.returnErr != nil {
+		return nil, f.returnErr
+	}
+	profiles := make([]domain.ImportProfile, 0, len(f.profiles))
+	for _, profile := range f.profiles {
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+func (f *fakeImportProfileStore) Update(ctx context.Context, profile domain.ImportProfile) (*domain.ImportProfile, error) {
+	if f.returnErr != nil {
+		return nil, f.returnErr
+	}
+	if _, ok := f.profiles[profile.Name]; !ok {
+		return nil, domain.ErrImportProfileNotFound
+	}
+	f.profiles[profile.Name] = profile
+	return &profile, nil
+}
+
+func (f *fakeImportProfileStore) Delete(ctx context.Context, name string) error {
+	if f.returnErr != nil {
+		return f.returnErr
+	}
+	if _, ok := f.profiles[name]; !ok {
+		return domain.ErrImportProfileNotFound
+	}
+	delete(f.profiles, name)
+	return nil
+}
+
+func TestCreateImportProfile(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeImportProfileStore{}
+	uc := app.NewCreateImportProfile(store)
+
+	out, err := uc.Execute(context.Background(), app.ImportProfileInput{
+		Name:            " CRM ",
+		AddressStrategy: "merge",
+		ChunkSize:       500,
+		MaxAttempts:     2,
+		FieldMapping:    map[string]string{"fullName": "name"},
+		Rules: []app.ValidationRuleInput{
+			{ID: "phone-required", Type: "required", Field: "phone_number"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.Name != "crm" || out.AddressStrategy != "merge" || out.Format != "json" || out.ChunkSize != 500 || out.MaxAttempts != 2 {
+		t.Fatalf("unexpected output: %+v", out)
+	}
+	if len(out.Rules) != 1 || out.Rules[0].ID != "phone-required" {
+		t.Fatalf("unexpected rules: %+v", out.Rules)
+	}
+	stored := store.profiles["crm"]
+	if stored.Options.AddressStrategy != domain.AddressStrategyMerge || stored.Options.FieldMapping["fullName"] != "name" {
+		t.Fatalf("unexpected stored profile: %+v", stored)
+	}
+
+	_, err = uc.Execute(context.Background(), app.ImportProfileInput{Name: "crm"})
+	if !errors.Is(err, app.ErrImportProfileExists) {
+		t.Fatalf("expected ErrImportProfileExists, got %v", err)
+	}
+}
+
+func TestCreateImportProfileInvalid(t *testing.T) {
+	t.Parallel()
+
+	uc := app.NewCreateImportProfile(&fakeImportProfileStore{})
+
+	cases := []app.ImportProfileInput{
+		{},
+		{Name: "bad name!"},
+		{Name: "crm", ChunkSize: -1},
+		{Name: "crm", Format: "csv"},
+		{Name: "crm", Rules: []app.ValidationRuleInput{{ID: "r1", Type: "regex", Field: "name", Pattern: "("}}},
+	}
+	for _, in := range cases {
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, app.ErrInvalidImportProfile) {
+			t.Fatalf("input %+v: expected ErrInvalidImportProfile, got %v", in, err)
+		}
+	}
+}
+
+func TestImportProfileLifecycle(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeImportProfileStore{profiles: map[string]domain.ImportProfile{"crm": {Name: "crm"}}}
+
+	out, err := app.NewUpdateImportProfile(store).Execute(context.Background(), app.ImportProfileInput{Name: "crm", SchemaMode: "strict"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if out.SchemaMode != "strict" {
+		t.Fatalf("expected strict schema mode, got %q", out.SchemaMode)
+	}
+
+	got, err := app.NewGetImportProfile(store).Execute(context.Background(), app.GetImportProfileInput{Name: "crm"})
+	if err != nil || got.SchemaMode != "strict" {
+		t.Fatalf("unexpected get result: %+v, %v", got, err)
+	}
+
+	list, err := app.NewListImportProfiles(store).Execute(context.Background(), app.ListImportProfilesInput{Limit: 5000, Offset: -1})
+	if err != nil || len(list.Profiles) != 1 {
+		t.Fatalf("unexpected list result: %+v, %v", list, err)
+	}
+	if store.gotLimit != 1000 || store.gotOffset != 0 {
+		t.Fatalf("expected clamped paging, got limit=%d offset=%d", store.gotLimit, store.gotOffset)
+	}
+
+	if err := app.NewDeleteImportProfile(store).Execute(context.Background(), app.DeleteImportProfileInput{Name: "crm"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := app.NewDeleteImportProfile(store).Execute(context.Background(), app.DeleteImportProfileInput{Name: "crm"}); !errors.Is(err, app.ErrImportProfileNotFound) {
+		t.Fatalf("expected ErrImportProfileNotFound, got %v", err)
+	}
+	if _, err := app.NewGetImportProfile(store).Execute(context.Background(), app.GetImportProfileInput{Name: "crm"}); !errors.Is(err, app.ErrImportProfileNotFound) {
+		t.Fatalf("expected ErrImportProfileNotFound, got %v", err)
+	}
+	if _, err := app.NewUpdateImportProfile(store).Execute(context.Background(), app.ImportProfileInput{Name: "crm"}); !errors.Is(err, app.ErrImportProfileNotFound) {
+		t.Fatalf("expected ErrImportProfileNotFound, got %v", err)
+	}
+}
+
+func TestImportProfileRepositoryErrors(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeImportProfileStore{returnErr: errors.New("db down")}
+
+	if _, err := app.NewCreateImportProfile(store).Execute(context.Background(), app.ImportProfileInput{Name: "crm"}); !errors.Is(err, app.ErrSaveImportProfile) {
+		t.Fatalf("expected ErrSaveImportProfile, got %v", err)
+	}
+	if _, err := app.NewGetImportProfile(store).Execute(context.Background(), app.GetImportProfileInput{Name: "crm"}); !errors.Is(err, app.ErrGetImportProfile) {
+		t.Fatalf("expected ErrGetImportProfile, got %v", err)
+	}
+	if _, err := app.NewListImportProfiles(store).Execute(context.Background(), app.ListImportProfilesInput{}); !errors.Is(err, app.ErrGetImportProfile) {
+		t.Fatalf("expected ErrGetImportProfile, got %v", err)
+	}
+}
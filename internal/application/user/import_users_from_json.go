@@ -2,13 +2,76 @@ package user
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 )
 
+type FieldUpdatePoliciesInput struct {
+	Name        string
+	Email       string
+	PhoneNumber string
+}
+
+type AddressValidationInput struct {
+	Country     string
+	PostalCode  string
+	Subdivision string
+	Normalize   *bool
+}
+
+type FieldTransformInput struct {
+	Target    string
+	Source    string
+	Sources   []string
+	Separator string
+	Split     string
+	Index     int
+	Trim      bool
+	Case      string
+	Lookup    map[string]string
+	Default   *string
+}
+
 type StartImportUsersFromJSONInput struct {
-	SourcePath string
+	SourcePath      string
+	AddressStrategy string
+	UpdatePolicies  FieldUpdatePoliciesInput
+	Source          string
+	ConflictPolicy  string
+	Profile         string
+
+	AddressValidation AddressValidationInput
+	OversizePolicy    string
+	PhoneRegion       string
+	AttributeStrategy string
+	AttributeFields   []string
+	SchemaMode        string
+
+	Format           string
+	Encoding         string
+	Sheet            string
+	XML              XMLMappingInput
+	FieldMapping     map[string]string
+	Transforms       []FieldTransformInput
+	ChunkSize        int
+	ChunkConcurrency int
+	Partitions       int
+	MaxAttempts      int
+
+	RecordsPointer string
+	Metadata       map[string]string
+}
+
+type XMLMappingInput struct {
+	RecordPath    string
+	Fields        map[string]string
+	AddressPath   string
+	AddressFields map[string]string
+	Namespaces    map[string]string
 }
 
 type StartImportUsersFromJSONOutput struct {
@@ -21,24 +84,58 @@ type StartImportUsersFromJSON interface {
 }
 
 type importJobEnqueuer interface {
-	Enqueue(ctx context.Context, sourcePath string) (string, error)
+	Enqueue(ctx context.Context, sourcePath string, options domain.ImportOptions) (string, error)
+}
+
+type importProfileReader interface {
+	GetByName(ctx context.Context, name string) (*domain.ImportProfile, error)
 }
 
 type startImportUsersFromJSON struct {
 	importJobRepo importJobEnqueuer
+	profiles      importProfileReader
 }
 
-func NewStartImportUsersFromJSON(importJobRepo importJobEnqueuer) StartImportUsersFromJSON {
-	return &startImportUsersFromJSON{importJobRepo: importJobRepo}
+func NewStartImportUsersFromJSON(importJobRepo importJobEnqueuer, profiles importProfileReader) StartImportUsersFromJSON {
+	return &startImportUsersFromJSON{importJobRepo: importJobRepo, profiles: profiles}
 }
 
 func (uc *startImportUsersFromJSON) Execute(ctx context.Context, in StartImportUsersFromJSONInput) (StartImportUsersFromJSONOutput, error) {
 	sourcePath := strings.TrimSpace(in.SourcePath)
-	if sourcePath == "" || strings.ToLower(filepath.Ext(sourcePath)) != ".json" {
+	if sourcePath == "" {
+		return StartImportUsersFromJSONOutput{}, ErrInvalidImportSource
+	}
+
+	profileName, err := domain.ParseProfileName(in.Profile)
+	if err != nil {
+		return StartImportUsersFromJSONOutput{}, fmt.Errorf("%w: profile: %v", ErrInvalidImportOptions, err)
+	}
+
+	var profile *domain.ImportProfile
+	if profileName != "" {
+		profile, err = uc.profiles.GetByName(ctx, profileName)
+		if err != nil {
+			if errors.Is(err, domain.ErrImportProfileNotFound) {
+				return StartImportUsersFromJSONOutput{}, fmt.Errorf("%w: profile: %v", ErrInvalidImportOptions, err)
+			}
+			return StartImportUsersFromJSONOutput{}, fmt.Errorf("%w: %v", ErrEnqueueImportJob, err)
+		}
+		in = withProfileDefaults(in, *profile)
+	}
+
+	options, err := buildImportOptions(in)
+	if err != nil {
+		return StartImportUsersFromJSONOutput{}, fmt.Errorf("%w: %v", ErrInvalidImportOptions, err)
+	}
+	if profile != nil {
+		options.Rules = profile.Rules
+	}
+
+	if strings.ToLower(filepath.Ext(sourcePath)) != options.WithDefaults().Format.Extension() {
 		return StartImportUsersFromJSONOutput{}, ErrInvalidImportSource
 	}
 
-	jobID, err := uc.importJobRepo.Enqueue(ctx, sourcePath)
+	jobID, err := uc.importJobRepo.Enqueue(ctx, sourcePath, options)
 	if err != nil {
 		return StartImportUsersFromJSONOutput{}, fmt.Errorf("%w: %v", ErrEnqueueImportJob, err)
 	}
@@ -48,3 +145,272 @@ func (uc *startImportUsersFromJSON) Execute(ctx context.Context, in StartImportU
 		Status: "queued",
 	}, nil
 }
+
+func buildImportOptions(in StartImportUsersFromJSONInput) (domain.ImportOptions, error) {
+	addressStrategy, err := domain.ParseAddressStrategy(in.AddressStrategy)
+	if err != nil {
+		return domain.ImportOptions{}, err
+	}
+
+	namePolicy, err := domain.ParseFieldUpdatePolicy(in.UpdatePolicies.Name)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("name: %w", err)
+	}
+	emailPolicy, err := domain.ParseFieldUpdatePolicy(in.UpdatePolicies.Email)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("email: %w", err)
+	}
+	phonePolicy, err := domain.ParseFieldUpdatePolicy(in.UpdatePolicies.PhoneNumber)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("phone_number: %w", err)
+	}
+
+	source, err := domain.ParseSourceName(in.Source)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("source: %w", err)
+	}
+
+	conflictPolicy, err := domain.ParseIdentityConflictPolicy(in.ConflictPolicy)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("conflict_policy: %w", err)
+	}
+
+	profile, err := domain.ParseProfileName(in.Profile)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("profile: %w", err)
+	}
+
+	countryRule, err := domain.ParseValidationSeverity(in.AddressValidation.Country)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("address_validation.country: %w", err)
+	}
+	postalCodeRule, err := domain.ParseValidationSeverity(in.AddressValidation.PostalCode)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("address_validation.postal_code: %w", err)
+	}
+	subdivisionRule, err := domain.ParseValidationSeverity(in.AddressValidation.Subdivision)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("address_validation.subdivision: %w", err)
+	}
+
+	oversizePolicy, err := domain.ParseOversizePolicy(in.OversizePolicy)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("oversize_policy: %w", err)
+	}
+	phoneRegion, err := domain.ParsePhoneRegion(in.PhoneRegion)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("phone_region: %w", err)
+	}
+
+	attributeStrategy, err := domain.ParseAttributeStrategy(in.AttributeStrategy)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("attribute_strategy: %w", err)
+	}
+	attributeFields, err := domain.ParseAttributeKeys(in.AttributeFields)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("attribute_fields: %w", err)
+	}
+
+	schemaMode, err := domain.ParseSchemaMode(in.SchemaMode)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("schema_mode: %w", err)
+	}
+
+	format, err := domain.ParseImportFormat(in.Format)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("format: %w", err)
+	}
+	encoding, err := domain.ParseImportEncoding(in.Encoding)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("encoding: %w", err)
+	}
+	sheet, err := domain.ParseSheetName(in.Sheet, format)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("sheet: %w", err)
+	}
+	xmlMapping, err := domain.ParseXMLMapping(domain.XMLMapping(in.XML), format)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("xml: %w", err)
+	}
+	fieldMapping, err := domain.ParseFieldMapping(in.FieldMapping)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("field_mapping: %w", err)
+	}
+	transforms, err := domain.ParseFieldTransforms(toDomainFieldTransforms(in.Transforms))
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("field_transforms: %w", err)
+	}
+	if _, err := domain.NewFieldMapper(fieldMapping, transforms); err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("field_transforms: %w", err)
+	}
+	recordsPointer, err := domain.ParseJSONPointer(in.RecordsPointer)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("records_pointer: %w", err)
+	}
+	if recordsPointer != "" && format != domain.ImportFormatJSON {
+		return domain.ImportOptions{}, fmt.Errorf("records_pointer: %w", domain.ErrInvalidJSONPointer)
+	}
+	metadata, err := domain.ParseMetadataPointers(in.Metadata, recordsPointer)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("metadata: %w", err)
+	}
+	chunkSize, err := domain.ParseChunkSize(in.ChunkSize)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("chunk_size: %w", err)
+	}
+	chunkConcurrency, err := domain.ParseChunkConcurrency(in.ChunkConcurrency)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("chunk_concurrency: %w", err)
+	}
+	partitions, err := domain.ParsePartitions(in.Partitions, format, encoding)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("partitions: %w", err)
+	}
+	maxAttempts, err := domain.ParseMaxAttempts(in.MaxAttempts)
+	if err != nil {
+		return domain.ImportOptions{}, fmt.Errorf("max_attempts: %w", err)
+	}
+
+	return domain.ImportOptions{
+		Source:          source,
+		ConflictPolicy:  conflictPolicy,
+		Profile:         profile,
+		AddressStrategy: addressStrategy,
+		UpdatePolicies: domain.FieldUpdatePolicies{
+			Name:        namePolicy,
+			Email:       emailPolicy,
+			PhoneNumber: phonePolicy,
+		},
+		AddressValidation: domain.AddressValidationRules{
+			Country:     countryRule,
+			PostalCode:  postalCodeRule,
+			Subdivision: subdivisionRule,
+			Normalize:   in.AddressValidation.Normalize != nil && *in.AddressValidation.Normalize,
+		},
+		OversizePolicy:    oversizePolicy,
+		PhoneRegion:       phoneRegion,
+		AttributeStrategy: attributeStrategy,
+		AttributeFields:   attributeFields,
+		SchemaMode:        schemaMode,
+		Format:            format,
+		Encoding:          encoding,
+		Sheet:             sheet,
+		XML:               xmlMapping,
+		FieldMapping:      fieldMapping,
+		Transforms:        transforms,
+		ChunkSize:         chunkSize,
+		ChunkConcurrency:  chunkConcurrency,
+		Partitions:        partitions,
+		MaxAttempts:       maxAttempts,
+		RecordsPointer:    recordsPointer,
+		Metadata:          metadata,
+	}, nil
+}
+
+func withProfileDefaults(in StartImportUsersFromJSONInput, profile domain.ImportProfile) StartImportUsersFromJSONInput {
+	options := profile.Options
+	in.Profile = profile.Name
+	in.AddressStrategy = firstNonEmpty(in.AddressStrategy, string(options.AddressStrategy))
+	in.UpdatePolicies.Name = firstNonEmpty(in.UpdatePolicies.Name, string(options.UpdatePolicies.Name))
+	in.UpdatePolicies.Email = firstNonEmpty(in.UpdatePolicies.Email, string(options.UpdatePolicies.Email))
+	in.UpdatePolicies.PhoneNumber = firstNonEmpty(in.UpdatePolicies.PhoneNumber, string(options.UpdatePolicies.PhoneNumber))
+	in.Source = firstNonEmpty(in.Source, options.Source)
+	in.ConflictPolicy = firstNonEmpty(in.ConflictPolicy, string(options.ConflictPolicy))
+	in.AddressValidation.Country = firstNonEmpty(in.AddressValidation.Country, string(options.AddressValidation.Country))
+	in.AddressValidation.PostalCode = firstNonEmpty(in.AddressValidation.PostalCode, string(options.AddressValidation.PostalCode))
+	in.AddressValidation.Subdivision = firstNonEmpty(in.AddressValidation.Subdivision, string(options.AddressValidation.Subdivision))
+	if in.AddressValidation.Normalize == nil {
+		in.AddressValidation.Normalize = &options.AddressValidation.Normalize
+	}
+	in.OversizePolicy = firstNonEmpty(in.OversizePolicy, string(options.OversizePolicy))
+	in.PhoneRegion = firstNonEmpty(in.PhoneRegion, options.PhoneRegion)
+	in.AttributeStrategy = firstNonEmpty(in.AttributeStrategy, string(options.AttributeStrategy))
+	if in.AttributeFields == nil {
+		in.AttributeFields = options.AttributeFields
+	}
+	in.SchemaMode = firstNonEmpty(in.SchemaMode, string(options.SchemaMode))
+	in.Format = firstNonEmpty(in.Format, string(options.Format))
+	in.Encoding = firstNonEmpty(in.Encoding, string(options.Encoding))
+	in.Sheet = firstNonEmpty(in.Sheet, options.Sheet)
+	if domain.XMLMapping(in.XML).Empty() {
+		in.XML = XMLMappingInput(options.XML)
+	}
+	if in.FieldMapping == nil {
+		in.FieldMapping = options.FieldMapping
+	}
+	if in.Transforms == nil {
+		in.Transforms = toFieldTransformInputs(options.Transforms)
+	}
+	if in.ChunkSize == 0 {
+		in.ChunkSize = options.ChunkSize
+	}
+	if in.ChunkConcurrency == 0 {
+		in.ChunkConcurrency = options.ChunkConcurrency
+	}
+	if in.Partitions == 0 {
+		in.Partitions = options.Partitions
+	}
+	if in.MaxAttempts == 0 {
+		in.MaxAttempts = options.MaxAttempts
+	}
+	in.RecordsPointer = firstNonEmpty(in.RecordsPointer, options.RecordsPointer)
+	if in.Metadata == nil {
+		in.Metadata = options.Metadata
+	}
+	return in
+}
+
+func toDomainFieldTransforms(transforms []FieldTransformInput) []domain.FieldTransform {
+	if len(transforms) == 0 {
+		return nil
+	}
+
+	out := make([]domain.FieldTransform, 0, len(transforms))
+	for _, transform := range transforms {
+		out = append(out, domain.FieldTransform{
+			Target:    transform.Target,
+			Source:    transform.Source,
+			Sources:   transform.Sources,
+			Separator: transform.Separator,
+			Split:     transform.Split,
+			Index:     transform.Index,
+			Trim:      transform.Trim,
+			Case:      domain.FieldCase(transform.Case),
+			Lookup:    transform.Lookup,
+			Default:   transform.Default,
+		})
+	}
+	return out
+}
+
+func toFieldTransformInputs(transforms []domain.FieldTransform) []FieldTransformInput {
+	if len(transforms) == 0 {
+		return nil
+	}
+
+	out := make([]FieldTransformInput, 0, len(transforms))
+	for _, transform := range transforms {
+		out = append(out, FieldTransformInput{
+			Target:    transform.Target,
+			Source:    transform.Source,
+			Sources:   transform.Sources,
+			Separator: transform.Separator,
+			Split:     transform.Split,
+			Index:     transform.Index,
+			Trim:      transform.Trim,
+			Case:      string(transform.Case),
+			Lookup:    transform.Lookup,
+			Default:   transform.Default,
+		})
+	}
+	return out
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
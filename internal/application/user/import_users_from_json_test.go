@@ -6,18 +6,21 @@ import (
 	"testing"
 
 	app "github.com/mohammadpnp/user-import/internal/application/user"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 )
 
 type fakeImportJobRepository struct {
-	jobID     string
-	called    bool
-	gotPath   string
-	returnErr error
+	jobID      string
+	called     bool
+	gotPath    string
+	gotOptions domain.ImportOptions
+	returnErr  error
 }
 
-func (f *fakeImportJobRepository) Enqueue(ctx context.Context, sourcePath string) (string, error) {
+func (f *fakeImportJobRepository) Enqueue(ctx context.Context, sourcePath string, options domain.ImportOptions) (string, error) {
 	f.called = true
 	f.gotPath = sourcePath
+	f.gotOptions = options
 	if f.returnErr != nil {
 		return "", f.returnErr
 	}
@@ -28,7 +31,7 @@ func TestStartImportUsersFromJSONSuccess(t *testing.T) {
 	t.Parallel()
 
 	repo := &fakeImportJobRepository{jobID: "job-1"}
-	uc := app.NewStartImportUsersFromJSON(repo)
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
 
 	out, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
 		SourcePath: "users_data.json",
@@ -48,12 +51,51 @@ func TestStartImportUsersFromJSONSuccess(t *testing.T) {
 	if out.Status != "queued" {
 		t.Fatalf("unexpected status: %s", out.Status)
 	}
+	if repo.gotOptions.AddressStrategy != domain.AddressStrategyReplace {
+		t.Fatalf("expected default address strategy, got %q", repo.gotOptions.AddressStrategy)
+	}
+}
+
+func TestStartImportUsersFromJSONAddressStrategy(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:      "users_data.json",
+		AddressStrategy: "merge",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.AddressStrategy != domain.AddressStrategyMerge {
+		t.Fatalf("unexpected address strategy: %q", repo.gotOptions.AddressStrategy)
+	}
+}
+
+func TestStartImportUsersFromJSONInvalidAddressStrategy(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:      "users_data.json",
+		AddressStrategy: "overwrite",
+	})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+	if repo.called {
+		t.Fatal("did not expect repository to be called")
+	}
 }
 
 func TestStartImportUsersFromJSONInvalidPath(t *testing.T) {
 	t.Parallel()
 
-	uc := app.NewStartImportUsersFromJSON(&fakeImportJobRepository{})
+	uc := app.NewStartImportUsersFromJSON(&fakeImportJobRepository{}, nil)
 
 	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{})
 	if err == nil {
@@ -68,7 +110,7 @@ func TestStartImportUsersFromJSONRepositoryError(t *testing.T) {
 	t.Parallel()
 
 	repoErr := errors.New("db down")
-	uc := app.NewStartImportUsersFromJSON(&fakeImportJobRepository{returnErr: repoErr})
+	uc := app.NewStartImportUsersFromJSON(&fakeImportJobRepository{returnErr: repoErr}, nil)
 
 	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json"})
 	if err == nil {
@@ -78,3 +120,426 @@ func TestStartImportUsersFromJSONRepositoryError(t *testing.T) {
 		t.Fatalf("expected ErrEnqueueImportJob, got %v", err)
 	}
 }
+
+func TestStartImportUsersFromJSONUpdatePolicies(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath: "users_data.json",
+		UpdatePolicies: app.FieldUpdatePoliciesInput{
+			Name:        "fill_empty",
+			PhoneNumber: "if_present",
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := domain.FieldUpdatePolicies{
+		Name:        domain.FieldUpdateFillEmpty,
+		Email:       domain.FieldUpdateAlways,
+		PhoneNumber: domain.FieldUpdateIfPresent,
+	}
+	if repo.gotOptions.UpdatePolicies != want {
+		t.Fatalf("unexpected update policies: %+v", repo.gotOptions.UpdatePolicies)
+	}
+}
+
+func TestStartImportUsersFromJSONInvalidUpdatePolicy(t *testing.T) {
+	t.Parallel()
+
+	uc := app.NewStartImportUsersFromJSON(&fakeImportJobRepository{}, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:     "users_data.json",
+		UpdatePolicies: app.FieldUpdatePoliciesInput{Email: "sometimes"},
+	})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONSource(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath: "users_data.json",
+		Source:     "HR-System",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.Source != "hr-system" {
+		t.Fatalf("unexpected source: %q", repo.gotOptions.Source)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath: "users_data.json",
+		Source:     "hr system",
+	})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONAddressValidation(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	normalize := true
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:        "users_data.json",
+		AddressValidation: app.AddressValidationInput{Country: "Error", Subdivision: "warn", Normalize: &normalize},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := domain.AddressValidationRules{Country: domain.ValidationError, Subdivision: domain.ValidationWarn, Normalize: true}
+	if repo.gotOptions.AddressValidation != want {
+		t.Fatalf("unexpected address validation: %+v", repo.gotOptions.AddressValidation)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:        "users_data.json",
+		AddressValidation: app.AddressValidationInput{PostalCode: "strict"},
+	})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+type fakeImportProfileReader struct {
+	profiles map[string]domain.ImportProfile
+	err      error
+}
+
+func (f *fakeImportProfileReader) GetByName(ctx context.Context, name string) (*domain.ImportProfile, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	profile, ok := f.profiles[name]
+	if !ok {
+		return nil, domain.ErrImportProfileNotFound
+	}
+	return &profile, nil
+}
+
+func TestStartImportUsersFromJSONProfile(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	profiles := &fakeImportProfileReader{profiles: map[string]domain.ImportProfile{"crm": {Name: "crm"}}}
+	uc := app.NewStartImportUsersFromJSON(repo, profiles)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", Profile: "crm"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.Profile != "crm" {
+		t.Fatalf("expected profile crm, got %q", repo.gotOptions.Profile)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", Profile: "missing"})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions for unknown profile, got %v", err)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", Profile: "bad name!"})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions for invalid profile name, got %v", err)
+	}
+
+	profiles.err = errors.New("db down")
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", Profile: "crm"})
+	if !errors.Is(err, app.ErrEnqueueImportJob) {
+		t.Fatalf("expected ErrEnqueueImportJob, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONProfileSnapshot(t *testing.T) {
+	t.Parallel()
+
+	rules := []domain.ValidationRule{{ID: "phone-required", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldPhoneNumber}}
+	profiles := &fakeImportProfileReader{profiles: map[string]domain.ImportProfile{"crm": {
+		Name:  "crm",
+		Rules: rules,
+		Options: domain.ImportOptions{
+			AddressStrategy:  domain.AddressStrategyMerge,
+			ConflictPolicy:   domain.IdentityConflictMerge,
+			SchemaMode:       domain.SchemaModeStrict,
+			FieldMapping:     map[string]string{"fullName": "name"},
+			ChunkSize:        500,
+			ChunkConcurrency: 4,
+			MaxAttempts:      2,
+		},
+	}}}
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, profiles)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:      "users_data.json",
+		Profile:         "crm",
+		AddressStrategy: "append",
+		MaxAttempts:     4,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := repo.gotOptions
+	if got.AddressStrategy != domain.AddressStrategyAppend || got.MaxAttempts != 4 {
+		t.Fatalf("expected request overrides to win, got %+v", got)
+	}
+	if got.ConflictPolicy != domain.IdentityConflictMerge || got.SchemaMode != domain.SchemaModeStrict || got.ChunkSize != 500 || got.ChunkConcurrency != 4 {
+		t.Fatalf("expected profile defaults, got %+v", got)
+	}
+	if got.FieldMapping["fullName"] != "name" || len(got.Rules) != 1 || got.Rules[0].ID != "phone-required" {
+		t.Fatalf("expected profile mapping and rules to be snapshotted, got %+v", got)
+	}
+	if got.Format != domain.ImportFormatJSON {
+		t.Fatalf("expected json format, got %q", got.Format)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", Profile: "crm", ChunkSize: -5})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", ChunkConcurrency: domain.MaxImportChunkConcurrency + 1})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions for chunk_concurrency, got %v", err)
+	}
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.csv", Profile: "crm"})
+	if !errors.Is(err, app.ErrInvalidImportSource) {
+		t.Fatalf("expected ErrInvalidImportSource, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONOversizePolicy(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", OversizePolicy: "truncate"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.OversizePolicy != domain.OversizeTruncate {
+		t.Fatalf("expected truncate, got %q", repo.gotOptions.OversizePolicy)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", OversizePolicy: "drop"})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONPhoneRegion(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", PhoneRegion: "Germany"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.PhoneRegion != "DE" {
+		t.Fatalf("expected DE, got %q", repo.gotOptions.PhoneRegion)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", PhoneRegion: "Atlantis"})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONAttributes(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:        "users_data.json",
+		AttributeStrategy: "replace",
+		AttributeFields:   []string{"department", " cost_center "},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.AttributeStrategy != domain.AttributeStrategyReplace {
+		t.Fatalf("expected replace, got %q", repo.gotOptions.AttributeStrategy)
+	}
+	if len(repo.gotOptions.AttributeFields) != 2 || repo.gotOptions.AttributeFields[1] != "cost_center" {
+		t.Fatalf("unexpected attribute fields: %v", repo.gotOptions.AttributeFields)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", AttributeFields: []string{"cost center"}})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONSchemaMode(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", SchemaMode: "strict"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.SchemaMode != domain.SchemaModeStrict {
+		t.Fatalf("expected strict, got %q", repo.gotOptions.SchemaMode)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", SchemaMode: "loose"})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONFieldTransforms(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath: "users_data.json",
+		Transforms: []app.FieldTransformInput{{Target: "email", Source: "/contact/email", Case: "LOWER"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(repo.gotOptions.Transforms) != 1 || repo.gotOptions.Transforms[0].Case != domain.FieldCaseLower {
+		t.Fatalf("unexpected transforms: %+v", repo.gotOptions.Transforms)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:   "users_data.json",
+		FieldMapping: map[string]string{"mail": "email"},
+		Transforms:   []app.FieldTransformInput{{Target: "email", Source: "/contact/email"}},
+	})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONRecordsPointer(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath:     "users_data.json",
+		RecordsPointer: "/data/users",
+		Metadata:       map[string]string{"record_count": "/meta/total"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.RecordsPointer != "/data/users" || repo.gotOptions.Metadata["record_count"] != "/meta/total" {
+		t.Fatalf("unexpected options: %+v", repo.gotOptions)
+	}
+
+	invalid := []app.StartImportUsersFromJSONInput{
+		{SourcePath: "users_data.json", RecordsPointer: "data/users"},
+		{SourcePath: "users_data.json", Metadata: map[string]string{"record_count": "/meta/total"}},
+		{SourcePath: "users_data.json", RecordsPointer: "/data", Metadata: map[string]string{"users": "/data/users"}},
+	}
+	for _, in := range invalid {
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, app.ErrInvalidImportOptions) {
+			t.Fatalf("%+v: expected ErrInvalidImportOptions, got %v", in, err)
+		}
+	}
+}
+
+func TestStartImportUsersFromJSONEncoding(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", Encoding: "Latin1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.Encoding != domain.ImportEncodingLatin1 {
+		t.Fatalf("expected iso-8859-1, got %q", repo.gotOptions.Encoding)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users_data.json", Encoding: "ebcdic"})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
+
+func TestStartImportUsersFromJSONXLSX(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users.xlsx", Format: "xlsx", Sheet: "Users"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.Format != domain.ImportFormatXLSX || repo.gotOptions.Sheet != "Users" {
+		t.Fatalf("unexpected options: %+v", repo.gotOptions)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{SourcePath: "users.xlsx", Format: "json"})
+	if !errors.Is(err, app.ErrInvalidImportSource) {
+		t.Fatalf("expected ErrInvalidImportSource, got %v", err)
+	}
+
+	invalid := []app.StartImportUsersFromJSONInput{
+		{SourcePath: "users.json", Sheet: "Users"},
+		{SourcePath: "users.xlsx", Format: "xlsx", RecordsPointer: "/data"},
+	}
+	for _, in := range invalid {
+		if _, err := uc.Execute(context.Background(), in); !errors.Is(err, app.ErrInvalidImportOptions) {
+			t.Fatalf("%+v: expected ErrInvalidImportOptions, got %v", in, err)
+		}
+	}
+}
+
+func TestStartImportUsersFromJSONXMLMapping(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeImportJobRepository{jobID: "job-1"}
+	uc := app.NewStartImportUsersFromJSON(repo, nil)
+
+	_, err := uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath: "users.xml",
+		Format:     "xml",
+		XML:        app.XMLMappingInput{RecordPath: "/feed/user", Fields: map[string]string{"id": "@id"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotOptions.XML.RecordPath != "/feed/user" || repo.gotOptions.XML.Fields["id"] != "@id" {
+		t.Fatalf("unexpected options: %+v", repo.gotOptions.XML)
+	}
+
+	_, err = uc.Execute(context.Background(), app.StartImportUsersFromJSONInput{
+		SourcePath: "users.json",
+		XML:        app.XMLMappingInput{RecordPath: "/feed/user"},
+	})
+	if !errors.Is(err, app.ErrInvalidImportOptions) {
+		t.Fatalf("expected ErrInvalidImportOptions, got %v", err)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -23,7 +25,7 @@ type ImportSource interface {
 type ImportChunkResult = domain.ImportChunkResult
 
 type importChunker interface {
-	ImportChunk(ctx context.Context, jobID string, users []domain.User) (ImportChunkResult, error)
+	ImportChunk(ctx context.Context, jobID string, options domain.ImportOptions, users []domain.User) (ImportChunkResult, error)
 }
 
 type importWorkerJobRepo interface {
@@ -33,6 +35,8 @@ type importWorkerJobRepo interface {
 	Complete(ctx context.Context, jobID string, summary domain.ImportSummary) error
 	Requeue(ctx context.Context, jobID string, reason string) error
 	Fail(ctx context.Context, jobID string, reason string) error
+	RecordConflicts(ctx context.Context, jobID string, conflicts []domain.ImportConflict) error
+	Partition(ctx context.Context, jobID string, partitions []domain.ImportPartition) error
 }
 
 type ImportWorkerConfig struct {
@@ -41,6 +45,10 @@ type ImportWorkerConfig struct {
 	PollInterval      time.Duration
 	LeaseDuration     time.Duration
 	HeartbeatInterval time.Duration
+	EmailNormalizer   domain.EmailNormalizer
+	FieldLimits       domain.FieldLimits
+	RecordReaders     RecordReaders
+	ChunkConcurrency  int
 }
 
 type ImportWorker struct {
@@ -68,6 +76,12 @@ func NewImportWorker(repo importWorkerJobRepo, source ImportSource, importer imp
 	if cfg.HeartbeatInterval <= 0 {
 		cfg.HeartbeatInterval = cfg.LeaseDuration / 2
 	}
+	if cfg.ChunkConcurrency <= 0 {
+		cfg.ChunkConcurrency = 1
+	}
+	if cfg.RecordReaders == nil {
+		cfg.RecordReaders = DefaultRecordReaders()
+	}
 
 	return &ImportWorker{
 		repo:     repo,
@@ -116,60 +130,96 @@ func (w *ImportWorker) workerLoop(ctx context.Context) {
 }
 
 func (w *ImportWorker) ProcessJob(ctx context.Context, job domain.ImportJob) error {
-	reader, err := w.source.Open(ctx, job.SourcePath)
+	options := job.Options.WithDefaults()
+
+	rules, err := domain.NewRuleSet(options.Rules)
 	if err != nil {
-		return w.onProcessingError(ctx, job, fmt.Errorf("open import source: %w", err))
+		return w.onProcessingError(ctx, job, fmt.Errorf("compile import rules: %w", err))
+	}
+	mapper, err := domain.NewFieldMapper(options.FieldMapping, options.Transforms)
+	if err != nil {
+		return w.onProcessingError(ctx, job, fmt.Errorf("compile field mapping: %w", err))
 	}
-	defer reader.Close()
 
-	dec := json.NewDecoder(reader)
+	// Only line-delimited sources can be cut at record boundaries, and only their
+	// partitions rebase record offsets onto the whole source.
+	if job.Partition == nil && options.Partitions > 1 && options.Format.LineDelimited() {
+		partitioned, err := w.partitionJob(ctx, job, options)
+		if err != nil {
+			return w.onProcessingError(ctx, job, fmt.Errorf("partition import source: %w", err))
+		}
+		if partitioned {
+			return nil
+		}
+	}
 
-	token, err := dec.Token()
+	source, err := w.openSource(ctx, job)
 	if err != nil {
-		return w.onProcessingError(ctx, job, fmt.Errorf("read json start token: %w", err))
+		return w.onProcessingError(ctx, job, fmt.Errorf("open import source: %w", err))
 	}
+	defer source.Close()
 
-	delim, ok := token.(json.Delim)
-	if !ok || delim != '[' {
-		return w.onProcessingError(ctx, job, errors.New("import payload must be a JSON array"))
+	reader, err := w.cfg.RecordReaders.Open(source, options)
+	if err != nil {
+		return w.onProcessingError(ctx, job, err)
 	}
+	defer reader.Close()
+
+	chunkSize := w.cfg.ChunkSize
+	if options.ChunkSize > 0 {
+		chunkSize = options.ChunkSize
+	}
+	chunkConcurrency := w.cfg.ChunkConcurrency
+	if options.ChunkConcurrency > 0 {
+		chunkConcurrency = options.ChunkConcurrency
+	}
+	// The importer orders rows by source position once chunks can commit out of
+	// order, so it needs the effective value rather than the job's override.
+	options.ChunkConcurrency = chunkConcurrency
+
+	pipeline := newImportPipeline(ctx)
+	defer pipeline.Stop()
+
+	records := make(chan Record, min(chunkSize, maxBufferedRecords))
+	batches := make(chan *importBatch, chunkConcurrency)
+	results := make(chan *importBatch, chunkConcurrency)
+	// Each slot is a batch that has been dispatched but not yet committed, which
+	// bounds the results buffered behind a slow chunk.
+	slots := make(chan struct{}, 2*chunkConcurrency)
+
+	validator := recordValidator{
+		mapper:          mapper,
+		rules:           rules,
+		options:         options,
+		emailNormalizer: w.cfg.EmailNormalizer,
+		fieldLimits:     w.cfg.FieldLimits,
+	}
+	pipeline.Go(func() error {
+		return pipeline.readRecords(reader, job.Partition, records)
+	})
+	pipeline.Go(func() error {
+		return pipeline.batchRecords(validator, chunkSize, records, batches, slots)
+	})
+	var writers sync.WaitGroup
+	for range chunkConcurrency {
+		writers.Add(1)
+		pipeline.Go(func() error {
+			defer writers.Done()
+			return pipeline.writeChunks(w.importer, job.RootID(), options, batches, results)
+		})
+	}
+	go func() {
+		writers.Wait()
+		close(results)
+	}()
 
 	ticker := time.NewTicker(w.cfg.HeartbeatInterval)
 	defer ticker.Stop()
 
 	summary := domain.ImportSummary{}
-	chunk := make([]domain.User, 0, w.cfg.ChunkSize)
-
-	flush := func() error {
-		if len(chunk) == 0 {
-			return nil
-		}
-
-		result, importErr := w.importer.ImportChunk(ctx, job.ID, chunk)
-		if importErr != nil {
-			return importErr
-		}
-
-		summary.ImportedCount += result.ImportedCount
-		summary.UpdatedCount += result.UpdatedCount
-		summary.SkippedCount += result.SkippedCount
-
-		if err := w.repo.UpdateProgress(ctx, job.ID, domain.ImportProgress{
-			ProcessedCount: summary.ProcessedCount,
-			ImportedCount:  summary.ImportedCount,
-			UpdatedCount:   summary.UpdatedCount,
-			SkippedCount:   summary.SkippedCount,
-			FailedCount:    summary.FailedCount,
-		}); err != nil {
-			return err
-		}
-
-		chunk = chunk[:0]
-		return nil
-	}
-
-	var rowIndex int64
-	for dec.More() {
+	pending := make(map[int]*importBatch)
+	next := 0
+	for results != nil {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
@@ -177,66 +227,137 @@ func (w *ImportWorker) ProcessJob(ctx context.Context, job domain.ImportJob) err
 			if err := w.repo.Heartbeat(ctx, job.ID, w.cfg.LeaseDuration); err != nil {
 				return w.onProcessingError(ctx, job, fmt.Errorf("heartbeat: %w", err))
 			}
-		default:
+		case batch, ok := <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+			pending[batch.seq] = batch
+			for ready := pending[next]; ready != nil; ready = pending[next] {
+				delete(pending, next)
+				next++
+				if err := w.commitBatch(ctx, job.ID, &summary, ready); err != nil {
+					return w.onProcessingError(ctx, job, err)
+				}
+				<-slots
+			}
 		}
+	}
 
-		var raw rawUser
-		if err := dec.Decode(&raw); err != nil {
-			return w.onProcessingError(ctx, job, fmt.Errorf("decode user at index %d: %w", rowIndex, err))
+	if err := pipeline.Err(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
+		return w.onProcessingError(ctx, job, err)
+	}
 
-		summary.ProcessedCount++
-
-		userAggregate, validationErr := raw.toDomain()
-		if validationErr != nil {
-			summary.FailedCount++
-			summary.SkippedCount++
-			if len(summary.Failures) < maxStoredFailures {
-				summary.Failures = append(summary.Failures, domain.ImportFailure{
-					RowIndex: rowIndex,
-					Reason:   validationErr.Error(),
-				})
-			}
-			rowIndex++
-			continue
-		}
+	metadata := reader.Metadata()
+	summary.Metadata = metadata
+	if count, ok := metadataRecordCount(metadata); ok {
+		summary.ExpectedCount = &count
+	}
 
-		chunk = append(chunk, userAggregate)
-		if len(chunk) >= w.cfg.ChunkSize {
-			if err := flush(); err != nil {
-				return w.onProcessingError(ctx, job, fmt.Errorf("flush chunk: %w", err))
-			}
-			if err := w.repo.Heartbeat(ctx, job.ID, w.cfg.LeaseDuration); err != nil {
-				return w.onProcessingError(ctx, job, fmt.Errorf("heartbeat after flush: %w", err))
-			}
+	if err := w.repo.UpdateProgress(ctx, job.ID, progressOf(summary)); err != nil {
+		return w.onProcessingError(ctx, job, fmt.Errorf("update final progress: %w", err))
+	}
+
+	if err := w.repo.Complete(ctx, job.ID, summary); err != nil {
+		return w.onProcessingError(ctx, job, fmt.Errorf("complete job: %w", err))
+	}
+
+	return nil
+}
+
+// commitBatch folds a batch into the job summary. Batches are committed in read
+// order, so progress checkpoints never count rows ahead of an unfinished chunk.
+func (w *ImportWorker) commitBatch(ctx context.Context, jobID string, summary *domain.ImportSummary, batch *importBatch) error {
+	stats := batch.stats
+	summary.ProcessedCount += stats.ProcessedCount
+	summary.FailedCount += stats.FailedCount
+	summary.SkippedCount += stats.SkippedCount
+	summary.WarningCount += stats.WarningCount
+	for _, failure := range stats.Failures {
+		if len(summary.Failures) >= maxStoredFailures {
+			break
 		}
+		summary.Failures = append(summary.Failures, failure)
+	}
+	for _, warning := range stats.Warnings {
+		if len(summary.Warnings) >= maxStoredFailures {
+			break
+		}
+		summary.Warnings = append(summary.Warnings, warning)
+	}
+	for key, count := range stats.UnknownFields {
+		if summary.UnknownFields == nil {
+			summary.UnknownFields = make(map[string]int64)
+		}
+		summary.UnknownFields[key] += count
+	}
+
+	if len(batch.users) == 0 {
+		return nil
+	}
 
-		rowIndex++
+	summary.ChunkStats.Add(int64(len(batch.users)), batch.bytes, batch.writeDuration)
+	summary.ChunkStats.MaxHeapBytes = max(summary.ChunkStats.MaxHeapBytes, heapBytes())
+
+	result := batch.result
+	summary.ImportedCount += result.ImportedCount
+	summary.UpdatedCount += result.UpdatedCount
+	summary.SkippedCount += result.SkippedCount
+	summary.FailedCount += result.FailedCount
+	for _, skip := range result.Skipped {
+		if len(summary.Skipped) >= maxStoredFailures {
+			break
+		}
+		skip.Locator = batch.locator(skip.RowIndex)
+		skip.RowIndex = batch.rowIndex(skip.RowIndex)
+		summary.Skipped = append(summary.Skipped, skip)
+	}
+	for _, failure := range result.Failures {
+		if len(summary.Failures) >= maxStoredFailures {
+			break
+		}
+		failure.Locator = batch.locator(failure.RowIndex)
+		failure.RowIndex = batch.rowIndex(failure.RowIndex)
+		summary.Failures = append(summary.Failures, failure)
 	}
 
-	if _, err := dec.Token(); err != nil {
-		return w.onProcessingError(ctx, job, fmt.Errorf("read json end token: %w", err))
+	if len(result.Conflicts) > 0 {
+		conflicts := make([]domain.ImportConflict, 0, len(result.Conflicts))
+		for _, conflict := range result.Conflicts {
+			conflict.Locator = batch.locator(conflict.RowIndex)
+			conflict.RowIndex = batch.rowIndex(conflict.RowIndex)
+			conflicts = append(conflicts, conflict)
+		}
+		if err := w.repo.RecordConflicts(ctx, jobID, conflicts); err != nil {
+			return fmt.Errorf("flush chunk: %w", err)
+		}
 	}
 
-	if err := flush(); err != nil {
-		return w.onProcessingError(ctx, job, fmt.Errorf("flush last chunk: %w", err))
+	if err := w.repo.UpdateProgress(ctx, jobID, progressOf(*summary)); err != nil {
+		return fmt.Errorf("flush chunk: %w", err)
+	}
+	if err := w.repo.Heartbeat(ctx, jobID, w.cfg.LeaseDuration); err != nil {
+		return fmt.Errorf("heartbeat after flush: %w", err)
 	}
+	return nil
+}
 
-	if err := w.repo.UpdateProgress(ctx, job.ID, domain.ImportProgress{
+func progressOf(summary domain.ImportSummary) domain.ImportProgress {
+	return domain.ImportProgress{
 		ProcessedCount: summary.ProcessedCount,
 		ImportedCount:  summary.ImportedCount,
 		UpdatedCount:   summary.UpdatedCount,
 		SkippedCount:   summary.SkippedCount,
 		FailedCount:    summary.FailedCount,
-	}); err != nil {
-		return w.onProcessingError(ctx, job, fmt.Errorf("update final progress: %w", err))
-	}
-
-	if err := w.repo.Complete(ctx, job.ID, summary); err != nil {
-		return w.onProcessingError(ctx, job, fmt.Errorf("complete job: %w", err))
+		WarningCount:   summary.WarningCount,
+		ChunkStats:     summary.ChunkStats,
+		Failures:       summary.Failures,
+		Skipped:        summary.Skipped,
+		Warnings:       summary.Warnings,
 	}
-
-	return nil
 }
 
 func (w *ImportWorker) onProcessingError(ctx context.Context, job domain.ImportJob, err error) error {
@@ -266,6 +387,25 @@ func sleepWithContext(ctx context.Context, d time.Duration) bool {
 	}
 }
 
+func failureReason(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrInvalidPhoneNumber):
+		return domain.FailureReasonInvalidPhoneNumber
+	case errors.Is(err, domain.ErrInvalidAddressType):
+		return domain.FailureReasonInvalidAddressType
+	case errors.Is(err, domain.ErrMultipleDefaultAddresses):
+		return domain.FailureReasonMultipleDefaults
+	case errors.Is(err, domain.ErrInvalidCountry):
+		return domain.AddressIssueUnknownCountry
+	case errors.Is(err, domain.ErrInvalidPostalCode):
+		return domain.AddressIssueInvalidPostalCode
+	case errors.Is(err, domain.ErrInvalidSubdivision):
+		return domain.AddressIssueUnknownSubdivision
+	default:
+		return err.Error()
+	}
+}
+
 func truncateReason(reason string) string {
 	const maxLen = 1000
 	reason = strings.TrimSpace(reason)
@@ -281,17 +421,141 @@ type rawAddress struct {
 	State   string `json:"state"`
 	ZipCode string `json:"zip_code"`
 	Country string `json:"country"`
+
+	Type      string `json:"type"`
+	IsDefault bool   `json:"is_default"`
+
+	unknown []string
+}
+
+var rawAddressFields = map[string]struct{}{
+	"street":     {},
+	"city":       {},
+	"state":      {},
+	"zip_code":   {},
+	"country":    {},
+	"type":       {},
+	"is_default": {},
+}
+
+func (a *rawAddress) UnmarshalJSON(data []byte) error {
+	type plainRawAddress rawAddress
+	if err := json.Unmarshal(data, (*plainRawAddress)(a)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key := range fields {
+		if _, known := rawAddressFields[strings.ToLower(key)]; !known {
+			a.unknown = append(a.unknown, key)
+		}
+	}
+	sort.Strings(a.unknown)
+	return nil
+}
+
+type unknownField struct {
+	Path string
+	Key  string
+}
+
+func (u rawUser) unknownFields(attributeFields []string) []unknownField {
+	var fields []unknownField
+	keys := make([]string, 0, len(u.unknown))
+	for key := range u.unknown {
+		if !slices.Contains(attributeFields, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fields = append(fields, unknownField{Path: key, Key: key})
+	}
+
+	for i, address := range u.Addresses {
+		for _, key := range address.unknown {
+			fields = append(fields, unknownField{
+				Path: fmt.Sprintf("addresses[%d].%s", i, key),
+				Key:  "addresses." + key,
+			})
+		}
+	}
+	return fields
 }
 
 type rawUser struct {
-	ID          string       `json:"id"`
-	Name        string       `json:"name"`
-	Email       string       `json:"email"`
-	PhoneNumber string       `json:"phone_number"`
-	Addresses   []rawAddress `json:"addresses"`
+	ID               string                     `json:"id"`
+	Name             string                     `json:"name"`
+	Email            string                     `json:"email"`
+	PhoneNumber      string                     `json:"phone_number"`
+	Addresses        []rawAddress               `json:"addresses"`
+	UpdatedAt        string                     `json:"updated_at"`
+	SourceModifiedAt string                     `json:"source_modified_at"`
+	Attributes       map[string]json.RawMessage `json:"attributes"`
+
+	unknown map[string]json.RawMessage
+}
+
+var rawUserFields = map[string]struct{}{
+	"id":                 {},
+	"name":               {},
+	"email":              {},
+	"phone_number":       {},
+	"addresses":          {},
+	"updated_at":         {},
+	"source_modified_at": {},
+	"attributes":         {},
 }
 
-func (u rawUser) toDomain() (domain.User, error) {
+func (u *rawUser) UnmarshalJSON(data []byte) error {
+	type plainRawUser rawUser
+	if err := json.Unmarshal(data, (*plainRawUser)(u)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		if _, known := rawUserFields[strings.ToLower(key)]; known {
+			continue
+		}
+		if u.unknown == nil {
+			u.unknown = make(map[string]json.RawMessage)
+		}
+		u.unknown[key] = value
+	}
+	return nil
+}
+
+func (u rawUser) attributes(fields []string) map[string]any {
+	attributes := make(map[string]any, len(u.unknown)+len(u.Attributes))
+	if len(fields) == 0 {
+		for key, value := range u.unknown {
+			attributes[key] = value
+		}
+	} else {
+		for _, key := range fields {
+			if value, ok := u.unknown[key]; ok {
+				attributes[key] = value
+			}
+		}
+	}
+	for key, value := range u.Attributes {
+		attributes[key] = value
+	}
+
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
+
+func (u rawUser) toDomain(userOptions domain.UserOptions, addressRules domain.AddressValidationRules, attributeFields []string) (domain.User, []domain.AddressIssue, error) {
 	addresses := make([]domain.Address, 0, len(u.Addresses))
 	for _, address := range u.Addresses {
 		addresses = append(addresses, domain.Address{
@@ -300,8 +564,39 @@ func (u rawUser) toDomain() (domain.User, error) {
 			State:   address.State,
 			ZipCode: address.ZipCode,
 			Country: address.Country,
+
+			Type:      domain.AddressType(address.Type),
+			IsDefault: address.IsDefault,
 		})
 	}
 
-	return domain.NewUser(u.ID, u.Name, u.Email, u.PhoneNumber, addresses)
+	sourceModifiedAt, err := u.sourceModifiedAt()
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	userAggregate, err := domain.NewUserWithOptions(userOptions, u.ID, u.Name, u.Email, u.PhoneNumber, addresses)
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+	userAggregate.SourceModifiedAt = sourceModifiedAt
+	userAggregate.Attributes = u.attributes(attributeFields)
+
+	return userAggregate.ValidateAddresses(addressRules)
+}
+
+func (u rawUser) sourceModifiedAt() (time.Time, error) {
+	value := strings.TrimSpace(u.SourceModifiedAt)
+	if value == "" {
+		value = strings.TrimSpace(u.UpdatedAt)
+	}
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidSourceTimestamp
+	}
+	return parsed, nil
 }
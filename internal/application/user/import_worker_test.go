@@ -1,12 +1,19 @@
 package user_test
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+	"unicode/utf16"
 
 	app "github.com/mohammadpnp/user-import/internal/application/user"
 	domain "github.com/mohammadpnp/user-import/internal/domain/user"
@@ -20,9 +27,11 @@ type fakeWorkerRepo struct {
 	requeueCalled   bool
 	failCalled      bool
 	failMessage     string
+	conflicts       []domain.ImportConflict
+	partitions      []domain.ImportPartition
 }
 
-func (f *fakeWorkerRepo) Enqueue(ctx context.Context, sourcePath string) (string, error) {
+func (f *fakeWorkerRepo) Enqueue(ctx context.Context, sourcePath string, options domain.ImportOptions) (string, error) {
 	return "", nil
 }
 
@@ -35,6 +44,11 @@ func (f *fakeWorkerRepo) ClaimNext(ctx context.Context, leaseDuration time.Durat
 	return job, nil
 }
 
+func (f *fakeWorkerRepo) Partition(ctx context.Context, jobID string, partitions []domain.ImportPartition) error {
+	f.partitions = partitions
+	return nil
+}
+
 func (f *fakeWorkerRepo) Heartbeat(ctx context.Context, jobID string, leaseDuration time.Duration) error {
 	return nil
 }
@@ -55,6 +69,11 @@ func (f *fakeWorkerRepo) Requeue(ctx context.Context, jobID string, reason strin
 	return nil
 }
 
+func (f *fakeWorkerRepo) RecordConflicts(ctx context.Context, jobID string, conflicts []domain.ImportConflict) error {
+	f.conflicts = append(f.conflicts, conflicts...)
+	return nil
+}
+
 func (f *fakeWorkerRepo) Fail(ctx context.Context, jobID string, reason string) error {
 	f.failCalled = true
 	f.failMessage = reason
@@ -73,15 +92,36 @@ func (f *fakeSource) Open(ctx context.Context, sourcePath string) (io.ReadCloser
 	return io.NopCloser(strings.NewReader(f.data)), nil
 }
 
+type fakeRangeSource struct {
+	fakeSource
+}
+
+func (f *fakeRangeSource) Size(ctx context.Context, sourcePath string) (int64, error) {
+	return int64(len(f.data)), nil
+}
+
+func (f *fakeRangeSource) OpenRange(ctx context.Context, sourcePath string, start, end int64) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.data[start:end])), nil
+}
+
 type fakeBulkImporter struct {
-	result app.ImportChunkResult
-	err    error
-	calls  int
-	rows   int
+	mu      sync.Mutex
+	result  app.ImportChunkResult
+	err     error
+	calls   int
+	rows    int
+	jobID   string
+	options domain.ImportOptions
+	users   []domain.User
 }
 
-func (f *fakeBulkImporter) ImportChunk(ctx context.Context, jobID string, users []domain.User) (app.ImportChunkResult, error) {
+func (f *fakeBulkImporter) ImportChunk(ctx context.Context, jobID string, options domain.ImportOptions, users []domain.User) (app.ImportChunkResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.calls++
+	f.jobID = jobID
+	f.options = options
+	f.users = append(f.users, users...)
 	f.rows += len(users)
 	if f.err != nil {
 		return app.ImportChunkResult{}, f.err
@@ -98,14 +138,14 @@ func TestImportWorkerProcessJobSuccess(t *testing.T) {
         "id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
         "name":"Alice",
         "email":"alice@example.com",
-        "phone_number":"1111111111",
+        "phone_number":"+15125550111",
         "addresses":[{"street":"1 Main","city":"Austin","state":"TX","zip_code":"78701","country":"USA"}]
       },
       {
         "id":"",
         "name":"Broken",
         "email":"bad-email",
-        "phone_number":"2222222222",
+        "phone_number":"+15125550122",
         "addresses":[{"street":"2 Main","city":"Austin","state":"TX","zip_code":"78702","country":"USA"}]
       }
     ]`}
@@ -150,11 +190,303 @@ func TestImportWorkerProcessJobSuccess(t *testing.T) {
 	}
 }
 
+func TestImportWorkerProcessJobPassesOptions(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550111","addresses":[]}]`}
+	importer := &fakeBulkImporter{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{AddressStrategy: domain.AddressStrategyAppend},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if importer.options.AddressStrategy != domain.AddressStrategyAppend {
+		t.Fatalf("unexpected address strategy: %q", importer.options.AddressStrategy)
+	}
+}
+
+func TestImportWorkerProcessJobSourceTimestamps(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550101","updated_at":"2026-01-02T03:04:05Z"},
+      {"id":"","name":"Bob","email":"bob@example.com","phone_number":"+15125550102","source_modified_at":"yesterday"},
+      {"id":"","name":"Carol","email":"carol@example.com","phone_number":"+15125550103","source_modified_at":"2026-01-02T03:04:05+02:00"}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{
+		UpdatedCount: 1,
+		SkippedCount: 1,
+		Skipped:      []domain.ImportSkip{{RowIndex: 1, Reason: domain.SkipReasonStaleSourceTimestamp}},
+	}}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(importer.users) != 2 {
+		t.Fatalf("expected 2 staged users, got %d", len(importer.users))
+	}
+	if want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC); !importer.users[0].SourceModifiedAt.Equal(want) {
+		t.Fatalf("unexpected source timestamp: %v", importer.users[0].SourceModifiedAt)
+	}
+
+	summary := repo.completeSummary
+	if summary.FailedCount != 1 || len(summary.Failures) != 1 || summary.Failures[0].RowIndex != 1 {
+		t.Fatalf("expected invalid timestamp failure at row 1, got %+v", summary.Failures)
+	}
+	if summary.Failures[0].Reason != app.ErrInvalidSourceTimestamp.Error() {
+		t.Fatalf("unexpected failure reason: %s", summary.Failures[0].Reason)
+	}
+	if len(summary.Skipped) != 1 || summary.Skipped[0].RowIndex != 2 {
+		t.Fatalf("expected stale skip mapped to row 2, got %+v", summary.Skipped)
+	}
+	if summary.Skipped[0].Locator.Row != 3 {
+		t.Fatalf("expected stale skip located at source row 3, got %+v", summary.Skipped[0].Locator)
+	}
+
+	checkpoint := repo.progressCalls[len(repo.progressCalls)-1]
+	if !reflect.DeepEqual(checkpoint.Failures, summary.Failures) || !reflect.DeepEqual(checkpoint.Skipped, summary.Skipped) {
+		t.Fatalf("expected progress to carry the reported rows, got failures=%+v skipped=%+v", checkpoint.Failures, checkpoint.Skipped)
+	}
+}
+
+func TestImportWorkerProcessJobNormalizesEmails(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"","name":"Alice","email":" Alice.Smith+crm@GMail.com ","phone_number":"+15125550101"}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{ImportedCount: 1}}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{
+		ChunkSize:       10,
+		LeaseDuration:   30 * time.Second,
+		EmailNormalizer: domain.NewEmailNormalizer(domain.GmailEmailRule),
+	})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(importer.users) != 1 {
+		t.Fatalf("expected 1 staged user, got %d", len(importer.users))
+	}
+	if importer.users[0].Email != "Alice.Smith+crm@gmail.com" || importer.users[0].EmailKey != "alicesmith@gmail.com" {
+		t.Fatalf("unexpected normalized email: %q / %q", importer.users[0].Email, importer.users[0].EmailKey)
+	}
+}
+
+func TestImportWorkerProcessJobRejectsInvalidPhoneNumbers(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"","name":"Alice","email":"alice@example.com","phone_number":"call me"},
+      {"id":"","name":"Bob","email":"bob@example.com","phone_number":"(512) 555-0102","addresses":[{"street":"1 Main","city":"Austin","state":"TX","zip_code":"78701","country":"USA"}]},
+      {"id":"","name":"Carol","email":"carol@example.com","phone_number":"512 555 0103"}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{ImportedCount: 1}}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(importer.users) != 1 || importer.users[0].PhoneNumber != "+15125550102" || importer.users[0].PhoneNumberRaw != "(512) 555-0102" {
+		t.Fatalf("unexpected staged users: %+v", importer.users)
+	}
+
+	summary := repo.completeSummary
+	if len(summary.Failures) != 2 || summary.Failures[0].RowIndex != 0 || summary.Failures[0].Reason != domain.FailureReasonInvalidPhoneNumber ||
+		summary.Failures[1].RowIndex != 2 || summary.Failures[1].Reason != domain.FailureReasonInvalidPhoneNumber {
+		t.Fatalf("expected unparseable and region-less numbers to fail, got %+v", summary.Failures)
+	}
+
+	repo = &fakeWorkerRepo{}
+	importer = &fakeBulkImporter{}
+	worker = app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+	job := domain.ImportJob{ID: "job-2", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3, Options: domain.ImportOptions{PhoneRegion: "US"}}
+	if err := worker.ProcessJob(context.Background(), job); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 2 || importer.users[1].PhoneNumber != "+15125550103" {
+		t.Fatalf("expected phone_region to normalize the number without an address, got %+v", importer.users)
+	}
+}
+
+func TestImportWorkerProcessJobValidatesAddresses(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"","name":"Alice","email":"alice@example.com","phone_number":"+15125550101","addresses":[{"street":"1 Main","city":"Austin","state":"TX","zip_code":"7870","country":"United States"}]},
+      {"id":"","name":"Bob","email":"bob@example.com","phone_number":"+15125550102","addresses":[{"street":"2 Main","city":"Nowhere","state":"NA","zip_code":"1","country":"Atlantis"}]}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{ImportedCount: 1}}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{AddressValidation: domain.AddressValidationRules{Country: domain.ValidationError, Normalize: true}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(importer.users) != 1 || importer.users[0].Addresses[0].Country != "US" {
+		t.Fatalf("unexpected staged users: %+v", importer.users)
+	}
+
+	summary := repo.completeSummary
+	if summary.WarningCount != 1 || len(summary.Warnings) != 1 || summary.Warnings[0].Reason != domain.AddressIssueInvalidPostalCode || summary.Warnings[0].Field != "addresses[0]" {
+		t.Fatalf("unexpected warnings: %+v", summary.Warnings)
+	}
+	if len(summary.Failures) != 1 || summary.Failures[0].RowIndex != 1 || summary.Failures[0].Reason != domain.AddressIssueUnknownCountry {
+		t.Fatalf("unexpected failures: %+v", summary.Failures)
+	}
+}
+
+func TestImportWorkerProcessJobFailsRecordsWithMismatchedTypes(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"","name":"Alice","email":"alice@example.com","phone_number":5125550100},
+      {"id":"","name":"Bob","email":"bob@example.com","phone_number":"+15125550101"}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{ImportedCount: 1}}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 1 || importer.users[0].Name != "Bob" {
+		t.Fatalf("expected only the well-typed record to be imported, got %+v", importer.users)
+	}
+
+	summary := repo.completeSummary
+	if summary == nil || summary.ProcessedCount != 2 || summary.FailedCount != 1 || len(summary.Failures) != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	failure := summary.Failures[0]
+	if failure.RowIndex != 0 || failure.Reason != domain.FailureReasonInvalidRecord || failure.Field != "phone_number" || failure.Locator.Row != 1 {
+		t.Fatalf("unexpected failure: %+v", failure)
+	}
+}
+
+func TestImportWorkerProcessJobStoresUnparsableRecords(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"id":"","name":"Alice","email":"alice@example.com","phone_number":"+15125550100"}
+{"id":"","name":"Broken",
+{"id":"","name":"Bob","email":"bob@example.com","phone_number":"+15125550101"}
+`
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{ImportedCount: 1}}
+
+	// One row per chunk leaves the broken record alone in its batch.
+	worker := app.NewImportWorker(repo, &fakeSource{data: payload}, importer, app.ImportWorkerConfig{ChunkSize: 1, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.ndjson",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Format: domain.ImportFormatNDJSON},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 2 {
+		t.Fatalf("expected the records around the broken one to be imported, got %+v", importer.users)
+	}
+
+	want := []domain.ImportFailure{{
+		RowIndex: 1,
+		Locator:  domain.RecordLocator{Row: 2, Line: 2, Offset: int64(strings.Index(payload, "\n") + 1)},
+		Reason:   domain.FailureReasonInvalidRecord,
+	}}
+	if !reflect.DeepEqual(repo.completeSummary.Failures, want) {
+		t.Fatalf("expected failures %+v, got %+v", want, repo.completeSummary.Failures)
+	}
+	checkpoint := repo.progressCalls[len(repo.progressCalls)-1]
+	if !reflect.DeepEqual(checkpoint.Failures, want) {
+		t.Fatalf("expected the decode failure to be stored with the progress, got %+v", checkpoint.Failures)
+	}
+}
+
+func TestImportWorkerProcessJobRecordsConflicts(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"","name":"Broken","email":"bad-email","phone_number":"+15125550101"},
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550102"}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{
+		SkippedCount: 1,
+		FailedCount:  1,
+		Failures:     []domain.ImportFailure{{RowIndex: 0, Reason: domain.FailureReasonIdentityConflict}},
+		Conflicts: []domain.ImportConflict{{
+			RowIndex:    0,
+			ExternalID:  "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+			IDUserID:    "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+			EmailUserID: "d5987b5f-506d-4d84-934f-d5b5535a64e8",
+			Resolution:  domain.IdentityConflictReject,
+		}},
+	}}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(repo.conflicts) != 1 || repo.conflicts[0].RowIndex != 1 {
+		t.Fatalf("expected conflict recorded at row 1, got %+v", repo.conflicts)
+	}
+
+	summary := repo.completeSummary
+	if summary.FailedCount != 2 {
+		t.Fatalf("expected failed=2, got %d", summary.FailedCount)
+	}
+	if len(summary.Failures) != 2 || summary.Failures[1].RowIndex != 1 || summary.Failures[1].Reason != domain.FailureReasonIdentityConflict {
+		t.Fatalf("unexpected failures: %+v", summary.Failures)
+	}
+	if summary.Failures[1].Locator.Row != 2 {
+		t.Fatalf("expected identity conflict failure to locate its record, got %+v", summary.Failures[1].Locator)
+	}
+}
+
 func TestImportWorkerProcessJobRetryableFailure(t *testing.T) {
 	t.Parallel()
 
 	repo := &fakeWorkerRepo{}
-	source := &fakeSource{data: `[{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"1111111111","addresses":[]}]`}
+	source := &fakeSource{data: `[{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550111","addresses":[]}]`}
 	importer := &fakeBulkImporter{err: errors.New("copy failed")}
 
 	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
@@ -175,7 +507,7 @@ func TestImportWorkerProcessJobTerminalFailure(t *testing.T) {
 	t.Parallel()
 
 	repo := &fakeWorkerRepo{}
-	source := &fakeSource{data: `[{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"1111111111","addresses":[]}]`}
+	source := &fakeSource{data: `[{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550111","addresses":[]}]`}
 	importer := &fakeBulkImporter{err: errors.New("copy failed")}
 
 	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
@@ -191,3 +523,1312 @@ func TestImportWorkerProcessJobTerminalFailure(t *testing.T) {
 		t.Fatal("did not expect requeue to be called")
 	}
 }
+
+func TestImportWorkerProcessJobAppliesSnapshotRules(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550100","addresses":[]},
+      {"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@other.org","addresses":[]}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{ImportedCount: 1}}
+	rules := []domain.ValidationRule{
+		{ID: "phone-required", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldPhoneNumber},
+		{ID: "corporate-email", Type: domain.ValidationRuleEnum, Field: domain.RuleFieldEmailDomain, Values: []string{"example.com"}},
+	}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Profile: "crm", Rules: rules},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(importer.users) != 1 || importer.users[0].Name != "Alice" {
+		t.Fatalf("expected only Alice to be imported, got %+v", importer.users)
+	}
+
+	summary := repo.completeSummary
+	if summary.FailedCount != 1 {
+		t.Fatalf("expected failed=1, got %d", summary.FailedCount)
+	}
+	if len(summary.Failures) != 2 {
+		t.Fatalf("expected one failure per violated rule, got %+v", summary.Failures)
+	}
+	for i, want := range []struct{ ruleID, field string }{
+		{"phone-required", string(domain.RuleFieldPhoneNumber)},
+		{"corporate-email", string(domain.RuleFieldEmailDomain)},
+	} {
+		failure := summary.Failures[i]
+		if failure.RowIndex != 1 || failure.Reason != domain.FailureReasonRuleViolation || failure.RuleID != want.ruleID || failure.Field != want.field {
+			t.Fatalf("unexpected failure %d: %+v", i, failure)
+		}
+	}
+
+	checkpoint := repo.progressCalls[len(repo.progressCalls)-1]
+	if !reflect.DeepEqual(checkpoint.Failures, summary.Failures) {
+		t.Fatalf("expected rule violations to be stored with the progress, got %+v", checkpoint.Failures)
+	}
+}
+
+func TestImportWorkerProcessJobInvalidSnapshotRulesFail(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[]`}
+	importer := &fakeBulkImporter{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    3,
+		MaxAttempts: 3,
+		Options: domain.ImportOptions{Rules: []domain.ValidationRule{
+			{ID: "bad", Type: domain.ValidationRuleRegex, Field: domain.RuleFieldName, Pattern: "("},
+		}},
+	})
+	if !errors.Is(err, domain.ErrInvalidValidationRule) {
+		t.Fatalf("expected ErrInvalidValidationRule, got %v", err)
+	}
+	if !repo.failCalled {
+		t.Fatal("expected fail to be called")
+	}
+}
+
+func TestImportWorkerProcessJobEnforcesFieldLimits(t *testing.T) {
+	t.Parallel()
+
+	payload := `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alexandria","email":"alex@example.com","phone_number":"+15125550100","addresses":[{"street":"1 Main","city":"Austin","state":"TX","zip_code":"78701","country":"US"}]},
+      {"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@example.com","phone_number":"+15125550101","addresses":[]}
+    ]`
+	limits := domain.FieldLimits{Name: 5, Email: 320, PhoneNumber: 32}
+
+	cases := []struct {
+		policy      domain.OversizePolicy
+		wantUsers   int
+		wantFailed  int64
+		wantWarning int64
+	}{
+		{policy: domain.OversizeReject, wantUsers: 1, wantFailed: 1},
+		{policy: domain.OversizeTruncate, wantUsers: 2, wantWarning: 1},
+	}
+	for _, tc := range cases {
+		repo := &fakeWorkerRepo{}
+		importer := &fakeBulkImporter{}
+		worker := app.NewImportWorker(repo, &fakeSource{data: payload}, importer, app.ImportWorkerConfig{
+			ChunkSize:     10,
+			LeaseDuration: 30 * time.Second,
+			FieldLimits:   limits,
+		})
+
+		err := worker.ProcessJob(context.Background(), domain.ImportJob{
+			ID:          "job-1",
+			SourcePath:  "users_data.json",
+			Attempts:    1,
+			MaxAttempts: 3,
+			Options:     domain.ImportOptions{OversizePolicy: tc.policy},
+		})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.policy, err)
+		}
+
+		summary := repo.completeSummary
+		if len(importer.users) != tc.wantUsers || summary.FailedCount != tc.wantFailed || summary.WarningCount != tc.wantWarning {
+			t.Fatalf("%s: unexpected result users=%d summary=%+v", tc.policy, len(importer.users), summary)
+		}
+		switch tc.policy {
+		case domain.OversizeReject:
+			if len(summary.Failures) != 1 || summary.Failures[0].Reason != domain.FailureReasonValueTooLong || summary.Failures[0].Field != "name" {
+				t.Fatalf("unexpected failures: %+v", summary.Failures)
+			}
+		case domain.OversizeTruncate:
+			if importer.users[0].Name != "Alexa" {
+				t.Fatalf("expected truncated name, got %q", importer.users[0].Name)
+			}
+			if len(summary.Warnings) != 1 || summary.Warnings[0].Reason != domain.WarningReasonValueTruncated || summary.Warnings[0].Field != "name" {
+				t.Fatalf("unexpected warnings: %+v", summary.Warnings)
+			}
+			checkpoint := repo.progressCalls[len(repo.progressCalls)-1]
+			if !reflect.DeepEqual(checkpoint.Warnings, summary.Warnings) {
+				t.Fatalf("expected truncation warnings to be stored with the progress, got %+v", checkpoint.Warnings)
+			}
+		}
+	}
+}
+
+func TestImportWorkerProcessJobRejectsMultipleDefaultAddresses(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550100","addresses":[
+        {"street":"1 Main","city":"Austin","state":"TX","zip_code":"78701","country":"US","type":"home","is_default":true},
+        {"street":"5 Side","city":"Austin","state":"TX","zip_code":"78702","country":"US","type":"home","is_default":true}
+      ]},
+      {"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@example.com","phone_number":"+15125550101","addresses":[
+        {"street":"2 Main","city":"Austin","state":"TX","zip_code":"78701","country":"US","type":"shipping"}
+      ]}
+    ]`}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{ImportedCount: 1}}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	summary := repo.completeSummary
+	if len(summary.Failures) != 1 || summary.Failures[0].Reason != domain.FailureReasonMultipleDefaults {
+		t.Fatalf("unexpected failures: %+v", summary.Failures)
+	}
+	if len(importer.users) != 1 {
+		t.Fatalf("expected one imported user, got %d", len(importer.users))
+	}
+	address := importer.users[0].Addresses[0]
+	if address.Type != domain.AddressTypeShipping || !address.IsDefault || !address.PromotedDefault {
+		t.Fatalf("expected the only shipping address to be promoted, got %+v", address)
+	}
+}
+
+func TestImportWorkerProcessJobCapturesAttributes(t *testing.T) {
+	t.Parallel()
+
+	payload := `[{
+      "id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+      "name":"Alice",
+      "email":"alice@example.com",
+      "phone_number":"+15125550100",
+      "addresses":[],
+      "department":"Sales",
+      "cost_center":1234,
+      "attributes":{"employee_type":"contractor"}
+    }]`
+
+	cases := []struct {
+		fields []string
+		want   map[string]string
+	}{
+		{want: map[string]string{"department": `"Sales"`, "cost_center": "1234", "employee_type": `"contractor"`}},
+		{fields: []string{"department"}, want: map[string]string{"department": `"Sales"`, "employee_type": `"contractor"`}},
+	}
+	for _, tc := range cases {
+		repo := &fakeWorkerRepo{}
+		importer := &fakeBulkImporter{}
+		worker := app.NewImportWorker(repo, &fakeSource{data: payload}, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+		err := worker.ProcessJob(context.Background(), domain.ImportJob{
+			ID:          "job-1",
+			SourcePath:  "users_data.json",
+			Attempts:    1,
+			MaxAttempts: 3,
+			Options:     domain.ImportOptions{AttributeFields: tc.fields},
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(importer.users) != 1 {
+			t.Fatalf("expected one user, got %d", len(importer.users))
+		}
+
+		got := make(map[string]string)
+		for key, value := range importer.users[0].Attributes {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				t.Fatalf("marshal attribute %q: %v", key, err)
+			}
+			got[key] = string(encoded)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("fields %v: expected attributes %v, got %v", tc.fields, tc.want, got)
+		}
+	}
+}
+
+func TestImportWorkerProcessJobSchemaMode(t *testing.T) {
+	t.Parallel()
+
+	payload := `[{
+      "id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+      "name":"Alice",
+      "email":"alice@example.com",
+      "phone_number":"+15125550100",
+      "phoneNumber":"+15125550100",
+      "department":"Sales",
+      "addresses":[{"street":"1 Main St","city":"Austin","state":"TX","zip_code":"73301","zipCode":"73301","country":"US"}]
+    },{
+      "id":"f7c1c7a4-3c55-4f1f-8e25-0d8f1d0f3a11",
+      "name":"Bob",
+      "email":"bob@example.com",
+      "phone_number":"+15125550101",
+      "phoneNumber":"+15125550101",
+      "addresses":[]
+    }]`
+
+	wantHistogram := map[string]int64{"phoneNumber": 2, "addresses.zipCode": 1}
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: payload}, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{AttributeFields: []string{"department"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 2 || repo.completeSummary.FailedCount != 0 {
+		t.Fatalf("expected lenient mode to import both rows, got %d users and failures %+v", len(importer.users), repo.completeSummary.Failures)
+	}
+	if !reflect.DeepEqual(repo.completeSummary.UnknownFields, wantHistogram) {
+		t.Fatalf("expected unknown fields %v, got %v", wantHistogram, repo.completeSummary.UnknownFields)
+	}
+
+	repo = &fakeWorkerRepo{}
+	importer = &fakeBulkImporter{}
+	worker = app.NewImportWorker(repo, &fakeSource{data: payload}, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+	err = worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{AttributeFields: []string{"department"}, SchemaMode: domain.SchemaModeStrict},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 0 {
+		t.Fatalf("expected strict mode to reject both rows, got %d users", len(importer.users))
+	}
+	summary := repo.completeSummary
+	if summary.FailedCount != 2 || summary.SkippedCount != 2 {
+		t.Fatalf("expected two failed rows, got failed=%d skipped=%d", summary.FailedCount, summary.SkippedCount)
+	}
+	if !reflect.DeepEqual(summary.UnknownFields, wantHistogram) {
+		t.Fatalf("expected unknown fields %v, got %v", wantHistogram, summary.UnknownFields)
+	}
+
+	first := domain.RecordLocator{Row: 1, Offset: 1}
+	second := domain.RecordLocator{Row: 2, Offset: int64(strings.Index(payload, "},{") + 2)}
+	wantFailures := []domain.ImportFailure{
+		{RowIndex: 0, Locator: first, Reason: domain.FailureReasonUnknownField, Field: "phoneNumber"},
+		{RowIndex: 0, Locator: first, Reason: domain.FailureReasonUnknownField, Field: "addresses[0].zipCode"},
+		{RowIndex: 1, Locator: second, Reason: domain.FailureReasonUnknownField, Field: "phoneNumber"},
+	}
+	if !reflect.DeepEqual(summary.Failures, wantFailures) {
+		t.Fatalf("expected failures %+v, got %+v", wantFailures, summary.Failures)
+	}
+	checkpoint := repo.progressCalls[len(repo.progressCalls)-1]
+	if !reflect.DeepEqual(checkpoint.Failures, wantFailures) {
+		t.Fatalf("expected unknown field failures to be stored with the progress, got %+v", checkpoint.Failures)
+	}
+}
+
+func TestImportWorkerProcessJobUsesJobChunkSizeAndFieldMapping(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","fullName":"Alice","mail":"alice@example.com","phone_number":"+15125550100","addresses":[]},
+      {"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","fullName":"Bob","mail":"bob@example.com","phone_number":"+15125550101","addresses":[]}
+    ]`}
+	importer := &fakeBulkImporter{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options: domain.ImportOptions{
+			ChunkSize:    1,
+			FieldMapping: map[string]string{"fullName": "name", "mail": "email"},
+			SchemaMode:   domain.SchemaModeStrict,
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(importer.users) != 2 || importer.users[0].Name != "Alice" || importer.users[1].Email != "bob@example.com" {
+		t.Fatalf("expected mapped users, got %+v", importer.users)
+	}
+	if importer.calls != 2 {
+		t.Fatalf("expected one chunk per row, got %d", importer.calls)
+	}
+}
+
+func TestImportWorkerProcessJobAppliesFieldTransforms(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[{
+      "id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+      "first":"alice",
+      "last":"SMITH",
+      "contact":{"email":" Alice@Example.com ","phone":"+15125550100"},
+      "addr":[{"street":"1 Main St","city":"Austin","state":"TX","zip_code":"73301","country":"US"}],
+      "cost_center":12345678901234567890
+    }]`}
+	importer := &fakeBulkImporter{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options: domain.ImportOptions{
+			SchemaMode:      domain.SchemaModeStrict,
+			AttributeFields: []string{"cost_center"},
+			FieldMapping:    map[string]string{"addr": "addresses"},
+			Transforms: []domain.FieldTransform{
+				{Target: "name", Sources: []string{"first", "last"}, Case: domain.FieldCaseTitle},
+				{Target: "email", Source: "/contact/email", Trim: true},
+				{Target: "phone_number", Source: "/contact/phone"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.completeSummary.FailedCount != 0 || len(repo.completeSummary.UnknownFields) != 0 {
+		t.Fatalf("expected mapped row to pass strict mode, got %+v", repo.completeSummary)
+	}
+	if len(importer.users) != 1 {
+		t.Fatalf("expected one user, got %d", len(importer.users))
+	}
+
+	user := importer.users[0]
+	if user.Name != "Alice Smith" || user.Email != "Alice@example.com" || user.PhoneNumber != "+15125550100" || len(user.Addresses) != 1 {
+		t.Fatalf("unexpected mapped user: %+v", user)
+	}
+	encoded, err := json.Marshal(user.Attributes["cost_center"])
+	if err != nil || string(encoded) != "12345678901234567890" {
+		t.Fatalf("expected cost_center to keep its exact value, got %s, %v", encoded, err)
+	}
+}
+
+func TestImportWorkerProcessJobReadsWrappedPayload(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `{
+      "meta": {"exported_at": "2024-05-01T10:00:00Z", "total": "3", "tags": ["a", {"b": [1, 2]}]},
+      "links": [{"next": null}, {"prev": "/page/0"}],
+      "data": {
+        "cursor": {"skip": [[1], {"x": "]"}]},
+        "users": [
+          {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550100","addresses":[]},
+          {"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@example.com","phone_number":"+15125550101","addresses":[]}
+        ],
+        "page": 1
+      },
+      "checksum": "abc"
+    }`}
+	importer := &fakeBulkImporter{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options: domain.ImportOptions{
+			RecordsPointer: "/data/users",
+			Metadata: map[string]string{
+				"exported_at":              "/meta/exported_at",
+				domain.MetadataRecordCount: "/meta/total",
+				"checksum":                 "/checksum",
+				"page":                     "/data/page",
+				"missing":                  "/meta/missing",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 2 || importer.users[1].Name != "Bob" {
+		t.Fatalf("expected wrapped users to be imported, got %+v", importer.users)
+	}
+
+	summary := repo.completeSummary
+	if summary == nil {
+		t.Fatal("expected job to complete")
+	}
+	encoded, err := json.Marshal(summary.Metadata)
+	if err != nil {
+		t.Fatalf("marshal metadata: %v", err)
+	}
+	if string(encoded) != `{"checksum":"abc","exported_at":"2024-05-01T10:00:00Z","page":1,"record_count":"3"}` {
+		t.Fatalf("unexpected metadata: %s", encoded)
+	}
+	if summary.ExpectedCount == nil || *summary.ExpectedCount != 3 || summary.ProcessedCount != 2 {
+		t.Fatalf("expected record count 3 against 2 processed, got %v/%d", summary.ExpectedCount, summary.ProcessedCount)
+	}
+}
+
+func TestImportWorkerProcessJobRejectsInvalidRecordsPointer(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"missing":    `{"data": {"people": []}}`,
+		"not array":  `{"data": {"users": {"id": "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79"}}}`,
+		"not object": `[{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79"}]`,
+	}
+	for name, data := range cases {
+		repo := &fakeWorkerRepo{}
+		worker := app.NewImportWorker(repo, &fakeSource{data: data}, &fakeBulkImporter{}, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+		err := worker.ProcessJob(context.Background(), domain.ImportJob{
+			ID:          "job-1",
+			SourcePath:  "users_data.json",
+			Attempts:    3,
+			MaxAttempts: 3,
+			Options:     domain.ImportOptions{RecordsPointer: "/data/users"},
+		})
+		if err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		if repo.completeSummary != nil || !repo.failCalled {
+			t.Fatalf("%s: expected job to fail, got %+v", name, repo)
+		}
+	}
+}
+
+func TestImportWorkerProcessJobTranscodesSource(t *testing.T) {
+	t.Parallel()
+
+	payload := `[{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"José Müller","email":"jose@example.com","phone_number":"+15125550100","addresses":[]}]`
+	cases := []struct {
+		name     string
+		data     string
+		encoding domain.ImportEncoding
+	}{
+		{name: "utf-8 bom", data: "\xEF\xBB\xBF" + payload},
+		{name: "utf-16le bom", data: "\xFF\xFE" + encodeUTF16(payload, false)},
+		{name: "utf-16be bom", data: "\xFE\xFF" + encodeUTF16(payload, true)},
+		{name: "utf-16le sniffed", data: encodeUTF16(payload, false)},
+		{name: "explicit utf-16be", data: encodeUTF16(payload, true), encoding: domain.ImportEncodingUTF16BE},
+		{name: "latin1", data: strings.NewReplacer("é", "\xE9", "ü", "\xFC").Replace(payload), encoding: domain.ImportEncodingLatin1},
+		{name: "windows-1252", data: strings.NewReplacer("é", "\xE9", "ü", "\xFC").Replace(payload), encoding: domain.ImportEncodingWindows1252},
+	}
+	for _, tc := range cases {
+		repo := &fakeWorkerRepo{}
+		importer := &fakeBulkImporter{}
+		worker := app.NewImportWorker(repo, &fakeSource{data: tc.data}, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+		err := worker.ProcessJob(context.Background(), domain.ImportJob{
+			ID:          "job-1",
+			SourcePath:  "users_data.json",
+			Attempts:    1,
+			MaxAttempts: 3,
+			Options:     domain.ImportOptions{Encoding: tc.encoding},
+		})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.name, err)
+		}
+		if len(importer.users) != 1 || importer.users[0].Name != "José Müller" {
+			t.Fatalf("%s: expected transcoded name, got %+v", tc.name, importer.users)
+		}
+	}
+}
+
+func TestImportWorkerProcessJobLocatesTranscodedRecordsInSource(t *testing.T) {
+	t.Parallel()
+
+	first := `{"id":"","name":"José Müller","email":"jose@example.com","phone_number":"+15125550100","addresses":[]}`
+	second := `{"id":"","name":"Zoë","email":"zoe@example.com","phone_number":"+15125550101","addresses":[]}`
+	latin1 := strings.NewReplacer("é", "\xE9", "ü", "\xFC", "ë", "\xEB")
+	xmlUser := func(name, email string) string {
+		return "<user><name>" + name + "</name><email>" + email + "</email><phone_number>+15125550100</phone_number></user>"
+	}
+	xmlHead := `<?xml version="1.0" encoding="ISO-8859-1"?><users>`
+	cases := []struct {
+		name       string
+		path       string
+		options    domain.ImportOptions
+		head, tail string
+		encode     func(string) string
+	}{
+		{
+			name:    "json latin1",
+			path:    "users_data.json",
+			options: domain.ImportOptions{Encoding: domain.ImportEncodingLatin1},
+			head:    "[" + first + ",",
+			tail:    second + "]",
+			encode:  latin1.Replace,
+		},
+		{
+			name:   "json utf-16le bom",
+			path:   "users_data.json",
+			head:   "[" + first + ",",
+			tail:   second + "]",
+			encode: func(text string) string { return "\xFF\xFE" + encodeUTF16(text, false) },
+		},
+		{
+			name:    "ndjson utf-16be bom",
+			path:    "users_data.ndjson",
+			options: domain.ImportOptions{Format: domain.ImportFormatNDJSON},
+			head:    first + "\n",
+			tail:    second + "\n",
+			encode:  func(text string) string { return "\xFE\xFF" + encodeUTF16(text, true) },
+		},
+		{
+			name:    "xml declared latin1",
+			path:    "users_data.xml",
+			options: domain.ImportOptions{Format: domain.ImportFormatXML},
+			head:    xmlHead + xmlUser("José Müller", "jose@example.com"),
+			tail:    xmlUser("Zoë", "zoe@example.com") + "</users>",
+			encode:  latin1.Replace,
+		},
+	}
+	for _, tc := range cases {
+		importer := &fakeBulkImporter{}
+		worker := app.NewImportWorker(&fakeWorkerRepo{}, &fakeSource{data: tc.encode(tc.head + tc.tail)}, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+		err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: tc.path, Attempts: 1, MaxAttempts: 3, Options: tc.options})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.name, err)
+		}
+		want := int64(len(tc.encode(tc.head)))
+		if len(importer.users) != 2 || importer.users[1].Name != "Zoë" || importer.users[1].SourcePosition != want {
+			t.Fatalf("%s: expected the second record at source byte %d, got %+v", tc.name, want, importer.users)
+		}
+	}
+}
+
+func TestImportWorkerProcessJobRejectsInvalidUTF8Rows(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	source := &fakeSource{data: `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Jos` + "\xE9" + `","email":"jose@example.com","phone_number":"+15125550100","addresses":[]},
+      {"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@example.com","phone_number":"+15125550101","addresses":[]}
+    ]`}
+	importer := &fakeBulkImporter{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users_data.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 1 || importer.users[0].Name != "Bob" {
+		t.Fatalf("expected only the valid row to be imported, got %+v", importer.users)
+	}
+
+	summary := repo.completeSummary
+	if summary.ProcessedCount != 2 || summary.FailedCount != 1 || len(summary.Failures) != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if failure := summary.Failures[0]; failure.RowIndex != 0 || failure.Reason != domain.FailureReasonInvalidEncoding {
+		t.Fatalf("unexpected failure: %+v", failure)
+	}
+}
+
+func TestImportWorkerProcessJobRejectsConflictingByteOrderMark(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: "\xFF\xFE" + encodeUTF16(`[]`, false)}, &fakeBulkImporter{}, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users_data.json",
+		Attempts:    3,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Encoding: domain.ImportEncodingLatin1},
+	})
+	if err == nil || !repo.failCalled {
+		t.Fatalf("expected job to fail, got %v", err)
+	}
+}
+
+func encodeUTF16(value string, bigEndian bool) string {
+	var out strings.Builder
+	for _, unit := range utf16.Encode([]rune(value)) {
+		if bigEndian {
+			out.WriteByte(byte(unit >> 8))
+			out.WriteByte(byte(unit))
+		} else {
+			out.WriteByte(byte(unit))
+			out.WriteByte(byte(unit >> 8))
+		}
+	}
+	return out.String()
+}
+
+func TestImportWorkerProcessJobReadsXLSXSheet(t *testing.T) {
+	t.Parallel()
+
+	workbook := buildXLSX(t, map[string]string{
+		"xl/workbook.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+  <sheets><sheet name="Notes" sheetId="1" r:id="rId1"/><sheet name="Users" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`,
+		"xl/_rels/workbook.xml.rels": `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+  <Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+  <Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+  <Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/sharedStrings" Target="sharedStrings.xml"/>
+  <Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`,
+		"xl/sharedStrings.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <si><t>id</t></si><si><t>name</t></si><si><t>email</t></si><si><t>phone_number</t></si>
+  <si><t>addresses.0.street</t></si><si><t>addresses.0.zip_code</t></si><si><t>addresses.0.country</t></si>
+  <si><r><t>Alice </t></r><r><t>Smith</t></r><rPh><t>ignored</t></rPh></si>
+  <si><t>alice@example.com</t></si><si><t>US</t></si>
+</sst>`,
+		"xl/styles.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+  <numFmts count="1"><numFmt numFmtId="164" formatCode="00000"/></numFmts>
+  <cellXfs count="2"><xf numFmtId="0"/><xf numFmtId="164"/></cellXfs>
+</styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row r="1"><c r="A1" t="inlineStr"><is><t>not users</t></is></c></row></sheetData></worksheet>`,
+		"xl/worksheets/sheet2.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+  <row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c><c r="D1" t="s"><v>3</v></c><c r="E1" t="s"><v>4</v></c><c r="F1" t="s"><v>5</v></c><c r="G1" t="s"><v>6</v></c><c r="H1" t="inlineStr"><is><t>addresses.0.city</t></is></c><c r="I1" t="inlineStr"><is><t>addresses.0.state</t></is></c></row>
+  <row r="2"><c r="A2" t="inlineStr"><is><t>ab5e6ab5-ae1a-4a52-94f3-9c266d266c79</t></is></c><c r="B2" t="s"><v>7</v></c><c r="C2" t="s"><v>8</v></c><c r="D2"><v>1.5125550100E10</v></c><c r="E2" t="str"><v>1 Main St</v></c><c r="F2" s="1"><v>2134</v></c><c r="G2" t="s"><v>9</v></c><c r="H2" t="inlineStr"><is><t>Boston</t></is></c><c r="I2" t="inlineStr"><is><t>MA</t></is></c></row>
+  <row r="3"><c r="A3" s="1"/><c r="B3" t="inlineStr"><is><t></t></is></c></row>
+  <row r="5"><c r="A5" t="inlineStr"><is><t>d5987b5f-506d-4d84-934f-d5b5535a64e8</t></is></c><c r="B5" t="inlineStr"><is><t>Bob</t></is></c><c r="C5" t="inlineStr"><is><t>bob@example.com</t></is></c><c r="D5" t="inlineStr"><is><t>+15125550101</t></is></c></row>
+</sheetData></worksheet>`,
+	})
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: workbook}, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.xlsx",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Format: domain.ImportFormatXLSX, Sheet: "users", SchemaMode: domain.SchemaModeStrict},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.completeSummary.ProcessedCount != 2 || repo.completeSummary.FailedCount != 0 {
+		t.Fatalf("unexpected summary: %+v", repo.completeSummary)
+	}
+	if len(importer.users) != 2 {
+		t.Fatalf("expected two users, got %+v", importer.users)
+	}
+
+	alice := importer.users[0]
+	if alice.Name != "Alice Smith" || alice.Email != "alice@example.com" || alice.PhoneNumber != "+15125550100" {
+		t.Fatalf("unexpected user: %+v", alice)
+	}
+	if len(alice.Addresses) != 1 || alice.Addresses[0].ZipCode != "02134" || alice.Addresses[0].Street != "1 Main St" {
+		t.Fatalf("unexpected addresses: %+v", alice.Addresses)
+	}
+	if importer.users[1].Name != "Bob" || len(importer.users[1].Addresses) != 0 {
+		t.Fatalf("unexpected user: %+v", importer.users[1])
+	}
+}
+
+func TestImportWorkerProcessJobRejectsMissingXLSXSheet(t *testing.T) {
+	t.Parallel()
+
+	workbook := buildXLSX(t, map[string]string{
+		"xl/workbook.xml":            `<workbook><sheets></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships></Relationships>`,
+	})
+
+	repo := &fakeWorkerRepo{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: workbook}, &fakeBulkImporter{}, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.xlsx",
+		Attempts:    3,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Format: domain.ImportFormatXLSX, Sheet: "Users"},
+	})
+	if err == nil || !repo.failCalled || !strings.Contains(repo.failMessage, `sheet "Users" not found`) {
+		t.Fatalf("expected missing sheet failure, got %v / %q", err, repo.failMessage)
+	}
+}
+
+func buildXLSX(t *testing.T, parts map[string]string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for name, content := range parts {
+		part, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("create xlsx part %s: %v", name, err)
+		}
+		if _, err := part.Write([]byte(content)); err != nil {
+			t.Fatalf("write xlsx part %s: %v", name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatalf("close xlsx archive: %v", err)
+	}
+	return buf.String()
+}
+
+func TestImportWorkerProcessJobReadsXMLFeed(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{data: `<?xml version="1.0" encoding="UTF-8"?>
+<p:export xmlns:p="urn:partner" xmlns:x="urn:other">
+  <p:header><p:user id="not-a-record"/></p:header>
+  <p:people>
+    <p:user id="ab5e6ab5-ae1a-4a52-94f3-9c266d266c79">
+      <p:fullName>Alice Smith</p:fullName>
+      <p:contact><p:email>alice@example.com</p:email><p:phone>+15125550100</p:phone></p:contact>
+      <p:addresses>
+        <p:address default="true" zip="02134"><p:street>1 Main St</p:street><p:city>Boston</p:city><p:state>MA</p:state><p:country>US</p:country></p:address>
+        <p:address zip="73301"><p:street>2 Oak Ave</p:street><p:city>Austin</p:city><p:state>TX</p:state><p:country>US</p:country></p:address>
+      </p:addresses>
+      <x:fullName>ignored</x:fullName>
+    </p:user>
+    <p:user id="d5987b5f-506d-4d84-934f-d5b5535a64e8">
+      <p:fullName>Bob</p:fullName>
+      <p:contact><p:email>bob@example.com</p:email><p:phone>+15125550101</p:phone></p:contact>
+    </p:user>
+  </p:people>
+</p:export>`}
+	importer := &fakeBulkImporter{}
+	repo := &fakeWorkerRepo{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.xml",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options: domain.ImportOptions{
+			Format:     domain.ImportFormatXML,
+			SchemaMode: domain.SchemaModeStrict,
+			XML: domain.XMLMapping{
+				RecordPath: "/p:export/p:people/p:user",
+				Fields: map[string]string{
+					"id":           "@id",
+					"name":         "p:fullName",
+					"email":        "p:contact/p:email",
+					"phone_number": "p:contact/p:phone",
+				},
+				AddressPath:   "p:addresses/p:address",
+				AddressFields: map[string]string{"zip_code": "@zip", "is_default": "@default"},
+				Namespaces:    map[string]string{"p": "urn:partner"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.completeSummary.ProcessedCount != 2 || repo.completeSummary.FailedCount != 0 {
+		t.Fatalf("unexpected summary: %+v", repo.completeSummary)
+	}
+	if len(importer.users) != 2 {
+		t.Fatalf("expected two users, got %+v", importer.users)
+	}
+
+	alice := importer.users[0]
+	if alice.ID != "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79" || alice.Name != "Alice Smith" || alice.PhoneNumber != "+15125550100" {
+		t.Fatalf("unexpected user: %+v", alice)
+	}
+	if len(alice.Addresses) != 2 || alice.Addresses[0].ZipCode != "02134" || !alice.Addresses[0].IsDefault || alice.Addresses[1].City != "Austin" {
+		t.Fatalf("unexpected addresses: %+v", alice.Addresses)
+	}
+	if importer.users[1].Email != "bob@example.com" {
+		t.Fatalf("unexpected user: %+v", importer.users[1])
+	}
+}
+
+func TestImportWorkerProcessJobReadsXMLWithDefaultMapping(t *testing.T) {
+	t.Parallel()
+
+	source := &fakeSource{data: "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<users><user>" +
+		"<id>ab5e6ab5-ae1a-4a52-94f3-9c266d266c79</id><name>Jos\xE9</name><email>jose@example.com</email>" +
+		"<phone_number>+15125550100</phone_number><nickname>JJ</nickname>" +
+		"<addresses><address><street>1 Main St</street><city>Austin</city><state>TX</state><zip_code>73301</zip_code><country>US</country></address></addresses>" +
+		"</user></users>"}
+	importer := &fakeBulkImporter{}
+	repo := &fakeWorkerRepo{}
+
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.xml",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Format: domain.ImportFormatXML},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 1 || importer.users[0].Name != "José" || len(importer.users[0].Addresses) != 1 {
+		t.Fatalf("unexpected users: %+v", importer.users)
+	}
+	if repo.completeSummary.UnknownFields["nickname"] != 1 {
+		t.Fatalf("expected unmapped element to be reported, got %+v", repo.completeSummary.UnknownFields)
+	}
+}
+
+type fakeRecordReader struct {
+	records []app.Record
+	closed  bool
+}
+
+func (f *fakeRecordReader) Next() (app.Record, error) {
+	if len(f.records) == 0 {
+		return app.Record{}, io.EOF
+	}
+	record := f.records[0]
+	f.records = f.records[1:]
+	return record, nil
+}
+
+func (f *fakeRecordReader) Metadata() map[string]any {
+	return map[string]any{"source": "fake"}
+}
+
+func (f *fakeRecordReader) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestImportWorkerProcessJobUsesRegisteredRecordReader(t *testing.T) {
+	t.Parallel()
+
+	reader := &fakeRecordReader{records: []app.Record{
+		{
+			Fields: map[string]any{
+				"id":           "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+				"name":         "Alice",
+				"email":        "alice@example.com",
+				"phone_number": "+15125550100",
+			},
+			Locator: domain.RecordLocator{Row: 3},
+		},
+		{Locator: domain.RecordLocator{Row: 4, Line: 7}, Err: errors.New("unparseable row")},
+		{
+			Raw:     json.RawMessage(`{"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@example.com","phone_number":"bad"}`),
+			Locator: domain.RecordLocator{Row: 5, Offset: 120},
+		},
+	}}
+	var gotOptions domain.ImportOptions
+	readers := app.RecordReaders{
+		domain.ImportFormatJSON: func(source io.Reader, options domain.ImportOptions) (app.RecordReader, error) {
+			gotOptions = options
+			return reader, nil
+		},
+	}
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: "ignored"}, importer, app.ImportWorkerConfig{
+		ChunkSize:     10,
+		LeaseDuration: 30 * time.Second,
+		RecordReaders: readers,
+	})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users.json", Attempts: 1, MaxAttempts: 3})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if gotOptions.Format != domain.ImportFormatJSON || !reader.closed {
+		t.Fatalf("expected reader to be opened with defaults and closed, got %+v closed=%v", gotOptions, reader.closed)
+	}
+	if len(importer.users) != 1 || importer.users[0].Name != "Alice" {
+		t.Fatalf("expected one imported user, got %+v", importer.users)
+	}
+
+	summary := repo.completeSummary
+	if summary.ProcessedCount != 3 || summary.FailedCount != 2 || summary.Metadata["source"] != "fake" {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	wantFailures := []domain.ImportFailure{
+		{RowIndex: 1, Locator: domain.RecordLocator{Row: 4, Line: 7}, Reason: domain.FailureReasonInvalidRecord},
+		{RowIndex: 2, Locator: domain.RecordLocator{Row: 5, Offset: 120}, Reason: domain.FailureReasonInvalidPhoneNumber},
+	}
+	if !reflect.DeepEqual(summary.Failures, wantFailures) {
+		t.Fatalf("expected failures %+v, got %+v", wantFailures, summary.Failures)
+	}
+}
+
+func TestImportWorkerProcessJobRejectsUnregisteredFormat(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: "[]"}, &fakeBulkImporter{}, app.ImportWorkerConfig{
+		ChunkSize:     10,
+		LeaseDuration: 30 * time.Second,
+		RecordReaders: app.RecordReaders{},
+	})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users.json", Attempts: 3, MaxAttempts: 3})
+	if err == nil || !repo.failCalled || !strings.Contains(repo.failMessage, "unsupported record format: json") {
+		t.Fatalf("expected unsupported format failure, got %v / %q", err, repo.failMessage)
+	}
+}
+
+type overlappingImporter struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	overlapped  chan struct{}
+	overlap     sync.Once
+	imported    int
+}
+
+func (f *overlappingImporter) ImportChunk(ctx context.Context, jobID string, options domain.ImportOptions, users []domain.User) (app.ImportChunkResult, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxInFlight = max(f.maxInFlight, f.inFlight)
+	if f.inFlight > 1 {
+		f.overlap.Do(func() { close(f.overlapped) })
+	}
+	f.imported += len(users)
+	f.mu.Unlock()
+
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	if users[0].Name == "User 0" {
+		select {
+		case <-f.overlapped:
+		case <-time.After(2 * time.Second):
+		}
+	}
+	return app.ImportChunkResult{
+		ImportedCount: int64(len(users)) - 1,
+		FailedCount:   1,
+		Failures:      []domain.ImportFailure{{RowIndex: 1, Reason: "duplicate_in_chunk"}},
+	}, nil
+}
+
+func TestImportWorkerProcessJobWritesChunksConcurrentlyInOrder(t *testing.T) {
+	t.Parallel()
+
+	users := make([]string, 0, 8)
+	for i := range 8 {
+		users = append(users, fmt.Sprintf(`{"id":"00000000-0000-4000-8000-%012d","name":"User %d","email":"user%d@example.com","phone_number":"+15125550100"}`, i, i, i))
+	}
+	repo := &fakeWorkerRepo{}
+	importer := &overlappingImporter{overlapped: make(chan struct{})}
+	worker := app.NewImportWorker(repo, &fakeSource{data: "[" + strings.Join(users, ",") + "]"}, importer, app.ImportWorkerConfig{
+		ChunkSize:     2,
+		LeaseDuration: 30 * time.Second,
+	})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.json",
+		Options:     domain.ImportOptions{ChunkConcurrency: 3},
+		Attempts:    1,
+		MaxAttempts: 3,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if importer.maxInFlight < 2 || importer.maxInFlight > 3 || importer.imported != 8 {
+		t.Fatalf("expected 2-3 concurrent chunks importing 8 users, got max %d, imported %d", importer.maxInFlight, importer.imported)
+	}
+
+	var processed []int64
+	for _, progress := range repo.progressCalls {
+		processed = append(processed, progress.ProcessedCount)
+	}
+	if want := []int64{2, 4, 6, 8, 8}; !reflect.DeepEqual(processed, want) {
+		t.Fatalf("expected ordered checkpoints %v, got %v", want, processed)
+	}
+
+	summary := repo.completeSummary
+	if summary.ImportedCount != 4 || summary.FailedCount != 4 {
+		t.Fatalf("unexpected summary counts: %+v", summary)
+	}
+	for i, failure := range summary.Failures {
+		if want := int64(2*i + 1); failure.RowIndex != want || failure.Locator.Row != want+1 {
+			t.Fatalf("failure %d: expected row %d, got %+v", i, want, failure)
+		}
+	}
+
+	stats := summary.ChunkStats
+	if stats.Chunks != 4 || stats.Rows != 8 || stats.MaxChunkRows != 2 {
+		t.Fatalf("unexpected chunk stats: %+v", stats)
+	}
+	if stats.Bytes <= 0 || stats.MaxChunkBytes <= 0 || stats.MaxChunkBytes > stats.Bytes || stats.WriteDuration <= 0 || stats.MaxHeapBytes <= 0 {
+		t.Fatalf("expected chunk sizes, write time and heap to be measured, got %+v", stats)
+	}
+	if last := repo.progressCalls[len(repo.progressCalls)-1]; last.ChunkStats != stats {
+		t.Fatalf("expected final progress to carry chunk stats %+v, got %+v", stats, last.ChunkStats)
+	}
+}
+
+func TestImportWorkerProcessJobStopsPipelineOnChunkError(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{err: errors.New("database unavailable")}
+	worker := app.NewImportWorker(repo, &fakeSource{data: `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550100"},
+      {"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@example.com","phone_number":"+15125550101"}
+    ]`}, importer, app.ImportWorkerConfig{ChunkSize: 1, ChunkConcurrency: 2, LeaseDuration: 30 * time.Second})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users.json", Attempts: 1, MaxAttempts: 3})
+	if err == nil || !repo.requeueCalled || !strings.Contains(repo.failMessage, "flush chunk: database unavailable") {
+		t.Fatalf("expected requeue after chunk error, got %v / %q", err, repo.failMessage)
+	}
+	if repo.completeSummary != nil {
+		t.Fatalf("expected job not to complete, got %+v", repo.completeSummary)
+	}
+}
+
+func TestImportWorkerProcessJobPassesEffectiveChunkConcurrency(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: `[
+      {"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550100"}
+    ]`}, importer, app.ImportWorkerConfig{ChunkSize: 1, ChunkConcurrency: 4, LeaseDuration: 30 * time.Second})
+
+	if err := worker.ProcessJob(context.Background(), domain.ImportJob{ID: "job-1", SourcePath: "users.json", Attempts: 1, MaxAttempts: 3}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if importer.options.ChunkConcurrency != 4 {
+		t.Fatalf("expected importer to see the worker chunk concurrency, got %d", importer.options.ChunkConcurrency)
+	}
+}
+
+const ndjsonUsers = `{"id":"ab5e6ab5-ae1a-4a52-94f3-9c266d266c79","name":"Alice","email":"alice@example.com","phone_number":"+15125550100"}
+
+not json
+{"id":"d5987b5f-506d-4d84-934f-d5b5535a64e8","name":"Bob","email":"bob@example.com","phone_number":"+15125550101"}
+{"id":"4955eb4d-c7f2-42f6-80ca-33838ce37c31","name":"Carol","email":"carol@example.com","phone_number":"+15125550102"}
+`
+
+func TestImportWorkerProcessJobReadsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{}
+	worker := app.NewImportWorker(repo, &fakeSource{data: ndjsonUsers}, importer, app.ImportWorkerConfig{
+		ChunkSize:     10,
+		LeaseDuration: 30 * time.Second,
+	})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.ndjson",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Format: domain.ImportFormatNDJSON},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(importer.users) != 3 || importer.users[1].Name != "Bob" {
+		t.Fatalf("expected three imported users, got %+v", importer.users)
+	}
+
+	bobOffset := int64(strings.Index(ndjsonUsers, `{"id":"d598`))
+	if importer.users[0].SourcePosition != 0 || importer.users[1].SourcePosition != bobOffset {
+		t.Fatalf("unexpected source positions: %d, %d", importer.users[0].SourcePosition, importer.users[1].SourcePosition)
+	}
+
+	summary := repo.completeSummary
+	if summary == nil || summary.ProcessedCount != 4 || summary.FailedCount != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	wantFailures := []domain.ImportFailure{{
+		RowIndex: 1,
+		Locator:  domain.RecordLocator{Row: 2, Line: 3, Offset: int64(strings.Index(ndjsonUsers, "not json"))},
+		Reason:   domain.FailureReasonInvalidRecord,
+	}}
+	if !reflect.DeepEqual(summary.Failures, wantFailures) {
+		t.Fatalf("expected failures %+v, got %+v", wantFailures, summary.Failures)
+	}
+}
+
+func TestImportWorkerProcessJobPartitionsNDJSON(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{}
+	source := &fakeRangeSource{fakeSource{data: ndjsonUsers}}
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{
+		ChunkSize:     10,
+		LeaseDuration: 30 * time.Second,
+	})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1",
+		SourcePath:  "users.ndjson",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Format: domain.ImportFormatNDJSON, Partitions: 3},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if importer.calls != 0 || repo.completeSummary != nil {
+		t.Fatalf("expected partitioned job to import nothing itself, got %d calls", importer.calls)
+	}
+	if len(repo.partitions) != 3 {
+		t.Fatalf("expected 3 partitions, got %+v", repo.partitions)
+	}
+
+	size := int64(len(ndjsonUsers))
+	var next int64
+	for i, partition := range repo.partitions {
+		if partition.Index != i || partition.Start != next || partition.End <= partition.Start {
+			t.Fatalf("unexpected partition %d: %+v", i, partition)
+		}
+		if partition.Start > 0 && ndjsonUsers[partition.Start-1] != '\n' {
+			t.Fatalf("partition %d does not start at a line boundary: %+v", i, partition)
+		}
+		next = partition.End
+	}
+	if next != size {
+		t.Fatalf("expected partitions to end at %d, got %d", size, next)
+	}
+}
+
+func TestImportWorkerProcessJobOrdersRecordsBySourcePosition(t *testing.T) {
+	t.Parallel()
+
+	jsonSource := `[{"id":"","name":"Alice","email":"alice@example.com","phone_number":"+15125550100"},
+ {"id":"","name":"Bob","email":"bob@example.com","phone_number":"+15125550101"}]`
+	xmlSource := `<users><user><name>Alice</name><email>alice@example.com</email><phone_number>+15125550100</phone_number></user>
+<user><name>Bob</name><email>bob@example.com</email><phone_number>+15125550101</phone_number></user></users>`
+	workbook := buildXLSX(t, map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Users" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml": `<worksheet><sheetData>
+  <row r="1"><c r="A1" t="inlineStr"><is><t>name</t></is></c><c r="B1" t="inlineStr"><is><t>email</t></is></c><c r="C1" t="inlineStr"><is><t>phone_number</t></is></c></row>
+  <row r="2"><c r="A2" t="inlineStr"><is><t>Alice</t></is></c><c r="B2" t="inlineStr"><is><t>alice@example.com</t></is></c><c r="C2" t="inlineStr"><is><t>+15125550100</t></is></c></row>
+  <row r="4"><c r="A4" t="inlineStr"><is><t>Bob</t></is></c><c r="B4" t="inlineStr"><is><t>bob@example.com</t></is></c><c r="C4" t="inlineStr"><is><t>+15125550101</t></is></c></row>
+</sheetData></worksheet>`,
+	})
+
+	cases := []struct {
+		format domain.ImportFormat
+		source string
+		want   map[string]int64
+	}{
+		{domain.ImportFormatJSON, jsonSource, map[string]int64{
+			"Alice": int64(strings.Index(jsonSource, `{"id":"","name":"Alice"`)),
+			"Bob":   int64(strings.Index(jsonSource, `{"id":"","name":"Bob"`)),
+		}},
+		{domain.ImportFormatXML, xmlSource, map[string]int64{
+			"Alice": int64(strings.Index(xmlSource, "<user><name>Alice")),
+			"Bob":   int64(strings.Index(xmlSource, "<user><name>Bob")),
+		}},
+		{domain.ImportFormatXLSX, workbook, map[string]int64{"Alice": 2, "Bob": 4}},
+	}
+	for _, tc := range cases {
+		repo := &fakeWorkerRepo{}
+		importer := &fakeBulkImporter{}
+		// Partitions are ignored for formats that cannot be split at line boundaries.
+		worker := app.NewImportWorker(repo, &fakeRangeSource{fakeSource{data: tc.source}}, importer, app.ImportWorkerConfig{ChunkSize: 1, LeaseDuration: 30 * time.Second})
+
+		err := worker.ProcessJob(context.Background(), domain.ImportJob{
+			ID:          "job-1",
+			SourcePath:  "users",
+			Attempts:    1,
+			MaxAttempts: 3,
+			Options:     domain.ImportOptions{Format: tc.format, ChunkConcurrency: 2, Partitions: 2},
+		})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tc.format, err)
+		}
+		if len(repo.partitions) != 0 {
+			t.Fatalf("%s: expected the job not to be partitioned, got %+v", tc.format, repo.partitions)
+		}
+
+		got := make(map[string]int64, len(importer.users))
+		for _, user := range importer.users {
+			got[user.Name] = user.SourcePosition
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("%s: expected source positions %v, got %v", tc.format, tc.want, got)
+		}
+	}
+}
+
+func TestImportWorkerProcessJobImportsPartitionRange(t *testing.T) {
+	t.Parallel()
+
+	start := int64(strings.Index(ndjsonUsers, "not json"))
+	end := int64(strings.Index(ndjsonUsers, `{"id":"4955`))
+
+	repo := &fakeWorkerRepo{}
+	importer := &fakeBulkImporter{result: app.ImportChunkResult{
+		Conflicts: []domain.ImportConflict{{
+			RowIndex:    0,
+			ExternalID:  "d5987b5f-506d-4d84-934f-d5b5535a64e8",
+			IDUserID:    "d5987b5f-506d-4d84-934f-d5b5535a64e8",
+			EmailUserID: "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+			Resolution:  domain.IdentityConflictSuperseded,
+		}},
+	}}
+	source := &fakeRangeSource{fakeSource{data: ndjsonUsers}}
+	worker := app.NewImportWorker(repo, source, importer, app.ImportWorkerConfig{
+		ChunkSize:     10,
+		LeaseDuration: 30 * time.Second,
+	})
+
+	err := worker.ProcessJob(context.Background(), domain.ImportJob{
+		ID:          "job-1-p1",
+		ParentID:    "job-1",
+		SourcePath:  "users.ndjson",
+		Attempts:    1,
+		MaxAttempts: 3,
+		Options:     domain.ImportOptions{Format: domain.ImportFormatNDJSON, Partitions: 3},
+		Partition:   &domain.ImportPartition{Index: 1, Start: start, End: end},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(repo.partitions) != 0 {
+		t.Fatalf("expected partition job not to be split again, got %+v", repo.partitions)
+	}
+	if importer.jobID != "job-1" {
+		t.Fatalf("expected chunks to be written under the parent job, got %q", importer.jobID)
+	}
+	if len(importer.users) != 1 || importer.users[0].Name != "Bob" {
+		t.Fatalf("expected only Bob to be imported, got %+v", importer.users)
+	}
+	if want := int64(strings.Index(ndjsonUsers, `{"id":"d598`)); importer.users[0].SourcePosition != want {
+		t.Fatalf("expected source position %d, got %d", want, importer.users[0].SourcePosition)
+	}
+	if want := int64(strings.Index(ndjsonUsers, `{"id":"d598`)); len(repo.conflicts) != 1 || repo.conflicts[0].Locator.Offset != want {
+		t.Fatalf("expected the conflict to be located at source offset %d, got %+v", want, repo.conflicts)
+	}
+
+	summary := repo.completeSummary
+	if summary == nil || summary.ProcessedCount != 2 || len(summary.Failures) != 1 || summary.Failures[0].Locator.Offset != start {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+}
@@ -0,0 +1,277 @@
+package user
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+type jsonFrame struct {
+	path   []string
+	object bool
+	index  int
+}
+
+type jsonRecordStream struct {
+	dec      *json.Decoder
+	records  []string
+	metadata map[string][]string
+	captured map[string]any
+	stack    []jsonFrame
+}
+
+type jsonRecordReader struct {
+	dec      *json.Decoder
+	offsets  *sourceOffsets
+	stream   *jsonRecordStream
+	row      int64
+	metadata map[string]any
+	done     bool
+}
+
+func newJSONRecordReader(source io.Reader, options domain.ImportOptions) (RecordReader, error) {
+	text, offsets, err := newTextReader(source, options.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("detect source encoding: %w", err)
+	}
+
+	dec := json.NewDecoder(text)
+	stream := newJSONRecordStream(dec, options.RecordsPointer, options.Metadata)
+	if err := stream.Open(); err != nil {
+		return nil, err
+	}
+	return &jsonRecordReader{dec: dec, offsets: offsets, stream: stream}, nil
+}
+
+func (r *jsonRecordReader) Next() (Record, error) {
+	if r.done {
+		return Record{}, io.EOF
+	}
+	if !r.stream.More() {
+		r.done = true
+		if _, err := r.dec.Token(); err != nil {
+			return Record{}, fmt.Errorf("read json end token: %w", err)
+		}
+		metadata, err := r.stream.Finish()
+		if err != nil {
+			return Record{}, fmt.Errorf("read json metadata: %w", err)
+		}
+		r.metadata = metadata
+		return Record{}, io.EOF
+	}
+
+	r.row++
+	var data json.RawMessage
+	if err := r.dec.Decode(&data); err != nil {
+		return Record{}, fmt.Errorf("decode json record %d: %w", r.row, err)
+	}
+	locator := domain.RecordLocator{Row: r.row, Offset: r.offsets.Source(r.dec.InputOffset() - int64(len(data)))}
+	if !utf8.Valid(data) {
+		return Record{Locator: locator, Err: errInvalidUTF8}, nil
+	}
+	return Record{Raw: data, Locator: locator}, nil
+}
+
+func (r *jsonRecordReader) Metadata() map[string]any {
+	return r.metadata
+}
+
+func (r *jsonRecordReader) Close() error {
+	return nil
+}
+
+func newJSONRecordStream(dec *json.Decoder, recordsPointer string, metadata map[string]string) *jsonRecordStream {
+	stream := &jsonRecordStream{
+		dec:      dec,
+		records:  domain.JSONPointerTokens(recordsPointer),
+		metadata: make(map[string][]string, len(metadata)),
+	}
+	for name, pointer := range metadata {
+		stream.metadata[name] = domain.JSONPointerTokens(pointer)
+	}
+	return stream
+}
+
+func (s *jsonRecordStream) Open() error {
+	if len(s.records) == 0 {
+		token, err := s.dec.Token()
+		if err != nil {
+			return fmt.Errorf("read json start token: %w", err)
+		}
+		if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return errors.New("import payload must be a JSON array")
+		}
+		return nil
+	}
+
+	found, err := s.walk(true)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("records pointer %q not found", "/"+strings.Join(s.records, "/"))
+	}
+	return nil
+}
+
+func (s *jsonRecordStream) More() bool {
+	return s.dec.More()
+}
+
+func (s *jsonRecordStream) Finish() (map[string]any, error) {
+	if len(s.metadata) == 0 {
+		return nil, nil
+	}
+
+	if _, err := s.walk(false); err != nil {
+		return nil, err
+	}
+	return s.captured, nil
+}
+
+func (s *jsonRecordStream) walk(stopAtRecords bool) (bool, error) {
+	started := len(s.stack) > 0
+	for {
+		var path []string
+		if len(s.stack) == 0 {
+			if started {
+				return false, nil
+			}
+			started = true
+		} else {
+			frame := &s.stack[len(s.stack)-1]
+			if !s.dec.More() {
+				if _, err := s.dec.Token(); err != nil {
+					return false, fmt.Errorf("read json token: %w", err)
+				}
+				s.stack = s.stack[:len(s.stack)-1]
+				continue
+			}
+			if frame.object {
+				token, err := s.dec.Token()
+				if err != nil {
+					return false, fmt.Errorf("read json key: %w", err)
+				}
+				key, ok := token.(string)
+				if !ok {
+					return false, fmt.Errorf("unexpected json token %v", token)
+				}
+				path = append(slices.Clone(frame.path), key)
+			} else {
+				path = append(slices.Clone(frame.path), strconv.Itoa(frame.index))
+				frame.index++
+			}
+		}
+
+		if stopAtRecords && slices.Equal(path, s.records) {
+			token, err := s.dec.Token()
+			if err != nil {
+				return false, fmt.Errorf("read json records token: %w", err)
+			}
+			if delim, ok := token.(json.Delim); !ok || delim != '[' {
+				return false, errors.New("records pointer must reference a JSON array")
+			}
+			return true, nil
+		}
+
+		if name, ok := s.metadataAt(path); ok {
+			var value json.RawMessage
+			if err := s.dec.Decode(&value); err != nil {
+				return false, fmt.Errorf("decode metadata %q: %w", name, err)
+			}
+			if !utf8.Valid(value) {
+				return false, fmt.Errorf("decode metadata %q: %w", name, errInvalidUTF8)
+			}
+			if s.captured == nil {
+				s.captured = make(map[string]any)
+			}
+			s.captured[name] = value
+			continue
+		}
+
+		if !s.leadsToTarget(path, stopAtRecords) {
+			if err := s.skipValue(); err != nil {
+				return false, err
+			}
+			continue
+		}
+
+		token, err := s.dec.Token()
+		if err != nil {
+			return false, fmt.Errorf("read json token: %w", err)
+		}
+		if delim, ok := token.(json.Delim); ok {
+			s.stack = append(s.stack, jsonFrame{path: path, object: delim == '{'})
+		}
+	}
+}
+
+func (s *jsonRecordStream) metadataAt(path []string) (string, bool) {
+	for name, pointer := range s.metadata {
+		if slices.Equal(path, pointer) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (s *jsonRecordStream) leadsToTarget(path []string, includeRecords bool) bool {
+	if includeRecords && isPathPrefix(path, s.records) {
+		return true
+	}
+	for _, pointer := range s.metadata {
+		if isPathPrefix(path, pointer) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *jsonRecordStream) skipValue() error {
+	depth := 0
+	for {
+		token, err := s.dec.Token()
+		if err != nil {
+			return fmt.Errorf("skip json value: %w", err)
+		}
+		if delim, ok := token.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			default:
+				depth--
+			}
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func isPathPrefix(path, target []string) bool {
+	return len(path) < len(target) && slices.Equal(path, target[:len(path)])
+}
+
+func metadataRecordCount(metadata map[string]any) (int64, bool) {
+	raw, ok := metadata[domain.MetadataRecordCount].(json.RawMessage)
+	if !ok {
+		return 0, false
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		text = string(raw)
+	}
+	count, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+	if err != nil || count < 0 {
+		return 0, false
+	}
+	return count, true
+}
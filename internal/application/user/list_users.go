@@ -0,0 +1,78 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const (
+	defaultUserPageSize = 100
+	maxUserPageSize     = 1000
+)
+
+type ListUsersInput struct {
+	Attributes map[string]string
+	Limit      int
+	Offset     int
+}
+
+type ListUsersOutput struct {
+	Users  []GetUserByIDOutput `json:"users"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+type ListUsers interface {
+	Execute(ctx context.Context, in ListUsersInput) (ListUsersOutput, error)
+}
+
+type listUsers struct {
+	repo domain.UserQueryRepository
+}
+
+func NewListUsers(repo domain.UserQueryRepository) ListUsers {
+	return &listUsers{repo: repo}
+}
+
+func (uc *listUsers) Execute(ctx context.Context, in ListUsersInput) (ListUsersOutput, error) {
+	keys := make([]string, 0, len(in.Attributes))
+	for key := range in.Attributes {
+		keys = append(keys, key)
+	}
+	if _, err := domain.ParseAttributeKeys(keys); err != nil {
+		return ListUsersOutput{}, fmt.Errorf("%w: %v", ErrInvalidUserFilter, err)
+	}
+
+	limit := in.Limit
+	if limit <= 0 {
+		limit = defaultUserPageSize
+	}
+	if limit > maxUserPageSize {
+		limit = maxUserPageSize
+	}
+	offset := in.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, err := uc.repo.List(ctx, domain.UserFilter{
+		Attributes: in.Attributes,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		return ListUsersOutput{}, fmt.Errorf("%w: %v", ErrListUsers, err)
+	}
+
+	out := ListUsersOutput{
+		Users:  make([]GetUserByIDOutput, 0, len(users)),
+		Limit:  limit,
+		Offset: offset,
+	}
+	for _, userAggregate := range users {
+		out.Users = append(out.Users, toUserOutput(userAggregate))
+	}
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestListUsersSuccess(t *testing.T) {
+	t.Parallel()
+
+	repo := &fakeUserQueryRepo{users: []domain.User{{
+		ID:         "a3f91a91-7fdd-43bf-bfd2-00bc02f6c53e",
+		Name:       "Alice",
+		Attributes: map[string]any{"department": "Sales"},
+	}}}
+	uc := app.NewListUsers(repo)
+
+	out, err := uc.Execute(context.Background(), app.ListUsersInput{
+		Attributes: map[string]string{"department": "Sales"},
+		Limit:      5000,
+		Offset:     -1,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if repo.gotFilter.Limit != 1000 || repo.gotFilter.Offset != 0 || repo.gotFilter.Attributes["department"] != "Sales" {
+		t.Fatalf("unexpected filter: %+v", repo.gotFilter)
+	}
+	if len(out.Users) != 1 || out.Users[0].Attributes["department"] != "Sales" {
+		t.Fatalf("unexpected users: %+v", out.Users)
+	}
+}
+
+func TestListUsersInvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	uc := app.NewListUsers(&fakeUserQueryRepo{})
+
+	_, err := uc.Execute(context.Background(), app.ListUsersInput{Attributes: map[string]string{"cost center": "1"}})
+	if !errors.Is(err, app.ErrInvalidUserFilter) {
+		t.Fatalf("expected ErrInvalidUserFilter, got %v", err)
+	}
+}
+
+func TestListUsersRepositoryError(t *testing.T) {
+	t.Parallel()
+
+	uc := app.NewListUsers(&fakeUserQueryRepo{returnErr: errors.New("db down")})
+
+	_, err := uc.Execute(context.Background(), app.ListUsersInput{})
+	if !errors.Is(err, app.ErrListUsers) {
+		t.Fatalf("expected ErrListUsers, got %v", err)
+	}
+}
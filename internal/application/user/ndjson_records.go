@@ -0,0 +1,104 @@
+package user
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+var errNotJSONObject = errors.New("line is not a JSON object")
+
+type ndjsonRecordReader struct {
+	src      *bufio.Reader
+	encoding domain.ImportEncoding
+	offsets  *sourceOffsets
+	offset   int64
+	line     int64
+	row      int64
+}
+
+func newNDJSONRecordReader(source io.Reader, options domain.ImportOptions) (RecordReader, error) {
+	src := bufio.NewReader(source)
+	encoding, skipped, err := resolveTextEncoding(src, options.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("detect source encoding: %w", err)
+	}
+
+	reader := &ndjsonRecordReader{src: src, encoding: encoding, offset: int64(skipped)}
+	// Newlines can only be found in the raw bytes of single-byte-compatible
+	// encodings; UTF-16 is decoded up front and its offsets are mapped back to
+	// the source.
+	if encoding == domain.ImportEncodingUTF16LE || encoding == domain.ImportEncodingUTF16BE {
+		text, offsets := decodeText(src, encoding, 0, int64(skipped))
+		reader.src = bufio.NewReader(text)
+		reader.encoding = domain.ImportEncodingUTF8
+		reader.offsets = offsets
+		reader.offset = 0
+	}
+	return reader, nil
+}
+
+func (r *ndjsonRecordReader) Next() (Record, error) {
+	for {
+		line, err := r.src.ReadBytes('\n')
+		if len(line) == 0 && errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return Record{}, fmt.Errorf("read ndjson line %d: %w", r.line+1, err)
+		}
+
+		offset := r.offset
+		r.offset += int64(len(line))
+		if r.offsets != nil {
+			offset = r.offsets.Source(offset)
+		}
+		r.line++
+
+		data := bytes.TrimSpace(line)
+		if len(data) == 0 {
+			continue
+		}
+
+		r.row++
+		locator := domain.RecordLocator{Row: r.row, Line: r.line, Offset: offset}
+		data, err = r.decode(data)
+		if err != nil {
+			return Record{Locator: locator, Err: err}, nil
+		}
+		return Record{Raw: data, Locator: locator}, nil
+	}
+}
+
+func (r *ndjsonRecordReader) decode(data []byte) ([]byte, error) {
+	if r.encoding == domain.ImportEncodingLatin1 || r.encoding == domain.ImportEncodingWindows1252 {
+		text, _ := decodeText(bufio.NewReader(bytes.NewReader(data)), r.encoding, 0, 0)
+		decoded, err := io.ReadAll(text)
+		if err != nil {
+			return nil, err
+		}
+		data = decoded
+	}
+
+	if !utf8.Valid(data) {
+		return nil, errInvalidUTF8
+	}
+	if data[0] != '{' || !json.Valid(data) {
+		return nil, errNotJSONObject
+	}
+	return json.RawMessage(data), nil
+}
+
+func (r *ndjsonRecordReader) Metadata() map[string]any {
+	return nil
+}
+
+func (r *ndjsonRecordReader) Close() error {
+	return nil
+}
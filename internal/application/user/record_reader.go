@@ -0,0 +1,86 @@
+package user
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+var errUnsupportedRecordFormat = errors.New("unsupported record format")
+
+type Record struct {
+	Fields  map[string]any
+	Raw     json.RawMessage
+	Locator domain.RecordLocator
+	Err     error
+}
+
+type RecordReader interface {
+	Next() (Record, error)
+	Metadata() map[string]any
+	Close() error
+}
+
+type RecordReaderFactory func(source io.Reader, options domain.ImportOptions) (RecordReader, error)
+
+type RecordReaders map[domain.ImportFormat]RecordReaderFactory
+
+func DefaultRecordReaders() RecordReaders {
+	return RecordReaders{
+		domain.ImportFormatJSON:   newJSONRecordReader,
+		domain.ImportFormatNDJSON: newNDJSONRecordReader,
+		domain.ImportFormatXLSX:   newXLSXRecordReader,
+		domain.ImportFormatXML:    newXMLRecordReader,
+	}
+}
+
+func (r RecordReaders) Open(source io.Reader, options domain.ImportOptions) (RecordReader, error) {
+	factory, ok := r[options.Format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnsupportedRecordFormat, options.Format)
+	}
+	return factory(source, options)
+}
+
+func decodeRecord(record Record, mapper domain.FieldMapper) (rawUser, error) {
+	var raw rawUser
+	fields := record.Fields
+	if record.Raw != nil {
+		if mapper.Empty() {
+			err := json.Unmarshal(record.Raw, &raw)
+			return raw, err
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(record.Raw))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			return raw, err
+		}
+	}
+
+	data, err := json.Marshal(mapper.Apply(fields))
+	if err != nil {
+		return raw, err
+	}
+	err = json.Unmarshal(data, &raw)
+	return raw, err
+}
+
+func recordErrorReason(err error) string {
+	if errors.Is(err, errInvalidUTF8) {
+		return domain.FailureReasonInvalidEncoding
+	}
+	return domain.FailureReasonInvalidRecord
+}
+
+func recordErrorField(err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return typeErr.Field
+	}
+	return ""
+}
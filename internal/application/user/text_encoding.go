@@ -0,0 +1,252 @@
+package user
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const transcodeBufferSize = 4096
+
+// invalidByte is emitted for input the source encoding cannot decode, so the
+// affected row fails UTF-8 validation instead of storing a replacement character.
+const invalidByte = 0xFF
+
+var errInvalidUTF8 = errors.New("invalid utf-8 sequence")
+
+var byteOrderMarks = []struct {
+	encoding domain.ImportEncoding
+	mark     []byte
+}{
+	{encoding: domain.ImportEncodingUTF8, mark: []byte{0xEF, 0xBB, 0xBF}},
+	{encoding: domain.ImportEncodingUTF16LE, mark: []byte{0xFF, 0xFE}},
+	{encoding: domain.ImportEncodingUTF16BE, mark: []byte{0xFE, 0xFF}},
+}
+
+var windows1252Runes = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ',
+	0x89: '‰', 0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“',
+	0x94: '”', 0x95: '•', 0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›',
+	0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// newTextReader decodes r to UTF-8. The returned offsets translate positions in
+// the decoded text back to byte offsets in r, byte order mark included.
+func newTextReader(r io.Reader, encoding domain.ImportEncoding) (io.Reader, *sourceOffsets, error) {
+	src := bufio.NewReader(r)
+	encoding, skipped, err := resolveTextEncoding(src, encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+	text, offsets := decodeText(src, encoding, 0, int64(skipped))
+	return text, offsets, nil
+}
+
+// resolveTextEncoding consumes a byte order mark, if any, and reports the
+// encoding of the remaining input along with the number of bytes skipped.
+func resolveTextEncoding(src *bufio.Reader, encoding domain.ImportEncoding) (domain.ImportEncoding, int, error) {
+	head, err := src.Peek(3)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", 0, fmt.Errorf("read byte order mark: %w", err)
+	}
+
+	detected, markLen := detectByteOrderMark(head)
+	switch {
+	case detected != "" && encoding != domain.ImportEncodingAuto && encoding != detected:
+		return "", 0, fmt.Errorf("%s byte order mark conflicts with encoding %s", detected, encoding)
+	case detected != "":
+		if _, err := src.Discard(markLen); err != nil {
+			return "", 0, fmt.Errorf("skip byte order mark: %w", err)
+		}
+		return detected, markLen, nil
+	case encoding == domain.ImportEncodingAuto:
+		return sniffEncoding(head), 0, nil
+	default:
+		return encoding, 0, nil
+	}
+}
+
+// decodeText decodes src, which starts at offset decoded of the text and raw
+// of the source.
+func decodeText(src *bufio.Reader, encoding domain.ImportEncoding, decoded, raw int64) (io.Reader, *sourceOffsets) {
+	switch encoding {
+	case domain.ImportEncodingUTF16LE:
+		offsets := newSourceOffsets(2, decoded, raw)
+		return &transcodingReader{src: src, next: utf16Decoder(false), offsets: offsets}, offsets
+	case domain.ImportEncodingUTF16BE:
+		offsets := newSourceOffsets(2, decoded, raw)
+		return &transcodingReader{src: src, next: utf16Decoder(true), offsets: offsets}, offsets
+	case domain.ImportEncodingLatin1:
+		offsets := newSourceOffsets(1, decoded, raw)
+		return &transcodingReader{src: src, next: singleByteDecoder(nil), offsets: offsets}, offsets
+	case domain.ImportEncodingWindows1252:
+		offsets := newSourceOffsets(1, decoded, raw)
+		return &transcodingReader{src: src, next: singleByteDecoder(windows1252Runes), offsets: offsets}, offsets
+	default:
+		return src, newSourceOffsets(1, decoded, raw)
+	}
+}
+
+func detectByteOrderMark(head []byte) (domain.ImportEncoding, int) {
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(head, bom.mark) {
+			return bom.encoding, len(bom.mark)
+		}
+	}
+	return "", 0
+}
+
+func sniffEncoding(head []byte) domain.ImportEncoding {
+	if len(head) < 2 {
+		return domain.ImportEncodingUTF8
+	}
+	switch {
+	case head[0] != 0 && head[1] == 0:
+		return domain.ImportEncodingUTF16LE
+	case head[0] == 0 && head[1] != 0:
+		return domain.ImportEncodingUTF16BE
+	default:
+		return domain.ImportEncodingUTF8
+	}
+}
+
+// sourceOffsets maps offsets in decoded text back to the source. ASCII
+// characters take unit source bytes each, so only the characters that do not
+// are marked.
+type sourceOffsets struct {
+	unit    int64
+	decoded int64
+	raw     int64
+	marks   []offsetMark
+}
+
+type offsetMark struct {
+	decoded int64
+	raw     int64
+}
+
+func newSourceOffsets(unit, decoded, raw int64) *sourceOffsets {
+	return &sourceOffsets{unit: unit, decoded: decoded, raw: raw, marks: []offsetMark{{decoded: decoded, raw: raw}}}
+}
+
+func (o *sourceOffsets) advance(raw, decoded int) {
+	o.decoded += int64(decoded)
+	o.raw += int64(raw)
+	if int64(raw) != o.unit || decoded != 1 {
+		o.marks = append(o.marks, offsetMark{decoded: o.decoded, raw: o.raw})
+	}
+}
+
+// Source returns the source offset of the character at decoded. Offsets must
+// be looked up in increasing order, since the marks before decoded are dropped.
+func (o *sourceOffsets) Source(decoded int64) int64 {
+	i := 0
+	for i+1 < len(o.marks) && o.marks[i+1].decoded <= decoded {
+		i++
+	}
+	o.marks = o.marks[i:]
+	mark := o.marks[0]
+	return mark.raw + (decoded-mark.decoded)*o.unit
+}
+
+type transcodingReader struct {
+	src     *bufio.Reader
+	next    func(src *bufio.Reader, dst []byte) ([]byte, int, error)
+	offsets *sourceOffsets
+	buf     []byte
+	pending []byte
+	err     error
+}
+
+func (t *transcodingReader) Read(p []byte) (int, error) {
+	for len(t.pending) == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+		t.buf = t.buf[:0]
+		for len(t.buf) < transcodeBufferSize && t.err == nil {
+			decoded := len(t.buf)
+			var raw int
+			t.buf, raw, t.err = t.next(t.src, t.buf)
+			t.offsets.advance(raw, len(t.buf)-decoded)
+		}
+		t.pending = t.buf
+	}
+
+	n := copy(p, t.pending)
+	t.pending = t.pending[n:]
+	return n, nil
+}
+
+func utf16Decoder(bigEndian bool) func(src *bufio.Reader, dst []byte) ([]byte, int, error) {
+	readUnit := func(src *bufio.Reader) (uint16, error) {
+		var unit [2]byte
+		if _, err := io.ReadFull(src, unit[:]); err != nil {
+			return 0, err
+		}
+		if bigEndian {
+			return uint16(unit[0])<<8 | uint16(unit[1]), nil
+		}
+		return uint16(unit[1])<<8 | uint16(unit[0]), nil
+	}
+
+	return func(src *bufio.Reader, dst []byte) ([]byte, int, error) {
+		unit, err := readUnit(src)
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return append(dst, invalidByte), 1, io.EOF
+		}
+		if err != nil {
+			return dst, 0, err
+		}
+		if !utf16.IsSurrogate(rune(unit)) {
+			return utf8.AppendRune(dst, rune(unit)), 2, nil
+		}
+		if unit >= 0xDC00 {
+			return append(dst, invalidByte), 2, nil
+		}
+
+		next, err := src.Peek(2)
+		if err != nil {
+			return append(dst, invalidByte), 2, nil
+		}
+		low := uint16(next[1])<<8 | uint16(next[0])
+		if bigEndian {
+			low = uint16(next[0])<<8 | uint16(next[1])
+		}
+		decoded := utf16.DecodeRune(rune(unit), rune(low))
+		if decoded == utf8.RuneError {
+			return append(dst, invalidByte), 2, nil
+		}
+		_, _ = src.Discard(2)
+		return utf8.AppendRune(dst, decoded), 4, nil
+	}
+}
+
+func singleByteDecoder(overrides map[byte]rune) func(src *bufio.Reader, dst []byte) ([]byte, int, error) {
+	return func(src *bufio.Reader, dst []byte) ([]byte, int, error) {
+		b, err := src.ReadByte()
+		if err != nil {
+			return dst, 0, err
+		}
+		if b < utf8.RuneSelf {
+			return append(dst, b), 1, nil
+		}
+		if overrides == nil {
+			return utf8.AppendRune(dst, rune(b)), 1, nil
+		}
+		if b >= 0x80 && b <= 0x9F {
+			r, ok := overrides[b]
+			if !ok {
+				return append(dst, invalidByte), 1, nil
+			}
+			return utf8.AppendRune(dst, r), 1, nil
+		}
+		return utf8.AppendRune(dst, rune(b)), 1, nil
+	}
+}
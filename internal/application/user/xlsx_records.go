@@ -0,0 +1,560 @@
+package user
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const xlsxMaxArrayIndex = 100
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxStyles struct {
+	NumFmts []struct {
+		ID   int    `xml:"numFmtId,attr"`
+		Code string `xml:"formatCode,attr"`
+	} `xml:"numFmts>numFmt"`
+	CellXfs []struct {
+		NumFmtID int `xml:"numFmtId,attr"`
+	} `xml:"cellXfs>xf"`
+}
+
+type xlsxSheetReader struct {
+	dec     *xml.Decoder
+	closers []func() error
+	shared  []string
+	padding map[int]int
+	headers [][]string
+	row     int64
+}
+
+func newXLSXRecordReader(source io.Reader, options domain.ImportOptions) (RecordReader, error) {
+	return openXLSXSheet(source, options.Sheet)
+}
+
+func openXLSXSheet(r io.Reader, sheetName string) (*xlsxSheetReader, error) {
+	readerAt, size, cleanup, err := xlsxReaderAt(r)
+	if err != nil {
+		return nil, err
+	}
+	s := &xlsxSheetReader{closers: []func() error{cleanup}}
+
+	archive, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		s.Close()
+		return nil, fmt.Errorf("open xlsx archive: %w", err)
+	}
+	if err := s.open(archive, sheetName); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *xlsxSheetReader) open(archive *zip.Reader, sheetName string) error {
+	var workbook xlsxWorkbook
+	if err := unmarshalZipXML(archive, "xl/workbook.xml", &workbook); err != nil {
+		return err
+	}
+	var rels xlsxRelationships
+	if err := unmarshalZipXML(archive, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return err
+	}
+
+	targets := make(map[string]string, len(rels.Relationships))
+	var sharedPath, stylesPath string
+	for _, rel := range rels.Relationships {
+		target := xlsxPartPath(rel.Target)
+		targets[rel.ID] = target
+		switch {
+		case strings.HasSuffix(rel.Type, "/sharedStrings"):
+			sharedPath = target
+		case strings.HasSuffix(rel.Type, "/styles"):
+			stylesPath = target
+		}
+	}
+
+	sheetPath := ""
+	for _, sheet := range workbook.Sheets {
+		if sheetName == "" || strings.EqualFold(sheet.Name, sheetName) {
+			sheetPath = targets[sheet.RID]
+			break
+		}
+	}
+	if sheetPath == "" {
+		if sheetName == "" {
+			return errors.New("xlsx workbook has no sheets")
+		}
+		return fmt.Errorf("xlsx sheet %q not found", sheetName)
+	}
+
+	if sharedPath != "" {
+		shared, err := readSharedStrings(archive, sharedPath)
+		if err != nil {
+			return err
+		}
+		s.shared = shared
+	}
+	if stylesPath != "" {
+		padding, err := readZeroPaddedStyles(archive, stylesPath)
+		if err != nil {
+			return err
+		}
+		s.padding = padding
+	}
+
+	file, err := archive.Open(sheetPath)
+	if err != nil {
+		return fmt.Errorf("open xlsx sheet %s: %w", sheetPath, err)
+	}
+	s.closers = append(s.closers, file.Close)
+	s.dec = xml.NewDecoder(file)
+
+	for {
+		headers, ok, err := s.readRow()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("xlsx sheet has no header row")
+		}
+		if len(headers) > 0 {
+			return s.setHeaders(headers)
+		}
+	}
+}
+
+func (s *xlsxSheetReader) setHeaders(cells map[int]any) error {
+	maxColumn := -1
+	for column := range cells {
+		maxColumn = max(maxColumn, column)
+	}
+
+	s.headers = make([][]string, maxColumn+1)
+	probe := make(map[string]any)
+	for column, value := range cells {
+		header := strings.TrimSpace(fmt.Sprint(value))
+		if header == "" {
+			continue
+		}
+		parts := strings.Split(header, ".")
+		if err := setRecordPath(probe, parts, ""); err != nil {
+			return fmt.Errorf("xlsx header %q: %w", header, err)
+		}
+		s.headers[column] = parts
+	}
+	return nil
+}
+
+func (s *xlsxSheetReader) Next() (Record, error) {
+	for {
+		cells, ok, err := s.readRow()
+		if err != nil {
+			return Record{}, err
+		}
+		if !ok {
+			return Record{}, io.EOF
+		}
+
+		record := make(map[string]any, len(cells))
+		for column, value := range cells {
+			if column >= len(s.headers) || s.headers[column] == nil {
+				continue
+			}
+			if err := setRecordPath(record, s.headers[column], value); err != nil {
+				return Record{}, err
+			}
+		}
+		if len(record) == 0 {
+			continue
+		}
+		return Record{
+			Fields:  compactRecordArrays(record).(map[string]any),
+			Locator: domain.RecordLocator{Row: s.row},
+		}, nil
+	}
+}
+
+func (s *xlsxSheetReader) Metadata() map[string]any {
+	return nil
+}
+
+func (s *xlsxSheetReader) Close() error {
+	var errs []error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		errs = append(errs, s.closers[i]())
+	}
+	s.closers = nil
+	return errors.Join(errs...)
+}
+
+func (s *xlsxSheetReader) readRow() (map[int]any, bool, error) {
+	for {
+		token, err := s.dec.Token()
+		if errors.Is(err, io.EOF) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("read xlsx sheet: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		s.row++
+		if value := xmlAttr(start, "r"); value != "" {
+			if row, err := strconv.ParseInt(value, 10, 64); err == nil {
+				s.row = row
+			}
+		}
+
+		cells, err := s.readCells()
+		return cells, true, err
+	}
+}
+
+func (s *xlsxSheetReader) readCells() (map[int]any, error) {
+	cells := make(map[int]any)
+	column := 0
+	for {
+		token, err := s.dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("read xlsx row %d: %w", s.row, err)
+		}
+
+		switch element := token.(type) {
+		case xml.EndElement:
+			if element.Name.Local == "row" {
+				return cells, nil
+			}
+		case xml.StartElement:
+			if element.Name.Local != "c" {
+				if err := s.dec.Skip(); err != nil {
+					return nil, fmt.Errorf("read xlsx row %d: %w", s.row, err)
+				}
+				continue
+			}
+			if ref := xmlAttr(element, "r"); ref != "" {
+				parsed, err := xlsxColumnIndex(ref)
+				if err != nil {
+					return nil, fmt.Errorf("read xlsx row %d: %w", s.row, err)
+				}
+				column = parsed
+			}
+			value, err := s.readCell(element)
+			if err != nil {
+				return nil, fmt.Errorf("read xlsx cell %s: %w", xmlAttr(element, "r"), err)
+			}
+			if value != nil {
+				cells[column] = value
+			}
+			column++
+		}
+	}
+}
+
+func (s *xlsxSheetReader) readCell(start xml.StartElement) (any, error) {
+	var cell struct {
+		Value  *string `xml:"v"`
+		Inline *struct {
+			Text string `xml:"t"`
+			Runs []struct {
+				Text string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"is"`
+	}
+	if err := s.dec.DecodeElement(&cell, &start); err != nil {
+		return nil, err
+	}
+
+	cellType := xmlAttr(start, "t")
+	if cellType == "inlineStr" {
+		if cell.Inline == nil {
+			return nil, nil
+		}
+		text := cell.Inline.Text
+		for _, run := range cell.Inline.Runs {
+			text += run.Text
+		}
+		return nonEmptyCell(text), nil
+	}
+	if cell.Value == nil {
+		return nil, nil
+	}
+	value := *cell.Value
+
+	switch cellType {
+	case "s":
+		index, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || index < 0 || index >= len(s.shared) {
+			return nil, fmt.Errorf("invalid shared string index %q", value)
+		}
+		return nonEmptyCell(s.shared[index]), nil
+	case "b":
+		return strings.TrimSpace(value) == "1", nil
+	case "e":
+		return nil, nil
+	case "", "n":
+		style, _ := strconv.Atoi(xmlAttr(start, "s"))
+		return numericCellText(value, s.padding[style]), nil
+	default:
+		return nonEmptyCell(value), nil
+	}
+}
+
+func numericCellText(value string, width int) string {
+	value = strings.TrimSpace(value)
+	if number, err := strconv.ParseFloat(value, 64); err == nil && strings.ContainsAny(value, ".eE") {
+		value = strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	if width > 0 && !strings.ContainsAny(value, ".-") && len(value) < width {
+		value = strings.Repeat("0", width-len(value)) + value
+	}
+	return value
+}
+
+func nonEmptyCell(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func setRecordPath(record map[string]any, parts []string, value any) error {
+	var current any = record
+	for i, part := range parts {
+		last := i == len(parts)-1
+		switch node := current.(type) {
+		case map[string]any:
+			if last {
+				if _, exists := node[part]; exists {
+					return errors.New("column conflicts with another column")
+				}
+				node[part] = value
+				return nil
+			}
+			child, exists := node[part]
+			if !exists {
+				child = newRecordContainer(parts[i+1])
+				node[part] = child
+			}
+			current = child
+		case *[]any:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= xlsxMaxArrayIndex {
+				return fmt.Errorf("invalid array index %q", part)
+			}
+			for len(*node) <= index {
+				*node = append(*node, nil)
+			}
+			if last {
+				if (*node)[index] != nil {
+					return errors.New("column conflicts with another column")
+				}
+				(*node)[index] = value
+				return nil
+			}
+			if (*node)[index] == nil {
+				(*node)[index] = newRecordContainer(parts[i+1])
+			}
+			current = (*node)[index]
+		default:
+			return errors.New("column conflicts with another column")
+		}
+	}
+	return nil
+}
+
+func newRecordContainer(next string) any {
+	if _, err := strconv.Atoi(next); err == nil {
+		return &[]any{}
+	}
+	return map[string]any{}
+}
+
+func compactRecordArrays(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		for key, child := range node {
+			node[key] = compactRecordArrays(child)
+		}
+		return node
+	case *[]any:
+		items := make([]any, 0, len(*node))
+		for _, item := range *node {
+			if item != nil {
+				items = append(items, compactRecordArrays(item))
+			}
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+func readSharedStrings(archive *zip.Reader, name string) ([]string, error) {
+	file, err := archive.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open xlsx shared strings: %w", err)
+	}
+	defer file.Close()
+
+	var shared []string
+	var text strings.Builder
+	depth, phonetic := 0, 0
+	dec := xml.NewDecoder(file)
+	for {
+		token, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return shared, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read xlsx shared strings: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			switch element.Name.Local {
+			case "si":
+				depth++
+				text.Reset()
+			case "rPh":
+				phonetic++
+			case "t":
+				if depth > 0 && phonetic == 0 {
+					var value string
+					if err := dec.DecodeElement(&value, &element); err != nil {
+						return nil, fmt.Errorf("read xlsx shared strings: %w", err)
+					}
+					text.WriteString(value)
+				}
+			}
+		case xml.EndElement:
+			switch element.Name.Local {
+			case "si":
+				depth--
+				shared = append(shared, text.String())
+			case "rPh":
+				phonetic--
+			}
+		}
+	}
+}
+
+func readZeroPaddedStyles(archive *zip.Reader, name string) (map[int]int, error) {
+	var styles xlsxStyles
+	if err := unmarshalZipXML(archive, name, &styles); err != nil {
+		return nil, err
+	}
+
+	widths := make(map[int]int)
+	for _, format := range styles.NumFmts {
+		if code := strings.Trim(format.Code, `"`); code != "" && strings.Trim(code, "0") == "" {
+			widths[format.ID] = len(code)
+		}
+	}
+
+	padding := make(map[int]int)
+	for index, xf := range styles.CellXfs {
+		if width, ok := widths[xf.NumFmtID]; ok {
+			padding[index] = width
+		}
+	}
+	return padding, nil
+}
+
+func unmarshalZipXML(archive *zip.Reader, name string, target any) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("open xlsx part %s: %w", name, err)
+	}
+	defer file.Close()
+
+	if err := xml.NewDecoder(file).Decode(target); err != nil {
+		return fmt.Errorf("decode xlsx part %s: %w", name, err)
+	}
+	return nil
+}
+
+func xlsxPartPath(target string) string {
+	if strings.HasPrefix(target, "/") {
+		return strings.TrimPrefix(target, "/")
+	}
+	return path.Join("xl", target)
+}
+
+func xlsxColumnIndex(ref string) (int, error) {
+	column := 0
+	letters := 0
+	for _, r := range ref {
+		upper := r &^ 0x20
+		if upper < 'A' || upper > 'Z' {
+			break
+		}
+		column = column*26 + int(upper-'A'+1)
+		letters++
+	}
+	if letters == 0 || letters > 3 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return column - 1, nil
+}
+
+func xmlAttr(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Space == "" && attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func xlsxReaderAt(r io.Reader) (io.ReaderAt, int64, func() error, error) {
+	if file, ok := r.(*os.File); ok {
+		info, err := file.Stat()
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("stat xlsx source: %w", err)
+		}
+		return file, info.Size(), func() error { return nil }, nil
+	}
+
+	spool, err := os.CreateTemp("", "import-*.xlsx")
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("create xlsx spool file: %w", err)
+	}
+	cleanup := func() error {
+		closeErr := spool.Close()
+		return errors.Join(closeErr, os.Remove(spool.Name()))
+	}
+
+	size, err := io.Copy(spool, r)
+	if err != nil {
+		_ = cleanup()
+		return nil, 0, nil, fmt.Errorf("spool xlsx source: %w", err)
+	}
+	return spool, size, cleanup, nil
+}
@@ -0,0 +1,302 @@
+package user
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const (
+	defaultXMLRecordPath  = "/users/user"
+	defaultXMLAddressPath = "addresses/address"
+)
+
+type xmlNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	text     strings.Builder
+	children []*xmlNode
+}
+
+type xmlStep struct {
+	space     string
+	local     string
+	attribute bool
+}
+
+type xmlRecordReader struct {
+	dec           *xml.Decoder
+	offsets       *sourceOffsets
+	recordPath    []xmlStep
+	fields        map[string][]xmlStep
+	addressPath   []xmlStep
+	addressFields map[string][]xmlStep
+	stack         []xml.Name
+	row           int64
+}
+
+func newXMLRecordReader(source io.Reader, options domain.ImportOptions) (RecordReader, error) {
+	text, offsets, err := newTextReader(source, options.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("detect source encoding: %w", err)
+	}
+	return newXMLRecordDecoder(text, offsets, options.XML, options.Encoding), nil
+}
+
+func newXMLRecordDecoder(r io.Reader, offsets *sourceOffsets, mapping domain.XMLMapping, encoding domain.ImportEncoding) *xmlRecordReader {
+	recordPath := firstNonEmpty(mapping.RecordPath, defaultXMLRecordPath)
+	addressPath := firstNonEmpty(mapping.AddressPath, defaultXMLAddressPath)
+
+	fields := make(map[string][]xmlStep)
+	for field := range rawUserFields {
+		if field != "addresses" && field != "attributes" {
+			fields[field] = parseXMLSteps(field, mapping.Namespaces)
+		}
+	}
+	for field, path := range mapping.Fields {
+		fields[field] = parseXMLSteps(path, mapping.Namespaces)
+	}
+
+	addressFields := make(map[string][]xmlStep)
+	for field := range rawAddressFields {
+		addressFields[field] = parseXMLSteps(field, mapping.Namespaces)
+	}
+	for field, path := range mapping.AddressFields {
+		addressFields[field] = parseXMLSteps(path, mapping.Namespaces)
+	}
+
+	reader := &xmlRecordReader{
+		dec:           xml.NewDecoder(r),
+		offsets:       offsets,
+		recordPath:    parseXMLSteps(strings.TrimPrefix(recordPath, "/"), mapping.Namespaces),
+		fields:        fields,
+		addressPath:   parseXMLSteps(addressPath, mapping.Namespaces),
+		addressFields: addressFields,
+	}
+	reader.dec.CharsetReader = func(label string, input io.Reader) (io.Reader, error) {
+		if encoding != domain.ImportEncodingAuto {
+			return input, nil
+		}
+		// The declared charset applies from the end of the declaration on.
+		at := reader.dec.InputOffset()
+		text, offsets, err := xmlCharsetReader(label, input, at, reader.offsets.Source(at))
+		if err != nil {
+			return nil, err
+		}
+		if offsets != nil {
+			reader.offsets = offsets
+		}
+		return text, nil
+	}
+	return reader
+}
+
+func (r *xmlRecordReader) Next() (Record, error) {
+	for {
+		offset := r.dec.InputOffset()
+		line, _ := r.dec.InputPos()
+		token, err := r.dec.Token()
+		if errors.Is(err, io.EOF) {
+			if len(r.stack) > 0 {
+				return Record{}, fmt.Errorf("read xml: %w", io.ErrUnexpectedEOF)
+			}
+			return Record{}, io.EOF
+		}
+		if err != nil {
+			return Record{}, fmt.Errorf("read xml: %w", err)
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			r.stack = append(r.stack, element.Name)
+			if !matchesXMLPath(r.stack, r.recordPath) {
+				continue
+			}
+			r.stack = r.stack[:len(r.stack)-1]
+
+			node, err := readXMLNode(r.dec, element)
+			if err != nil {
+				return Record{}, fmt.Errorf("read xml record: %w", err)
+			}
+			r.row++
+			return Record{
+				Fields:  r.record(node),
+				Locator: domain.RecordLocator{Row: r.row, Line: int64(line), Offset: r.offsets.Source(offset)},
+			}, nil
+		case xml.EndElement:
+			r.stack = r.stack[:len(r.stack)-1]
+		}
+	}
+}
+
+func (r *xmlRecordReader) Metadata() map[string]any {
+	return nil
+}
+
+func (r *xmlRecordReader) Close() error {
+	return nil
+}
+
+func (r *xmlRecordReader) record(node *xmlNode) map[string]any {
+	record := make(map[string]any)
+	consumed := map[string]struct{}{r.addressPath[0].local: {}}
+	for field, steps := range r.fields {
+		if value, ok := node.value(steps); ok {
+			record[field] = value
+		}
+		if !steps[0].attribute {
+			consumed[steps[0].local] = struct{}{}
+		}
+	}
+
+	for _, child := range node.children {
+		if _, ok := consumed[child.name.Local]; ok || len(child.children) > 0 {
+			continue
+		}
+		if _, exists := record[child.name.Local]; !exists {
+			record[child.name.Local] = strings.TrimSpace(child.text.String())
+		}
+	}
+
+	addressNodes := node.find(r.addressPath)
+	if len(addressNodes) > 0 {
+		addresses := make([]any, 0, len(addressNodes))
+		for _, addressNode := range addressNodes {
+			address := make(map[string]any)
+			for field, steps := range r.addressFields {
+				value, ok := addressNode.value(steps)
+				if !ok {
+					continue
+				}
+				if field == "is_default" {
+					if parsed, err := strconv.ParseBool(value); err == nil {
+						address[field] = parsed
+						continue
+					}
+				}
+				address[field] = value
+			}
+			addresses = append(addresses, address)
+		}
+		record["addresses"] = addresses
+	}
+	return record
+}
+
+func (n *xmlNode) find(steps []xmlStep) []*xmlNode {
+	if len(steps) == 0 {
+		return []*xmlNode{n}
+	}
+
+	var found []*xmlNode
+	for _, child := range n.children {
+		if steps[0].matches(child.name) {
+			found = append(found, child.find(steps[1:])...)
+		}
+	}
+	return found
+}
+
+func (n *xmlNode) value(steps []xmlStep) (string, bool) {
+	last := steps[len(steps)-1]
+	if !last.attribute {
+		nodes := n.find(steps)
+		if len(nodes) == 0 {
+			return "", false
+		}
+		return strings.TrimSpace(nodes[0].text.String()), true
+	}
+
+	for _, target := range n.find(steps[:len(steps)-1]) {
+		for _, attr := range target.attrs {
+			if last.matches(attr.Name) {
+				return strings.TrimSpace(attr.Value), true
+			}
+		}
+	}
+	return "", false
+}
+
+func (s xmlStep) matches(name xml.Name) bool {
+	return s.local == name.Local && (s.space == "" || s.space == name.Space)
+}
+
+func readXMLNode(dec *xml.Decoder, start xml.StartElement) (*xmlNode, error) {
+	root := &xmlNode{name: start.Name, attrs: start.Attr}
+	stack := []*xmlNode{root}
+	for len(stack) > 0 {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		current := stack[len(stack)-1]
+		switch element := token.(type) {
+		case xml.StartElement:
+			child := &xmlNode{name: element.Name, attrs: element.Attr}
+			current.children = append(current.children, child)
+			stack = append(stack, child)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			current.text.Write(element)
+		}
+	}
+	return root, nil
+}
+
+func matchesXMLPath(stack []xml.Name, steps []xmlStep) bool {
+	if len(stack) != len(steps) {
+		return false
+	}
+	for i, step := range steps {
+		if !step.matches(stack[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseXMLSteps(path string, namespaces map[string]string) []xmlStep {
+	segments := strings.Split(path, "/")
+	steps := make([]xmlStep, 0, len(segments))
+	for _, segment := range segments {
+		step := xmlStep{local: segment}
+		if strings.HasPrefix(segment, "@") {
+			step.attribute = true
+			step.local = segment[1:]
+		}
+		if prefix, local, ok := strings.Cut(step.local, ":"); ok {
+			step.space = namespaces[prefix]
+			step.local = local
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// xmlCharsetReader decodes input, which starts at offset decoded of the text
+// and raw of the source, from the charset of the XML declaration. It returns
+// nil offsets when input is already UTF-8.
+func xmlCharsetReader(label string, input io.Reader, decoded, raw int64) (io.Reader, *sourceOffsets, error) {
+	switch strings.ToLower(label) {
+	case "utf-16", "utf-16le", "utf-16be", "us-ascii", "ascii":
+		return input, nil, nil
+	}
+
+	encoding, err := domain.ParseImportEncoding(label)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unsupported xml encoding %q", label)
+	}
+	if encoding == domain.ImportEncodingUTF8 {
+		return input, nil, nil
+	}
+	text, offsets := decodeText(bufio.NewReader(input), encoding, decoded, raw)
+	return text, offsets, nil
+}
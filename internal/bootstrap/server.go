@@ -18,13 +18,27 @@ func NewHTTPServer(db *gorm.DB) *echo.Echo {
 	server.Use(middleware.BodyLimit("10M"))
 
 	importJobRepo := repository.NewImportJobRepository(db)
-	startImport := app.NewStartImportUsersFromJSON(importJobRepo)
+	importProfileRepo := repository.NewImportProfileRepository(db)
+	startImport := app.NewStartImportUsersFromJSON(importJobRepo, importProfileRepo)
 	importHandler := httpecho.NewImportHandler(startImport)
 	userQueryRepo := repository.NewUserQueryRepository(db)
 	getUserByID := app.NewGetUserByID(userQueryRepo)
-	userHandler := httpecho.NewUserHandler(getUserByID)
+	listUsers := app.NewListUsers(userQueryRepo)
+	userHandler := httpecho.NewUserHandler(getUserByID, listUsers)
 
-	httpecho.RegisterRoutes(server, importHandler, userHandler)
+	getImportJob := app.NewGetImportJob(importJobRepo)
+	listImportConflicts := app.NewListImportConflicts(importJobRepo)
+	importJobHandler := httpecho.NewImportJobHandler(getImportJob, listImportConflicts)
+
+	importProfileHandler := httpecho.NewImportProfileHandler(
+		app.NewCreateImportProfile(importProfileRepo),
+		app.NewGetImportProfile(importProfileRepo),
+		app.NewListImportProfiles(importProfileRepo),
+		app.NewUpdateImportProfile(importProfileRepo),
+		app.NewDeleteImportProfile(importProfileRepo),
+	)
+
+	httpecho.RegisterRoutes(server, importHandler, userHandler, importJobHandler, importProfileHandler)
 
 	server.GET("/healthz", func(c echo.Context) error {
 		return c.JSON(200, map[string]string{"status": "ok"})
@@ -0,0 +1,120 @@
+package user
+
+import "strings"
+
+const (
+	AddressIssueUnknownCountry     = "unknown_country"
+	AddressIssueInvalidPostalCode  = "invalid_postal_code"
+	AddressIssueUnknownSubdivision = "unknown_subdivision"
+	// AddressIssueSubdivisionNotChecked is reported when subdivision validation
+	// is on but no subdivisions are bundled for the address country.
+	AddressIssueSubdivisionNotChecked = "subdivision_not_checked"
+)
+
+type ValidationSeverity string
+
+const (
+	ValidationError ValidationSeverity = "error"
+	ValidationWarn  ValidationSeverity = "warn"
+	ValidationOff   ValidationSeverity = "off"
+)
+
+func ParseValidationSeverity(value string) (ValidationSeverity, error) {
+	switch severity := ValidationSeverity(strings.ToLower(strings.TrimSpace(value))); severity {
+	case "", ValidationError, ValidationWarn, ValidationOff:
+		return severity, nil
+	default:
+		return "", ErrInvalidValidationSeverity
+	}
+}
+
+type AddressValidationRules struct {
+	Country     ValidationSeverity
+	PostalCode  ValidationSeverity
+	Subdivision ValidationSeverity
+	// Normalize stores recognised countries as alpha-2 and subdivisions as their
+	// code. Without it the address keeps the values from the source.
+	Normalize bool
+}
+
+func (r AddressValidationRules) WithDefaults() AddressValidationRules {
+	if r.Country == "" {
+		r.Country = ValidationWarn
+	}
+	if r.PostalCode == "" {
+		r.PostalCode = ValidationWarn
+	}
+	if r.Subdivision == "" {
+		r.Subdivision = ValidationOff
+	}
+	return r
+}
+
+type AddressIssue struct {
+	AddressIndex int
+	Reason       string
+}
+
+func (r AddressValidationRules) Validate(address Address) (Address, []string, error) {
+	r = r.WithDefaults()
+	if r.Country == ValidationOff && r.PostalCode == ValidationOff && r.Subdivision == ValidationOff {
+		return address, nil, nil
+	}
+
+	var warnings []string
+	country, ok := LookupCountry(address.Country)
+	if !ok {
+		if r.Country == ValidationError {
+			return Address{}, nil, ErrInvalidCountry
+		}
+		if r.Country == ValidationWarn {
+			warnings = append(warnings, AddressIssueUnknownCountry)
+		}
+		return address, warnings, nil
+	}
+	if r.Normalize && r.Country != ValidationOff {
+		address.Country = country.Alpha2
+	}
+
+	if r.PostalCode != ValidationOff && !country.ValidPostalCode(address.ZipCode) {
+		if r.PostalCode == ValidationError {
+			return Address{}, nil, ErrInvalidPostalCode
+		}
+		warnings = append(warnings, AddressIssueInvalidPostalCode)
+	}
+
+	if r.Subdivision != ValidationOff {
+		code, known, found := LookupSubdivision(country.Alpha2, address.State)
+		switch {
+		case found:
+			if r.Normalize {
+				address.State = code
+			}
+		case !known:
+			warnings = append(warnings, AddressIssueSubdivisionNotChecked)
+		case r.Subdivision == ValidationError:
+			return Address{}, nil, ErrInvalidSubdivision
+		default:
+			warnings = append(warnings, AddressIssueUnknownSubdivision)
+		}
+	}
+
+	return address, warnings, nil
+}
+
+func (u User) ValidateAddresses(rules AddressValidationRules) (User, []AddressIssue, error) {
+	var issues []AddressIssue
+	addresses := make([]Address, 0, len(u.Addresses))
+	for i, address := range u.Addresses {
+		validated, warnings, err := rules.Validate(address)
+		if err != nil {
+			return User{}, nil, err
+		}
+		for _, reason := range warnings {
+			issues = append(issues, AddressIssue{AddressIndex: i, Reason: reason})
+		}
+		addresses = append(addresses, validated)
+	}
+	u.Addresses = addresses
+	return u, issues, nil
+}
@@ -0,0 +1,139 @@
+package user_test
+
+import (
+	"reflect"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestLookupCountry(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"France", "FR", "fra", " france "} {
+		country, ok := domain.LookupCountry(input)
+		if !ok {
+			t.Fatalf("lookup %q: expected country", input)
+		}
+		if country.Alpha2 != "FR" || country.Alpha3 != "FRA" || country.CallingCode != "33" {
+			t.Fatalf("lookup %q: unexpected country %+v", input, country)
+		}
+	}
+
+	if _, ok := domain.LookupCountry("Atlantis"); ok {
+		t.Fatal("expected unknown country")
+	}
+}
+
+func TestAddressValidationRulesValidate(t *testing.T) {
+	t.Parallel()
+
+	address := domain.Address{Street: "1 Main", City: "Austin", State: "Texas", ZipCode: "78701", Country: "United States"}
+
+	got, warnings, err := domain.AddressValidationRules{Subdivision: domain.ValidationError}.Validate(address)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+	if got != address {
+		t.Fatalf("expected address kept as given without normalize, got %+v", got)
+	}
+
+	got, _, err = domain.AddressValidationRules{Subdivision: domain.ValidationError, Normalize: true}.Validate(address)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Country != "US" || got.State != "TX" {
+		t.Fatalf("expected canonical codes, got %+v", got)
+	}
+
+	got, _, err = domain.AddressValidationRules{Country: domain.ValidationOff, PostalCode: domain.ValidationOff}.Validate(address)
+	if err != nil || got != address {
+		t.Fatalf("expected address untouched when rules are off, got %+v, %v", got, err)
+	}
+}
+
+func TestAddressValidationRulesSeverities(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		rules        domain.AddressValidationRules
+		address      domain.Address
+		wantWarnings []string
+		wantErr      error
+	}{
+		{
+			name:         "unknown country warns by default",
+			address:      domain.Address{Country: "Atlantis", ZipCode: "1"},
+			wantWarnings: []string{domain.AddressIssueUnknownCountry},
+		},
+		{
+			name:    "unknown country error",
+			rules:   domain.AddressValidationRules{Country: domain.ValidationError},
+			address: domain.Address{Country: "Atlantis"},
+			wantErr: domain.ErrInvalidCountry,
+		},
+		{
+			name:         "postal code warns by default",
+			address:      domain.Address{Country: "GB", ZipCode: "12345"},
+			wantWarnings: []string{domain.AddressIssueInvalidPostalCode},
+		},
+		{
+			name:    "postal code error",
+			rules:   domain.AddressValidationRules{PostalCode: domain.ValidationError},
+			address: domain.Address{Country: "CA", ZipCode: "12345"},
+			wantErr: domain.ErrInvalidPostalCode,
+		},
+		{
+			name:    "postal code valid",
+			rules:   domain.AddressValidationRules{PostalCode: domain.ValidationError},
+			address: domain.Address{Country: "CA", ZipCode: "k1a 0b1"},
+		},
+		{
+			name:    "postal code off",
+			rules:   domain.AddressValidationRules{PostalCode: domain.ValidationOff},
+			address: domain.Address{Country: "GB", ZipCode: "12345"},
+		},
+		{
+			name:         "subdivision warn",
+			rules:        domain.AddressValidationRules{Subdivision: domain.ValidationWarn},
+			address:      domain.Address{Country: "USA", State: "Atlantis", ZipCode: "78701"},
+			wantWarnings: []string{domain.AddressIssueUnknownSubdivision},
+		},
+		{
+			name:    "subdivision error",
+			rules:   domain.AddressValidationRules{Subdivision: domain.ValidationError},
+			address: domain.Address{Country: "USA", State: "Atlantis", ZipCode: "78701"},
+			wantErr: domain.ErrInvalidSubdivision,
+		},
+		{
+			name:         "subdivision without dataset",
+			rules:        domain.AddressValidationRules{Subdivision: domain.ValidationError},
+			address:      domain.Address{Country: "France", State: "Arkansas", ZipCode: "58532"},
+			wantWarnings: []string{domain.AddressIssueSubdivisionNotChecked},
+		},
+	}
+	for _, tc := range cases {
+		_, warnings, err := tc.rules.Validate(tc.address)
+		if err != tc.wantErr {
+			t.Fatalf("%s: expected error %v, got %v", tc.name, tc.wantErr, err)
+		}
+		if !reflect.DeepEqual(warnings, tc.wantWarnings) {
+			t.Fatalf("%s: expected warnings %v, got %v", tc.name, tc.wantWarnings, warnings)
+		}
+	}
+}
+
+func TestParseValidationSeverity(t *testing.T) {
+	t.Parallel()
+
+	if got, err := domain.ParseValidationSeverity(" WARN "); err != nil || got != domain.ValidationWarn {
+		t.Fatalf("expected warn, got %q, %v", got, err)
+	}
+	if _, err := domain.ParseValidationSeverity("strict"); err != domain.ErrInvalidValidationSeverity {
+		t.Fatalf("expected ErrInvalidValidationSeverity, got %v", err)
+	}
+}
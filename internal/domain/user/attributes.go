@@ -0,0 +1,53 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+)
+
+var attributeKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,64}$`)
+
+type AttributeStrategy string
+
+const (
+	AttributeStrategyMerge   AttributeStrategy = "merge"
+	AttributeStrategyReplace AttributeStrategy = "replace"
+)
+
+func ParseAttributeStrategy(value string) (AttributeStrategy, error) {
+	switch strategy := AttributeStrategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case "":
+		return AttributeStrategyMerge, nil
+	case AttributeStrategyMerge, AttributeStrategyReplace:
+		return strategy, nil
+	default:
+		return "", ErrInvalidAttributeStrategy
+	}
+}
+
+func ParseAttributeKeys(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		key := strings.TrimSpace(value)
+		if !attributeKeyPattern.MatchString(key) {
+			return nil, ErrInvalidAttributeKey
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+type UserFilter struct {
+	Attributes map[string]string
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,47 @@
+package user_test
+
+import (
+	"reflect"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestParseAttributeStrategy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]domain.AttributeStrategy{
+		"":          domain.AttributeStrategyMerge,
+		"merge":     domain.AttributeStrategyMerge,
+		" REPLACE ": domain.AttributeStrategyReplace,
+	}
+	for input, want := range cases {
+		got, err := domain.ParseAttributeStrategy(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseAttributeStrategy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := domain.ParseAttributeStrategy("append"); err != domain.ErrInvalidAttributeStrategy {
+		t.Fatalf("expected ErrInvalidAttributeStrategy, got %v", err)
+	}
+}
+
+func TestParseAttributeKeys(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.ParseAttributeKeys([]string{" department ", "costCenter", "department", "employee.type"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	want := []string{"department", "costCenter", "employee.type"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	for _, invalid := range []string{"", "cost center", "a/b"} {
+		if _, err := domain.ParseAttributeKeys([]string{invalid}); err != domain.ErrInvalidAttributeKey {
+			t.Fatalf("ParseAttributeKeys(%q): expected ErrInvalidAttributeKey, got %v", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package user
+
+import (
+	_ "embed"
+	"regexp"
+	"strings"
+)
+
+//go:embed iso3166_1.tsv
+var iso3166CountriesData string
+
+//go:embed iso3166_2.tsv
+var iso3166SubdivisionsData string
+
+type Country struct {
+	Alpha2      string
+	Alpha3      string
+	Name        string
+	CallingCode string
+
+	postalCode *regexp.Regexp
+}
+
+func (c Country) ValidPostalCode(value string) bool {
+	if c.postalCode == nil {
+		return true
+	}
+	return c.postalCode.MatchString(strings.ToUpper(strings.TrimSpace(value)))
+}
+
+var (
+	countriesByKey       = make(map[string]Country)
+	callingCodes         = make(map[string]struct{})
+	subdivisionsByKey    = make(map[string]string)
+	subdivisionCountries = make(map[string]struct{})
+)
+
+func init() {
+	for _, line := range strings.Split(strings.TrimSpace(iso3166CountriesData), "\n") {
+		fields := strings.Split(line, "\t")
+		names := strings.Split(fields[4], "|")
+		country := Country{
+			Alpha2:      fields[0],
+			Alpha3:      fields[1],
+			Name:        names[0],
+			CallingCode: fields[2],
+		}
+		if fields[3] != "" {
+			country.postalCode = regexp.MustCompile(`^(` + fields[3] + `)$`)
+		}
+		if country.CallingCode != "" {
+			callingCodes[country.CallingCode] = struct{}{}
+		}
+		for _, key := range append([]string{country.Alpha2, country.Alpha3}, names...) {
+			countriesByKey[strings.ToLower(key)] = country
+		}
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(iso3166SubdivisionsData), "\n") {
+		fields := strings.Split(line, "\t")
+		countryCode, code, name := fields[0], fields[1], fields[2]
+		subdivisionCountries[countryCode] = struct{}{}
+		for _, key := range []string{code, countryCode + "-" + code, name} {
+			subdivisionsByKey[countryCode+"/"+strings.ToLower(key)] = code
+		}
+	}
+}
+
+func LookupCountry(value string) (Country, bool) {
+	country, ok := countriesByKey[strings.ToLower(strings.TrimSpace(value))]
+	return country, ok
+}
+
+func LookupSubdivision(countryAlpha2, value string) (code string, known, ok bool) {
+	if _, known = subdivisionCountries[countryAlpha2]; !known {
+		return "", false, false
+	}
+	code, ok = subdivisionsByKey[countryAlpha2+"/"+strings.ToLower(strings.TrimSpace(value))]
+	return code, true, ok
+}
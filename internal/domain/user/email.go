@@ -0,0 +1,86 @@
+package user
+
+import (
+	"net/mail"
+	"strings"
+)
+
+type EmailProviderRule struct {
+	Domains         []string
+	CanonicalDomain string
+	StripDots       bool
+	StripPlusTag    bool
+}
+
+var GmailEmailRule = EmailProviderRule{
+	Domains:         []string{"gmail.com", "googlemail.com"},
+	CanonicalDomain: "gmail.com",
+	StripDots:       true,
+	StripPlusTag:    true,
+}
+
+var emailProviderRules = map[string]EmailProviderRule{
+	"gmail": GmailEmailRule,
+}
+
+func ParseEmailProviderRules(value string) ([]EmailProviderRule, error) {
+	var rules []EmailProviderRule
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		rule, ok := emailProviderRules[name]
+		if !ok {
+			return nil, ErrInvalidEmailProviderRule
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+type EmailNormalizer struct {
+	rules map[string]EmailProviderRule
+}
+
+func NewEmailNormalizer(rules ...EmailProviderRule) EmailNormalizer {
+	byDomain := make(map[string]EmailProviderRule)
+	for _, rule := range rules {
+		for _, domain := range rule.Domains {
+			byDomain[strings.ToLower(domain)] = rule
+		}
+	}
+	return EmailNormalizer{rules: byDomain}
+}
+
+func (n EmailNormalizer) Normalize(email string) (address, key string, err error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return "", "", ErrInvalidEmail
+	}
+
+	at := strings.LastIndex(parsed.Address, "@")
+	if at <= 0 || at == len(parsed.Address)-1 {
+		return "", "", ErrInvalidEmail
+	}
+	local := parsed.Address[:at]
+	domain := strings.ToLower(parsed.Address[at+1:])
+	address = local + "@" + domain
+
+	keyLocal := strings.ToLower(local)
+	if rule, ok := n.rules[domain]; ok {
+		if rule.StripPlusTag {
+			if plus := strings.Index(keyLocal, "+"); plus > 0 {
+				keyLocal = keyLocal[:plus]
+			}
+		}
+		if rule.StripDots {
+			keyLocal = strings.ReplaceAll(keyLocal, ".", "")
+		}
+		if rule.CanonicalDomain != "" {
+			domain = strings.ToLower(rule.CanonicalDomain)
+		}
+	}
+
+	return address, keyLocal + "@" + domain, nil
+}
@@ -0,0 +1,76 @@
+package user_test
+
+import (
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestEmailNormalizerDefault(t *testing.T) {
+	t.Parallel()
+
+	address, key, err := domain.EmailNormalizer{}.Normalize("  Alice.Smith+news@Example.COM ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if address != "Alice.Smith+news@example.com" {
+		t.Fatalf("unexpected address: %q", address)
+	}
+	if key != "alice.smith+news@example.com" {
+		t.Fatalf("unexpected key: %q", key)
+	}
+}
+
+func TestEmailNormalizerGmailRule(t *testing.T) {
+	t.Parallel()
+
+	normalizer := domain.NewEmailNormalizer(domain.GmailEmailRule)
+
+	for _, input := range []string{"Alice.Smith+news@gmail.com", "alicesmith@GoogleMail.com", "a.l.i.c.e.smith@gmail.com"} {
+		_, key, err := normalizer.Normalize(input)
+		if err != nil {
+			t.Fatalf("normalize %q: expected no error, got %v", input, err)
+		}
+		if key != "alicesmith@gmail.com" {
+			t.Fatalf("normalize %q: unexpected key %q", input, key)
+		}
+	}
+
+	_, key, err := normalizer.Normalize("alice.smith+news@example.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if key != "alice.smith+news@example.com" {
+		t.Fatalf("expected other providers to keep dots and tags, got %q", key)
+	}
+}
+
+func TestEmailNormalizerInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "alice", "alice@", "@example.com"} {
+		if _, _, err := (domain.EmailNormalizer{}).Normalize(input); err != domain.ErrInvalidEmail {
+			t.Fatalf("normalize %q: expected ErrInvalidEmail, got %v", input, err)
+		}
+	}
+}
+
+func TestParseEmailProviderRules(t *testing.T) {
+	t.Parallel()
+
+	rules, err := domain.ParseEmailProviderRules(" Gmail ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(rules) != 1 || rules[0].CanonicalDomain != "gmail.com" {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+
+	if rules, err := domain.ParseEmailProviderRules(""); err != nil || len(rules) != 0 {
+		t.Fatalf("expected no rules, got %+v, %v", rules, err)
+	}
+
+	if _, err := domain.ParseEmailProviderRules("gmail,yahoo"); err != domain.ErrInvalidEmailProviderRule {
+		t.Fatalf("expected ErrInvalidEmailProviderRule, got %v", err)
+	}
+}
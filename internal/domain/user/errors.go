@@ -5,5 +5,45 @@ import "errors"
 var (
 	ErrInvalidEmail   = errors.New("invalid email")
 	ErrInvalidAddress = errors.New("invalid address")
-	ErrUserNotFound   = errors.New("user not found")
+
+	ErrInvalidAddressType       = errors.New("invalid address type")
+	ErrMultipleDefaultAddresses = errors.New("multiple default addresses")
+	ErrUserNotFound             = errors.New("user not found")
+	ErrInvalidUUID              = errors.New("invalid uuid")
+
+	ErrInvalidEmailProviderRule = errors.New("invalid email provider rule")
+	ErrInvalidPhoneNumber       = errors.New("invalid phone number")
+	ErrInvalidCountry           = errors.New("invalid country")
+	ErrInvalidPostalCode        = errors.New("invalid postal code")
+	ErrInvalidSubdivision       = errors.New("invalid subdivision")
+	ErrValueTooLong             = errors.New("value too long")
+
+	ErrInvalidAddressStrategy   = errors.New("invalid address strategy")
+	ErrInvalidFieldUpdatePolicy = errors.New("invalid field update policy")
+	ErrInvalidSourceName        = errors.New("invalid source name")
+
+	ErrInvalidIdentityConflictPolicy = errors.New("invalid identity conflict policy")
+	ErrInvalidValidationSeverity     = errors.New("invalid validation severity")
+	ErrInvalidValidationRule         = errors.New("invalid validation rule")
+	ErrInvalidProfileName            = errors.New("invalid profile name")
+	ErrInvalidOversizePolicy         = errors.New("invalid oversize policy")
+	ErrInvalidPhoneRegion            = errors.New("invalid phone region")
+	ErrInvalidAttributeStrategy      = errors.New("invalid attribute strategy")
+	ErrInvalidAttributeKey           = errors.New("invalid attribute key")
+	ErrInvalidSchemaMode             = errors.New("invalid schema mode")
+	ErrInvalidImportFormat           = errors.New("invalid import format")
+	ErrInvalidImportEncoding         = errors.New("invalid import encoding")
+	ErrInvalidSheetName              = errors.New("invalid sheet name")
+	ErrInvalidXMLMapping             = errors.New("invalid xml mapping")
+	ErrInvalidFieldMapping           = errors.New("invalid field mapping")
+	ErrInvalidFieldTransform         = errors.New("invalid field transform")
+	ErrInvalidJSONPointer            = errors.New("invalid json pointer")
+	ErrInvalidMetadataPointer        = errors.New("invalid metadata pointer")
+	ErrInvalidChunkSize              = errors.New("invalid chunk size")
+	ErrInvalidChunkConcurrency       = errors.New("invalid chunk concurrency")
+	ErrInvalidPartitions             = errors.New("invalid partitions")
+	ErrInvalidMaxAttempts            = errors.New("invalid max attempts")
+	ErrImportProfileNotFound         = errors.New("import profile not found")
+	ErrImportProfileExists           = errors.New("import profile already exists")
+	ErrImportJobNotFound             = errors.New("import job not found")
 )
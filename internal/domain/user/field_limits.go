@@ -0,0 +1,89 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type OversizePolicy string
+
+const (
+	OversizeReject   OversizePolicy = "reject"
+	OversizeTruncate OversizePolicy = "truncate"
+)
+
+func ParseOversizePolicy(value string) (OversizePolicy, error) {
+	switch policy := OversizePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return OversizeReject, nil
+	case OversizeReject, OversizeTruncate:
+		return policy, nil
+	default:
+		return "", ErrInvalidOversizePolicy
+	}
+}
+
+type FieldLimits struct {
+	Name        int
+	Email       int
+	PhoneNumber int
+	Street      int
+	City        int
+	State       int
+	ZipCode     int
+	Country     int
+}
+
+type OversizeValue struct {
+	Field string
+	Limit int
+}
+
+func (l FieldLimits) Enforce(u User, policy OversizePolicy) (User, []OversizeValue, error) {
+	var oversize []OversizeValue
+	check := func(field string, value *string, limit int, truncatable bool) bool {
+		if limit <= 0 || utf8.RuneCountInString(*value) <= limit {
+			return true
+		}
+		oversize = append(oversize, OversizeValue{Field: field, Limit: limit})
+		if policy != OversizeTruncate || !truncatable {
+			return false
+		}
+		*value = truncateRunes(*value, limit)
+		return true
+	}
+
+	ok := check(RuleFieldName, &u.Name, l.Name, true)
+	ok = check(RuleFieldEmail, &u.Email, l.Email, false) && ok
+	ok = check(RuleFieldPhoneNumber, &u.PhoneNumber, l.PhoneNumber, false) && ok
+
+	addresses := make([]Address, len(u.Addresses))
+	copy(addresses, u.Addresses)
+	for i := range addresses {
+		address := &addresses[i]
+		prefix := fmt.Sprintf("addresses[%d].", i)
+		ok = check(prefix+"street", &address.Street, l.Street, true) && ok
+		ok = check(prefix+"city", &address.City, l.City, true) && ok
+		ok = check(prefix+"state", &address.State, l.State, true) && ok
+		ok = check(prefix+"zip_code", &address.ZipCode, l.ZipCode, true) && ok
+		ok = check(prefix+"country", &address.Country, l.Country, true) && ok
+	}
+	u.Addresses = addresses
+
+	if !ok {
+		return User{}, oversize, ErrValueTooLong
+	}
+	return u, oversize, nil
+}
+
+func truncateRunes(value string, limit int) string {
+	count := 0
+	for i := range value {
+		if count == limit {
+			return value[:i]
+		}
+		count++
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+package user_test
+
+import (
+	"reflect"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestParseOversizePolicy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]domain.OversizePolicy{
+		"":           domain.OversizeReject,
+		"reject":     domain.OversizeReject,
+		" Truncate ": domain.OversizeTruncate,
+	}
+	for input, want := range cases {
+		got, err := domain.ParseOversizePolicy(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseOversizePolicy(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := domain.ParseOversizePolicy("ignore"); err != domain.ErrInvalidOversizePolicy {
+		t.Fatalf("expected ErrInvalidOversizePolicy, got %v", err)
+	}
+}
+
+func TestFieldLimitsEnforce(t *testing.T) {
+	t.Parallel()
+
+	limits := domain.FieldLimits{Name: 5, Email: 20, PhoneNumber: 16, Street: 4, City: 10, State: 10, ZipCode: 5, Country: 2}
+	u := domain.User{
+		Name:        "Zoë Zimmermann",
+		Email:       "zoe@example.com",
+		PhoneNumber: "+15125550100",
+		Addresses:   []domain.Address{{Street: "1 Main St", City: "Austin", State: "TX", ZipCode: "78701", Country: "US"}},
+	}
+
+	_, oversize, err := limits.Enforce(u, domain.OversizeReject)
+	if err != domain.ErrValueTooLong {
+		t.Fatalf("expected ErrValueTooLong, got %v", err)
+	}
+	want := []domain.OversizeValue{{Field: "name", Limit: 5}, {Field: "addresses[0].street", Limit: 4}}
+	if !reflect.DeepEqual(oversize, want) {
+		t.Fatalf("expected %+v, got %+v", want, oversize)
+	}
+
+	truncated, oversize, err := limits.Enforce(u, domain.OversizeTruncate)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(oversize, want) {
+		t.Fatalf("expected %+v, got %+v", want, oversize)
+	}
+	if truncated.Name != "Zoë Z" || truncated.Addresses[0].Street != "1 Ma" {
+		t.Fatalf("unexpected truncation: %q, %q", truncated.Name, truncated.Addresses[0].Street)
+	}
+	if u.Addresses[0].Street != "1 Main St" {
+		t.Fatal("expected original addresses to be left untouched")
+	}
+
+	u.Email = "a-very-long-address@example.com"
+	if _, _, err := limits.Enforce(u, domain.OversizeTruncate); err != domain.ErrValueTooLong {
+		t.Fatalf("expected emails to never be truncated, got %v", err)
+	}
+
+	if _, oversize, err := (domain.FieldLimits{}).Enforce(u, domain.OversizeReject); err != nil || len(oversize) != 0 {
+		t.Fatalf("expected zero limits to be unlimited, got %+v, %v", oversize, err)
+	}
+}
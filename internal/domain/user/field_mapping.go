@@ -0,0 +1,309 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const maxFieldMappingSourceLength = 256
+
+func ParseFieldMapping(mapping map[string]string) (map[string]string, error) {
+	if len(mapping) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(mapping))
+	targets := make(map[string]struct{}, len(mapping))
+	for source, target := range mapping {
+		source = strings.TrimSpace(source)
+		if source == "" || utf8.RuneCountInString(source) > maxFieldMappingSourceLength {
+			return nil, ErrInvalidFieldMapping
+		}
+		if _, duplicate := parsed[source]; duplicate {
+			return nil, ErrInvalidFieldMapping
+		}
+		target = strings.TrimSpace(target)
+		if !attributeKeyPattern.MatchString(target) {
+			return nil, ErrInvalidFieldMapping
+		}
+		if _, duplicate := targets[target]; duplicate {
+			return nil, ErrInvalidFieldMapping
+		}
+		targets[target] = struct{}{}
+		parsed[source] = target
+	}
+	return parsed, nil
+}
+
+type FieldCase string
+
+const (
+	FieldCaseLower FieldCase = "lower"
+	FieldCaseUpper FieldCase = "upper"
+	FieldCaseTitle FieldCase = "title"
+)
+
+type FieldTransform struct {
+	Target    string
+	Source    string
+	Sources   []string
+	Separator string
+	Split     string
+	Index     int
+	Trim      bool
+	Case      FieldCase
+	Lookup    map[string]string
+	Default   *string
+}
+
+func ParseFieldTransforms(transforms []FieldTransform) ([]FieldTransform, error) {
+	if len(transforms) == 0 {
+		return nil, nil
+	}
+
+	parsed := make([]FieldTransform, 0, len(transforms))
+	targets := make(map[string]struct{}, len(transforms))
+	for _, transform := range transforms {
+		transform.Target = strings.TrimSpace(transform.Target)
+		if !attributeKeyPattern.MatchString(transform.Target) {
+			return nil, ErrInvalidFieldTransform
+		}
+		if _, duplicate := targets[transform.Target]; duplicate {
+			return nil, ErrInvalidFieldTransform
+		}
+		targets[transform.Target] = struct{}{}
+
+		transform.Source = strings.TrimSpace(transform.Source)
+		if transform.Source != "" && len(transform.Sources) > 0 {
+			return nil, ErrInvalidFieldTransform
+		}
+		if transform.Source == "" && len(transform.Sources) == 0 && transform.Default == nil {
+			return nil, ErrInvalidFieldTransform
+		}
+		if utf8.RuneCountInString(transform.Source) > maxFieldMappingSourceLength {
+			return nil, ErrInvalidFieldTransform
+		}
+		sources := make([]string, 0, len(transform.Sources))
+		for _, source := range transform.Sources {
+			source = strings.TrimSpace(source)
+			if source == "" || utf8.RuneCountInString(source) > maxFieldMappingSourceLength {
+				return nil, ErrInvalidFieldTransform
+			}
+			sources = append(sources, source)
+		}
+		if len(sources) > 0 {
+			transform.Sources = sources
+		}
+		if len(transform.Sources) > 0 && transform.Separator == "" {
+			transform.Separator = " "
+		}
+
+		switch transform.Case = FieldCase(strings.ToLower(strings.TrimSpace(string(transform.Case)))); transform.Case {
+		case "", FieldCaseLower, FieldCaseUpper, FieldCaseTitle:
+		default:
+			return nil, ErrInvalidFieldTransform
+		}
+		if transform.Split == "" && transform.Index != 0 {
+			return nil, ErrInvalidFieldTransform
+		}
+
+		parsed = append(parsed, transform)
+	}
+	return parsed, nil
+}
+
+type FieldMapper struct {
+	transforms []FieldTransform
+	consumed   map[string]struct{}
+}
+
+func NewFieldMapper(renames map[string]string, transforms []FieldTransform) (FieldMapper, error) {
+	renames, err := ParseFieldMapping(renames)
+	if err != nil {
+		return FieldMapper{}, err
+	}
+
+	steps := make([]FieldTransform, 0, len(renames)+len(transforms))
+	for source, target := range renames {
+		steps = append(steps, FieldTransform{Target: target, Source: source})
+	}
+	steps = append(steps, transforms...)
+
+	steps, err = ParseFieldTransforms(steps)
+	if err != nil {
+		return FieldMapper{}, err
+	}
+
+	mapper := FieldMapper{transforms: steps, consumed: make(map[string]struct{})}
+	for _, transform := range steps {
+		for _, source := range append([]string{transform.Source}, transform.Sources...) {
+			if key, ok := topLevelKey(source); ok {
+				mapper.consumed[key] = struct{}{}
+			}
+		}
+	}
+	return mapper, nil
+}
+
+func (m FieldMapper) Empty() bool {
+	return len(m.transforms) == 0
+}
+
+func (m FieldMapper) Apply(record map[string]any) map[string]any {
+	if m.Empty() {
+		return record
+	}
+
+	out := make(map[string]any, len(record)+len(m.transforms))
+	for key, value := range record {
+		if _, consumed := m.consumed[key]; !consumed {
+			out[key] = value
+		}
+	}
+	for _, transform := range m.transforms {
+		if value, ok := transform.apply(record); ok {
+			out[transform.Target] = value
+		}
+	}
+	return out
+}
+
+func (t FieldTransform) apply(record map[string]any) (any, bool) {
+	var value any
+	var ok bool
+	if len(t.Sources) > 0 {
+		parts := make([]string, 0, len(t.Sources))
+		for _, source := range t.Sources {
+			if part, found := scalarString(resolveSource(record, source)); found && part != "" {
+				parts = append(parts, part)
+			}
+		}
+		value, ok = strings.Join(parts, t.Separator), len(parts) > 0
+	} else if t.Source != "" {
+		value, ok = resolveSource(record, t.Source)
+	}
+
+	if text, isText := value.(string); ok && isText {
+		if t.Split != "" {
+			text, ok = splitPart(text, t.Split, t.Index)
+		}
+		if t.Trim {
+			text = strings.TrimSpace(text)
+		}
+		switch t.Case {
+		case FieldCaseLower:
+			text = strings.ToLower(text)
+		case FieldCaseUpper:
+			text = strings.ToUpper(text)
+		case FieldCaseTitle:
+			text = titleCase(text)
+		}
+		if replacement, found := t.Lookup[text]; found {
+			text = replacement
+		}
+		value = text
+	}
+
+	if !ok || value == nil || value == "" {
+		if t.Default == nil {
+			return nil, false
+		}
+		return *t.Default, true
+	}
+	return value, true
+}
+
+func resolveSource(record map[string]any, source string) (any, bool) {
+	if !strings.HasPrefix(source, "/") {
+		value, ok := record[source]
+		return value, ok
+	}
+
+	var current any = record
+	for _, token := range JSONPointerTokens(source) {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, ok := parseIndex(token)
+			if !ok || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func topLevelKey(source string) (string, bool) {
+	if source == "" {
+		return "", false
+	}
+	if !strings.HasPrefix(source, "/") {
+		return source, true
+	}
+	return JSONPointerTokens(source)[0], true
+}
+
+func parseIndex(token string) (int, bool) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, false
+	}
+	index := 0
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return 0, false
+		}
+		index = index*10 + int(r-'0')
+	}
+	return index, true
+}
+
+func scalarString(value any, ok bool) (string, bool) {
+	if !ok || value == nil {
+		return "", false
+	}
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case map[string]any, []any:
+		return "", false
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+func splitPart(value, separator string, index int) (string, bool) {
+	parts := strings.Split(value, separator)
+	if index < 0 {
+		index += len(parts)
+	}
+	if index < 0 || index >= len(parts) {
+		return "", false
+	}
+	return parts[index], true
+}
+
+func titleCase(value string) string {
+	runes := []rune(strings.ToLower(value))
+	start := true
+	for i, r := range runes {
+		if unicode.IsSpace(r) || r == '-' {
+			start = true
+			continue
+		}
+		if start {
+			runes[i] = unicode.ToUpper(r)
+			start = false
+		}
+	}
+	return string(runes)
+}
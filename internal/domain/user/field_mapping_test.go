@@ -0,0 +1,107 @@
+package user_test
+
+import (
+	"reflect"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestFieldMapperApply(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := domain.NewFieldMapper(map[string]string{"addr": "addresses"}, []domain.FieldTransform{
+		{Target: "email", Source: "/contact/email", Trim: true, Case: domain.FieldCaseLower},
+		{Target: "phone_number", Source: "/contact/phones/0"},
+		{Target: "name", Sources: []string{"/first", "/last"}, Case: domain.FieldCaseTitle},
+		{Target: "family_name", Source: "fullName", Split: " ", Index: -1},
+		{Target: "department", Source: "/dept", Lookup: map[string]string{"S": "Sales", "M": "Marketing"}},
+		{Target: "source_system", Default: stringPtr("partner-a")},
+		{Target: "region", Source: "/meta/region", Default: stringPtr("unknown")},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	record := map[string]any{
+		"id":       "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+		"contact":  map[string]any{"email": "  Alice@Example.COM ", "phones": []any{"+15125550100"}},
+		"first":    "aLICE",
+		"last":     "van der berg",
+		"fullName": "Alice van der Berg",
+		"dept":     "S",
+		"addr":     []any{map[string]any{"city": "Austin"}},
+		"extra":    true,
+	}
+
+	want := map[string]any{
+		"id":            "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+		"email":         "alice@example.com",
+		"phone_number":  "+15125550100",
+		"name":          "Alice Van Der Berg",
+		"family_name":   "Berg",
+		"department":    "Sales",
+		"addresses":     []any{map[string]any{"city": "Austin"}},
+		"source_system": "partner-a",
+		"region":        "unknown",
+		"extra":         true,
+	}
+	if got := mapper.Apply(record); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected mapped record:\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestFieldMapperMissingSources(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := domain.NewFieldMapper(nil, []domain.FieldTransform{
+		{Target: "name", Sources: []string{"/first", "/last"}},
+		{Target: "email", Source: "/contact/email"},
+		{Target: "phone_number", Source: "/contact/phones/3"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := mapper.Apply(map[string]any{"last": "Smith", "contact": map[string]any{"phones": []any{}}})
+	want := map[string]any{"name": "Smith"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestFieldMapperEmpty(t *testing.T) {
+	t.Parallel()
+
+	mapper, err := domain.NewFieldMapper(nil, nil)
+	if err != nil || !mapper.Empty() {
+		t.Fatalf("expected empty mapper, got %v", err)
+	}
+}
+
+func TestParseFieldTransformsInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := [][]domain.FieldTransform{
+		{{Target: "name"}},
+		{{Target: "bad target", Source: "/name"}},
+		{{Target: "name", Source: "/a", Sources: []string{"/b"}}},
+		{{Target: "name", Sources: []string{" "}}},
+		{{Target: "name", Source: "/a", Case: "camel"}},
+		{{Target: "name", Source: "/a", Index: 1}},
+		{{Target: "name", Source: "/a"}, {Target: "name", Source: "/b"}},
+	}
+	for _, transforms := range cases {
+		if _, err := domain.ParseFieldTransforms(transforms); err != domain.ErrInvalidFieldTransform {
+			t.Fatalf("parse %+v: expected ErrInvalidFieldTransform, got %v", transforms, err)
+		}
+	}
+
+	if _, err := domain.NewFieldMapper(map[string]string{"fullName": "name"}, []domain.FieldTransform{{Target: "name", Source: "/n"}}); err != domain.ErrInvalidFieldTransform {
+		t.Fatalf("expected conflicting rename and transform to be rejected, got %v", err)
+	}
+}
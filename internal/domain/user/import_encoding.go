@@ -0,0 +1,31 @@
+package user
+
+import "strings"
+
+type ImportEncoding string
+
+const (
+	ImportEncodingAuto        ImportEncoding = "auto"
+	ImportEncodingUTF8        ImportEncoding = "utf-8"
+	ImportEncodingUTF16LE     ImportEncoding = "utf-16le"
+	ImportEncodingUTF16BE     ImportEncoding = "utf-16be"
+	ImportEncodingLatin1      ImportEncoding = "iso-8859-1"
+	ImportEncodingWindows1252 ImportEncoding = "windows-1252"
+)
+
+func ParseImportEncoding(value string) (ImportEncoding, error) {
+	switch encoding := ImportEncoding(strings.ToLower(strings.TrimSpace(value))); encoding {
+	case "":
+		return ImportEncodingAuto, nil
+	case "utf8":
+		return ImportEncodingUTF8, nil
+	case "latin1", "latin-1":
+		return ImportEncodingLatin1, nil
+	case "cp1252":
+		return ImportEncodingWindows1252, nil
+	case ImportEncodingAuto, ImportEncodingUTF8, ImportEncodingUTF16LE, ImportEncodingUTF16BE, ImportEncodingLatin1, ImportEncodingWindows1252:
+		return encoding, nil
+	default:
+		return "", ErrInvalidImportEncoding
+	}
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type ImportFormat string
+
+const (
+	ImportFormatJSON   ImportFormat = "json"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+	ImportFormatXLSX   ImportFormat = "xlsx"
+	ImportFormatXML    ImportFormat = "xml"
+)
+
+const maxSheetNameLength = 31
+
+func ParseImportFormat(value string) (ImportFormat, error) {
+	switch format := ImportFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return ImportFormatJSON, nil
+	case ImportFormatJSON, ImportFormatNDJSON, ImportFormatXLSX, ImportFormatXML:
+		return format, nil
+	default:
+		return "", ErrInvalidImportFormat
+	}
+}
+
+func (f ImportFormat) Extension() string {
+	return "." + string(f)
+}
+
+func (f ImportFormat) LineDelimited() bool {
+	return f == ImportFormatNDJSON
+}
+
+// ReportsOffsets tells whether the readers of the format locate records by
+// byte offset in the source.
+func (f ImportFormat) ReportsOffsets() bool {
+	switch f {
+	case ImportFormatJSON, ImportFormatNDJSON, ImportFormatXML:
+		return true
+	default:
+		return false
+	}
+}
+
+func ParsePartitions(value int, format ImportFormat, encoding ImportEncoding) (int, error) {
+	if value < 0 || value > MaxImportPartitions {
+		return 0, ErrInvalidPartitions
+	}
+	if value > 1 && (!format.LineDelimited() || encoding == ImportEncodingUTF16LE || encoding == ImportEncodingUTF16BE) {
+		return 0, ErrInvalidPartitions
+	}
+	return value, nil
+}
+
+func ParseSheetName(value string, format ImportFormat) (string, error) {
+	sheet := strings.TrimSpace(value)
+	if sheet == "" {
+		return "", nil
+	}
+	if format != ImportFormatXLSX || utf8.RuneCountInString(sheet) > maxSheetNameLength {
+		return "", ErrInvalidSheetName
+	}
+	return sheet, nil
+}
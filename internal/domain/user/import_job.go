@@ -1,16 +1,80 @@
 package user
 
+import "time"
+
+const (
+	ImportJobStatusQueued    = "queued"
+	ImportJobStatusRunning   = "running"
+	ImportJobStatusWaiting   = "waiting"
+	ImportJobStatusSucceeded = "succeeded"
+	ImportJobStatusFailed    = "failed"
+)
+
 type ImportJob struct {
-	ID          string
-	SourcePath  string
-	Status      string
-	Attempts    int
-	MaxAttempts int
+	ID            string
+	ParentID      string
+	Partition     *ImportPartition
+	SourcePath    string
+	Status        string
+	Attempts      int
+	MaxAttempts   int
+	Options       ImportOptions
+	Progress      ImportProgress
+	ErrorMessage  string
+	UnknownFields map[string]int64
+	Metadata      map[string]any
+	ExpectedCount *int64
+	CreatedAt     time.Time
+	StartedAt     *time.Time
+	FinishedAt    *time.Time
+}
+
+// ImportPartition is the byte range [Start, End) of the parent job's source
+// that a child job imports.
+type ImportPartition struct {
+	Index int
+	Start int64
+	End   int64
+}
+
+// RootID is the job whose rows compete for the same users: the parent of a
+// partition, or the job itself.
+func (j ImportJob) RootID() string {
+	if j.ParentID != "" {
+		return j.ParentID
+	}
+	return j.ID
+}
+
+type RecordLocator struct {
+	Row    int64
+	Line   int64
+	Offset int64
+}
+
+// Position orders the records of one job in a single unit chosen by format:
+// the byte offset in the source where the format reports one, which stays
+// global across partitions, and the row otherwise.
+func (l RecordLocator) Position(format ImportFormat) int64 {
+	if format.ReportsOffsets() {
+		return l.Offset
+	}
+	return l.Row
 }
 
 type ImportFailure struct {
 	RowIndex int64
+	Locator  RecordLocator
 	Reason   string
+	RuleID   string
+	Field    string
+}
+
+type ImportWarning struct {
+	RowIndex int64
+	Locator  RecordLocator
+	Reason   string
+	Field    string
 }
 
 type ImportProgress struct {
@@ -19,6 +83,11 @@ type ImportProgress struct {
 	UpdatedCount   int64
 	SkippedCount   int64
 	FailedCount    int64
+	WarningCount   int64
+	ChunkStats     ImportChunkStats
+	Failures       []ImportFailure
+	Skipped        []ImportSkip
+	Warnings       []ImportWarning
 }
 
 type ImportSummary struct {
@@ -27,5 +96,41 @@ type ImportSummary struct {
 	UpdatedCount   int64
 	SkippedCount   int64
 	FailedCount    int64
+	WarningCount   int64
 	Failures       []ImportFailure
+	Skipped        []ImportSkip
+	Warnings       []ImportWarning
+	UnknownFields  map[string]int64
+	Metadata       map[string]any
+	ExpectedCount  *int64
+	ChunkStats     ImportChunkStats
+}
+
+// ImportChunkStats tracks the chunks written by a job: their estimated size in
+// memory, the time spent writing them and the largest heap seen meanwhile.
+type ImportChunkStats struct {
+	Chunks        int64
+	Rows          int64
+	Bytes         int64
+	MaxChunkRows  int64
+	MaxChunkBytes int64
+	WriteDuration time.Duration
+	MaxHeapBytes  int64
+}
+
+func (s *ImportChunkStats) Add(rows, bytes int64, duration time.Duration) {
+	s.Chunks++
+	s.Rows += rows
+	s.Bytes += bytes
+	s.MaxChunkRows = max(s.MaxChunkRows, rows)
+	s.MaxChunkBytes = max(s.MaxChunkBytes, bytes)
+	s.WriteDuration += duration
+}
+
+// RowsPerSecond is the write throughput of a single chunk writer.
+func (s ImportChunkStats) RowsPerSecond() float64 {
+	if s.WriteDuration <= 0 {
+		return 0
+	}
+	return float64(s.Rows) / s.WriteDuration.Seconds()
 }
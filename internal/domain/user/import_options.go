@@ -0,0 +1,197 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+)
+
+var sourceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+const (
+	MaxImportChunkSize        = 100000
+	MaxImportChunkConcurrency = 16
+	MaxImportPartitions       = 64
+	MaxImportMaxAttempts      = 20
+)
+
+type AddressStrategy string
+
+const (
+	AddressStrategyReplace AddressStrategy = "replace"
+	AddressStrategyAppend  AddressStrategy = "append"
+	AddressStrategyMerge   AddressStrategy = "merge"
+	AddressStrategyIgnore  AddressStrategy = "ignore"
+)
+
+func ParseAddressStrategy(value string) (AddressStrategy, error) {
+	switch strategy := AddressStrategy(strings.ToLower(strings.TrimSpace(value))); strategy {
+	case "":
+		return AddressStrategyReplace, nil
+	case AddressStrategyReplace, AddressStrategyAppend, AddressStrategyMerge, AddressStrategyIgnore:
+		return strategy, nil
+	default:
+		return "", ErrInvalidAddressStrategy
+	}
+}
+
+type FieldUpdatePolicy string
+
+const (
+	FieldUpdateAlways    FieldUpdatePolicy = "always"
+	FieldUpdateFillEmpty FieldUpdatePolicy = "fill_empty"
+	FieldUpdateNever     FieldUpdatePolicy = "never"
+	FieldUpdateIfPresent FieldUpdatePolicy = "if_present"
+)
+
+func ParseFieldUpdatePolicy(value string) (FieldUpdatePolicy, error) {
+	switch policy := FieldUpdatePolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return FieldUpdateAlways, nil
+	case FieldUpdateAlways, FieldUpdateFillEmpty, FieldUpdateNever, FieldUpdateIfPresent:
+		return policy, nil
+	default:
+		return "", ErrInvalidFieldUpdatePolicy
+	}
+}
+
+type FieldUpdatePolicies struct {
+	Name        FieldUpdatePolicy
+	Email       FieldUpdatePolicy
+	PhoneNumber FieldUpdatePolicy
+}
+
+func (p FieldUpdatePolicies) WithDefaults() FieldUpdatePolicies {
+	if p.Name == "" {
+		p.Name = FieldUpdateAlways
+	}
+	if p.Email == "" {
+		p.Email = FieldUpdateAlways
+	}
+	if p.PhoneNumber == "" {
+		p.PhoneNumber = FieldUpdateAlways
+	}
+	return p
+}
+
+type IdentityConflictPolicy string
+
+const (
+	IdentityConflictReject        IdentityConflictPolicy = "reject"
+	IdentityConflictReassignEmail IdentityConflictPolicy = "reassign_email"
+	IdentityConflictMerge         IdentityConflictPolicy = "merge"
+
+	// IdentityConflictSuperseded is only a resolution: the row lost its email to a
+	// later row of the same job, so it was applied without one.
+	IdentityConflictSuperseded IdentityConflictPolicy = "superseded"
+)
+
+func ParseIdentityConflictPolicy(value string) (IdentityConflictPolicy, error) {
+	switch policy := IdentityConflictPolicy(strings.ToLower(strings.TrimSpace(value))); policy {
+	case "":
+		return IdentityConflictReject, nil
+	case IdentityConflictReject, IdentityConflictReassignEmail, IdentityConflictMerge:
+		return policy, nil
+	default:
+		return "", ErrInvalidIdentityConflictPolicy
+	}
+}
+
+func ParseProfileName(value string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if name == "" {
+		return "", nil
+	}
+	if !sourceNamePattern.MatchString(name) {
+		return "", ErrInvalidProfileName
+	}
+	return name, nil
+}
+
+func ParseSourceName(value string) (string, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if name == "" {
+		return "", nil
+	}
+	if !sourceNamePattern.MatchString(name) {
+		return "", ErrInvalidSourceName
+	}
+	return name, nil
+}
+
+func ParseChunkSize(value int) (int, error) {
+	if value < 0 || value > MaxImportChunkSize {
+		return 0, ErrInvalidChunkSize
+	}
+	return value, nil
+}
+
+func ParseChunkConcurrency(value int) (int, error) {
+	if value < 0 || value > MaxImportChunkConcurrency {
+		return 0, ErrInvalidChunkConcurrency
+	}
+	return value, nil
+}
+
+func ParseMaxAttempts(value int) (int, error) {
+	if value < 0 || value > MaxImportMaxAttempts {
+		return 0, ErrInvalidMaxAttempts
+	}
+	return value, nil
+}
+
+type ImportOptions struct {
+	AddressStrategy AddressStrategy
+	UpdatePolicies  FieldUpdatePolicies
+	Source          string
+	ConflictPolicy  IdentityConflictPolicy
+	Profile         string
+
+	AddressValidation AddressValidationRules
+	OversizePolicy    OversizePolicy
+	PhoneRegion       string
+	AttributeStrategy AttributeStrategy
+	AttributeFields   []string
+	SchemaMode        SchemaMode
+
+	Format           ImportFormat
+	Encoding         ImportEncoding
+	Sheet            string
+	XML              XMLMapping
+	FieldMapping     map[string]string
+	Transforms       []FieldTransform
+	Rules            []ValidationRule
+	ChunkSize        int
+	ChunkConcurrency int
+	Partitions       int
+	MaxAttempts      int
+
+	RecordsPointer string
+	Metadata       map[string]string
+}
+
+func (o ImportOptions) WithDefaults() ImportOptions {
+	if o.AddressStrategy == "" {
+		o.AddressStrategy = AddressStrategyReplace
+	}
+	o.UpdatePolicies = o.UpdatePolicies.WithDefaults()
+	if o.ConflictPolicy == "" {
+		o.ConflictPolicy = IdentityConflictReject
+	}
+	o.AddressValidation = o.AddressValidation.WithDefaults()
+	if o.OversizePolicy == "" {
+		o.OversizePolicy = OversizeReject
+	}
+	if o.AttributeStrategy == "" {
+		o.AttributeStrategy = AttributeStrategyMerge
+	}
+	if o.SchemaMode == "" {
+		o.SchemaMode = SchemaModeLenient
+	}
+	if o.Format == "" {
+		o.Format = ImportFormatJSON
+	}
+	if o.Encoding == "" {
+		o.Encoding = ImportEncodingAuto
+	}
+	return o
+}
@@ -0,0 +1,310 @@
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestParseAddressStrategy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]domain.AddressStrategy{
+		"":         domain.AddressStrategyReplace,
+		"replace":  domain.AddressStrategyReplace,
+		" Append ": domain.AddressStrategyAppend,
+		"merge":    domain.AddressStrategyMerge,
+		"ignore":   domain.AddressStrategyIgnore,
+	}
+	for input, want := range cases {
+		got, err := domain.ParseAddressStrategy(input)
+		if err != nil {
+			t.Fatalf("parse %q: unexpected error %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("parse %q: expected %q, got %q", input, want, got)
+		}
+	}
+
+	if _, err := domain.ParseAddressStrategy("overwrite"); err != domain.ErrInvalidAddressStrategy {
+		t.Fatalf("expected ErrInvalidAddressStrategy, got %v", err)
+	}
+}
+
+func TestParseFieldUpdatePolicy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]domain.FieldUpdatePolicy{
+		"":           domain.FieldUpdateAlways,
+		"always":     domain.FieldUpdateAlways,
+		"fill_empty": domain.FieldUpdateFillEmpty,
+		"NEVER":      domain.FieldUpdateNever,
+		"if_present": domain.FieldUpdateIfPresent,
+	}
+	for input, want := range cases {
+		got, err := domain.ParseFieldUpdatePolicy(input)
+		if err != nil {
+			t.Fatalf("parse %q: unexpected error %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("parse %q: expected %q, got %q", input, want, got)
+		}
+	}
+
+	if _, err := domain.ParseFieldUpdatePolicy("sometimes"); err != domain.ErrInvalidFieldUpdatePolicy {
+		t.Fatalf("expected ErrInvalidFieldUpdatePolicy, got %v", err)
+	}
+}
+
+func TestParseSourceName(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.ParseSourceName(" HR-Feed.eu_1 ")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got != "hr-feed.eu_1" {
+		t.Fatalf("unexpected source name: %q", got)
+	}
+
+	if got, err := domain.ParseSourceName(""); err != nil || got != "" {
+		t.Fatalf("expected empty source to be allowed, got %q, %v", got, err)
+	}
+
+	for _, input := range []string{"hr feed", "-hr", strings.Repeat("a", 65)} {
+		if _, err := domain.ParseSourceName(input); err != domain.ErrInvalidSourceName {
+			t.Fatalf("parse %q: expected ErrInvalidSourceName, got %v", input, err)
+		}
+	}
+}
+
+func TestParseIdentityConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]domain.IdentityConflictPolicy{
+		"":                 domain.IdentityConflictReject,
+		"reject":           domain.IdentityConflictReject,
+		" Reassign_Email ": domain.IdentityConflictReassignEmail,
+		"merge":            domain.IdentityConflictMerge,
+	}
+	for input, want := range cases {
+		got, err := domain.ParseIdentityConflictPolicy(input)
+		if err != nil {
+			t.Fatalf("parse %q: expected no error, got %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("parse %q: expected %q, got %q", input, want, got)
+		}
+	}
+
+	if _, err := domain.ParseIdentityConflictPolicy("overwrite"); err != domain.ErrInvalidIdentityConflictPolicy {
+		t.Fatalf("expected ErrInvalidIdentityConflictPolicy, got %v", err)
+	}
+}
+
+func TestParseImportFormat(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{"", "json", " JSON "} {
+		got, err := domain.ParseImportFormat(input)
+		if err != nil || got != domain.ImportFormatJSON {
+			t.Fatalf("parse %q: expected json, got %q, %v", input, got, err)
+		}
+	}
+	if domain.ImportFormatJSON.Extension() != ".json" {
+		t.Fatalf("unexpected extension: %q", domain.ImportFormatJSON.Extension())
+	}
+
+	if got, err := domain.ParseImportFormat("ndjson"); err != nil || !got.LineDelimited() {
+		t.Fatalf("parse ndjson: got %q, %v", got, err)
+	}
+	if got, err := domain.ParseImportFormat("XLSX"); err != nil || got.Extension() != ".xlsx" {
+		t.Fatalf("parse xlsx: got %q, %v", got, err)
+	}
+
+	if _, err := domain.ParseImportFormat("csv"); err != domain.ErrInvalidImportFormat {
+		t.Fatalf("expected ErrInvalidImportFormat, got %v", err)
+	}
+}
+
+func TestParseSheetName(t *testing.T) {
+	t.Parallel()
+
+	if got, err := domain.ParseSheetName(" Users ", domain.ImportFormatXLSX); err != nil || got != "Users" {
+		t.Fatalf("expected Users, got %q, %v", got, err)
+	}
+	if got, err := domain.ParseSheetName("", domain.ImportFormatJSON); err != nil || got != "" {
+		t.Fatalf("expected empty sheet, got %q, %v", got, err)
+	}
+
+	invalid := []struct {
+		sheet  string
+		format domain.ImportFormat
+	}{
+		{sheet: "Users", format: domain.ImportFormatJSON},
+		{sheet: strings.Repeat("s", 32), format: domain.ImportFormatXLSX},
+	}
+	for _, tc := range invalid {
+		if _, err := domain.ParseSheetName(tc.sheet, tc.format); err != domain.ErrInvalidSheetName {
+			t.Fatalf("ParseSheetName(%q, %q): expected ErrInvalidSheetName, got %v", tc.sheet, tc.format, err)
+		}
+	}
+}
+
+func TestParseImportEncoding(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]domain.ImportEncoding{
+		"":           domain.ImportEncodingAuto,
+		"UTF8":       domain.ImportEncodingUTF8,
+		"utf-16le":   domain.ImportEncodingUTF16LE,
+		"UTF-16BE":   domain.ImportEncodingUTF16BE,
+		" latin1 ":   domain.ImportEncodingLatin1,
+		"cp1252":     domain.ImportEncodingWindows1252,
+		"iso-8859-1": domain.ImportEncodingLatin1,
+	}
+	for input, want := range cases {
+		got, err := domain.ParseImportEncoding(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseImportEncoding(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := domain.ParseImportEncoding("ebcdic"); err != domain.ErrInvalidImportEncoding {
+		t.Fatalf("expected ErrInvalidImportEncoding, got %v", err)
+	}
+}
+
+func TestParseFieldMapping(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.ParseFieldMapping(map[string]string{" Full Name ": "name", "mail": " email "})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(got) != 2 || got["Full Name"] != "name" || got["mail"] != "email" {
+		t.Fatalf("unexpected mapping: %v", got)
+	}
+
+	if got, err := domain.ParseFieldMapping(nil); err != nil || got != nil {
+		t.Fatalf("expected empty mapping, got %v, %v", got, err)
+	}
+
+	invalid := []map[string]string{
+		{"": "name"},
+		{"mail": "e mail"},
+		{"mail": "email", "e-mail": "email"},
+		{"mail": "email", " mail": "name"},
+	}
+	for _, mapping := range invalid {
+		if _, err := domain.ParseFieldMapping(mapping); err != domain.ErrInvalidFieldMapping {
+			t.Fatalf("parse %v: expected ErrInvalidFieldMapping, got %v", mapping, err)
+		}
+	}
+}
+
+func TestParseChunkSizeAndMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	if got, err := domain.ParseChunkSize(500); err != nil || got != 500 {
+		t.Fatalf("expected 500, got %d, %v", got, err)
+	}
+	for _, input := range []int{-1, domain.MaxImportChunkSize + 1} {
+		if _, err := domain.ParseChunkSize(input); err != domain.ErrInvalidChunkSize {
+			t.Fatalf("parse %d: expected ErrInvalidChunkSize, got %v", input, err)
+		}
+	}
+
+	if got, err := domain.ParseChunkConcurrency(4); err != nil || got != 4 {
+		t.Fatalf("expected 4, got %d, %v", got, err)
+	}
+	for _, input := range []int{-1, domain.MaxImportChunkConcurrency + 1} {
+		if _, err := domain.ParseChunkConcurrency(input); err != domain.ErrInvalidChunkConcurrency {
+			t.Fatalf("parse %d: expected ErrInvalidChunkConcurrency, got %v", input, err)
+		}
+	}
+
+	if got, err := domain.ParsePartitions(8, domain.ImportFormatNDJSON, domain.ImportEncodingAuto); err != nil || got != 8 {
+		t.Fatalf("expected 8, got %d, %v", got, err)
+	}
+	if got, err := domain.ParsePartitions(1, domain.ImportFormatJSON, domain.ImportEncodingAuto); err != nil || got != 1 {
+		t.Fatalf("expected 1, got %d, %v", got, err)
+	}
+	invalidPartitions := []struct {
+		value    int
+		format   domain.ImportFormat
+		encoding domain.ImportEncoding
+	}{
+		{value: -1, format: domain.ImportFormatNDJSON},
+		{value: domain.MaxImportPartitions + 1, format: domain.ImportFormatNDJSON},
+		{value: 2, format: domain.ImportFormatJSON},
+		{value: 2, format: domain.ImportFormatNDJSON, encoding: domain.ImportEncodingUTF16LE},
+	}
+	for _, tc := range invalidPartitions {
+		if _, err := domain.ParsePartitions(tc.value, tc.format, tc.encoding); err != domain.ErrInvalidPartitions {
+			t.Fatalf("ParsePartitions(%d, %q, %q): expected ErrInvalidPartitions, got %v", tc.value, tc.format, tc.encoding, err)
+		}
+	}
+
+	if got, err := domain.ParseMaxAttempts(0); err != nil || got != 0 {
+		t.Fatalf("expected 0, got %d, %v", got, err)
+	}
+	for _, input := range []int{-1, domain.MaxImportMaxAttempts + 1} {
+		if _, err := domain.ParseMaxAttempts(input); err != domain.ErrInvalidMaxAttempts {
+			t.Fatalf("parse %d: expected ErrInvalidMaxAttempts, got %v", input, err)
+		}
+	}
+}
+
+func TestRecordLocatorPosition(t *testing.T) {
+	t.Parallel()
+
+	locator := domain.RecordLocator{Row: 3, Line: 5, Offset: 0}
+	if got := locator.Position(domain.ImportFormatNDJSON); got != 0 {
+		t.Fatalf("expected ndjson position to be the byte offset 0, got %d", got)
+	}
+	if got := locator.Position(domain.ImportFormatXLSX); got != 3 {
+		t.Fatalf("expected xlsx position to be the row 3, got %d", got)
+	}
+}
+
+func TestParseXMLMapping(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.ParseXMLMapping(domain.XMLMapping{
+		RecordPath:    " /p:feed/p:user ",
+		Fields:        map[string]string{"id": "@id", "email": "p:contact/@p:email"},
+		AddressPath:   "addresses/address",
+		AddressFields: map[string]string{"zip_code": "@zip"},
+		Namespaces:    map[string]string{"p": "urn:partner"},
+	}, domain.ImportFormatXML)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.RecordPath != "/p:feed/p:user" || got.Fields["email"] != "p:contact/@p:email" {
+		t.Fatalf("unexpected mapping: %+v", got)
+	}
+	if got, err := domain.ParseXMLMapping(domain.XMLMapping{}, domain.ImportFormatJSON); err != nil || !got.Empty() {
+		t.Fatalf("expected empty mapping, got %+v, %v", got, err)
+	}
+
+	invalid := []struct {
+		mapping domain.XMLMapping
+		format  domain.ImportFormat
+	}{
+		{mapping: domain.XMLMapping{RecordPath: "/users/user"}, format: domain.ImportFormatJSON},
+		{mapping: domain.XMLMapping{RecordPath: "users/user"}, format: domain.ImportFormatXML},
+		{mapping: domain.XMLMapping{RecordPath: "/users/@id"}, format: domain.ImportFormatXML},
+		{mapping: domain.XMLMapping{RecordPath: "/q:users"}, format: domain.ImportFormatXML},
+		{mapping: domain.XMLMapping{Fields: map[string]string{"id": "@id/value"}}, format: domain.ImportFormatXML},
+		{mapping: domain.XMLMapping{Fields: map[string]string{"id": ""}}, format: domain.ImportFormatXML},
+		{mapping: domain.XMLMapping{Fields: map[string]string{"bad key": "id"}}, format: domain.ImportFormatXML},
+		{mapping: domain.XMLMapping{Namespaces: map[string]string{"p": " "}}, format: domain.ImportFormatXML},
+	}
+	for _, tc := range invalid {
+		if _, err := domain.ParseXMLMapping(tc.mapping, tc.format); err != domain.ErrInvalidXMLMapping {
+			t.Fatalf("ParseXMLMapping(%+v): expected ErrInvalidXMLMapping, got %v", tc.mapping, err)
+		}
+	}
+}
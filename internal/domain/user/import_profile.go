@@ -0,0 +1,12 @@
+package user
+
+import "time"
+
+type ImportProfile struct {
+	ID        string
+	Name      string
+	Options   ImportOptions
+	Rules     []ValidationRule
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
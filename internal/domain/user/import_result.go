@@ -1,7 +1,41 @@
 package user
 
+const (
+	SkipReasonStaleSourceTimestamp  = "stale_source_timestamp"
+	SkipReasonSupersededInSource    = "superseded_in_source"
+	FailureReasonIdentityConflict   = "identity_conflict"
+	FailureReasonInvalidPhoneNumber = "invalid_phone_number"
+	FailureReasonRuleViolation      = "rule_violation"
+	FailureReasonValueTooLong       = "value_too_long"
+	FailureReasonInvalidAddressType = "invalid_address_type"
+	FailureReasonMultipleDefaults   = "multiple_default_addresses"
+	FailureReasonUnknownField       = "unknown_field"
+	FailureReasonInvalidEncoding    = "invalid_encoding"
+	FailureReasonInvalidRecord      = "invalid_record"
+	WarningReasonValueTruncated     = "value_truncated"
+)
+
+type ImportSkip struct {
+	RowIndex int64
+	Locator  RecordLocator
+	Reason   string
+}
+
 type ImportChunkResult struct {
 	ImportedCount int64
 	UpdatedCount  int64
 	SkippedCount  int64
+	FailedCount   int64
+	Skipped       []ImportSkip
+	Failures      []ImportFailure
+	Conflicts     []ImportConflict
+}
+
+type ImportConflict struct {
+	RowIndex    int64
+	Locator     RecordLocator
+	ExternalID  string
+	IDUserID    string
+	EmailUserID string
+	Resolution  IdentityConflictPolicy
 }
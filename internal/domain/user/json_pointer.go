@@ -0,0 +1,47 @@
+package user
+
+import "strings"
+
+const MetadataRecordCount = "record_count"
+
+func ParseJSONPointer(value string) (string, error) {
+	pointer := strings.TrimSpace(value)
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return "", ErrInvalidJSONPointer
+	}
+	return pointer, nil
+}
+
+func JSONPointerTokens(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+func ParseMetadataPointers(pointers map[string]string, recordsPointer string) (map[string]string, error) {
+	if len(pointers) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(pointers))
+	for name, pointer := range pointers {
+		name = strings.TrimSpace(name)
+		if !attributeKeyPattern.MatchString(name) {
+			return nil, ErrInvalidMetadataPointer
+		}
+		pointer, err := ParseJSONPointer(pointer)
+		if err != nil || pointer == "" {
+			return nil, ErrInvalidMetadataPointer
+		}
+		if recordsPointer == "" || pointer == recordsPointer || strings.HasPrefix(pointer, recordsPointer+"/") || strings.HasPrefix(recordsPointer, pointer+"/") {
+			return nil, ErrInvalidMetadataPointer
+		}
+		parsed[name] = pointer
+	}
+	return parsed, nil
+}
@@ -0,0 +1,74 @@
+package user_test
+
+import (
+	"reflect"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestParseJSONPointer(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":              "",
+		" /data/users ": "/data/users",
+		"/":             "/",
+	}
+	for input, want := range cases {
+		got, err := domain.ParseJSONPointer(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseJSONPointer(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := domain.ParseJSONPointer("data/users"); err != domain.ErrInvalidJSONPointer {
+		t.Fatalf("expected ErrInvalidJSONPointer, got %v", err)
+	}
+}
+
+func TestJSONPointerTokens(t *testing.T) {
+	t.Parallel()
+
+	got := domain.JSONPointerTokens("/data/a~1b/m~0n/0")
+	want := []string{"data", "a/b", "m~n", "0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if tokens := domain.JSONPointerTokens(""); tokens != nil {
+		t.Fatalf("expected no tokens for root pointer, got %v", tokens)
+	}
+}
+
+func TestParseMetadataPointers(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.ParseMetadataPointers(map[string]string{
+		" record_count ": "/meta/total",
+		"exported_at":    "/meta/exported_at",
+	}, "/data/users")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got["record_count"] != "/meta/total" || got["exported_at"] != "/meta/exported_at" {
+		t.Fatalf("unexpected pointers: %v", got)
+	}
+
+	invalid := []struct {
+		pointers map[string]string
+		records  string
+	}{
+		{pointers: map[string]string{"total": "/meta/total"}, records: ""},
+		{pointers: map[string]string{"bad name": "/meta/total"}, records: "/data"},
+		{pointers: map[string]string{"total": "meta/total"}, records: "/data"},
+		{pointers: map[string]string{"total": ""}, records: "/data"},
+		{pointers: map[string]string{"total": "/data"}, records: "/data"},
+		{pointers: map[string]string{"total": "/data/users/0"}, records: "/data/users"},
+		{pointers: map[string]string{"total": "/data"}, records: "/data/users"},
+	}
+	for _, tc := range invalid {
+		if _, err := domain.ParseMetadataPointers(tc.pointers, tc.records); err != domain.ErrInvalidMetadataPointer {
+			t.Fatalf("ParseMetadataPointers(%v, %q): expected ErrInvalidMetadataPointer, got %v", tc.pointers, tc.records, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package user
+
+import "strings"
+
+const (
+	minNationalNumberLength = 4
+	maxE164Digits           = 15
+)
+
+type PhoneNumber struct {
+	E164 string
+	Raw  string
+}
+
+func ParsePhoneNumber(raw, defaultRegion string) (PhoneNumber, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return PhoneNumber{}, nil
+	}
+
+	international := false
+	digits := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c >= '0' && c <= '9':
+			digits = append(digits, c)
+		case c == '+' && len(digits) == 0 && !international:
+			international = true
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')' || c == '/':
+		default:
+			return PhoneNumber{}, ErrInvalidPhoneNumber
+		}
+	}
+
+	number := string(digits)
+	if !international && strings.HasPrefix(number, "00") {
+		international = true
+		number = number[2:]
+	}
+
+	var countryCode, national string
+	if international {
+		countryCode = matchCallingCode(number)
+		if countryCode == "" {
+			return PhoneNumber{}, ErrInvalidPhoneNumber
+		}
+		national = number[len(countryCode):]
+	} else {
+		countryCode = RegionCallingCode(defaultRegion)
+		if countryCode == "" {
+			return PhoneNumber{}, ErrInvalidPhoneNumber
+		}
+		national = number
+		if countryCode == "1" && len(national) == 11 && national[0] == '1' {
+			national = national[1:]
+		} else if countryCode != "1" {
+			national = strings.TrimPrefix(national, "0")
+		}
+	}
+
+	if !validNationalNumber(countryCode, national) {
+		return PhoneNumber{}, ErrInvalidPhoneNumber
+	}
+
+	return PhoneNumber{E164: "+" + countryCode + national, Raw: raw}, nil
+}
+
+// Value is the stored form of the number, empty when none was given.
+func (p PhoneNumber) Value() string {
+	return p.E164
+}
+
+// ParsePhoneRegion returns the alpha-2 code of the region used for national
+// phone numbers, given as a country name or ISO code.
+func ParsePhoneRegion(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+	country, ok := LookupCountry(value)
+	if !ok || country.CallingCode == "" {
+		return "", ErrInvalidPhoneRegion
+	}
+	return country.Alpha2, nil
+}
+
+func RegionCallingCode(region string) string {
+	country, _ := LookupCountry(region)
+	return country.CallingCode
+}
+
+func matchCallingCode(number string) string {
+	for length := 1; length <= 3 && length <= len(number); length++ {
+		if _, ok := callingCodes[number[:length]]; ok {
+			return number[:length]
+		}
+	}
+	return ""
+}
+
+func validNationalNumber(countryCode, national string) bool {
+	if national == "" || national[0] == '0' {
+		return false
+	}
+	if countryCode == "1" {
+		return len(national) == 10
+	}
+	return len(national) >= minNationalNumberLength && len(countryCode)+len(national) <= maxE164Digits
+}
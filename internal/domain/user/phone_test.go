@@ -0,0 +1,96 @@
+package user_test
+
+import (
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestParsePhoneNumber(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		raw    string
+		region string
+		want   string
+	}{
+		{raw: "+1 (512) 555-0100", want: "+15125550100"},
+		{raw: "0044 20 7946 0958", want: "+442079460958"},
+		{raw: "512.555.0100", region: "USA", want: "+15125550100"},
+		{raw: "1-512-555-0100", region: "us", want: "+15125550100"},
+		{raw: "030 1234567", region: "Germany", want: "+49301234567"},
+		{raw: "020 7946 0958", region: "GB", want: "+442079460958"},
+		{raw: "+33 1 42 68 53 00", region: "Japan", want: "+33142685300"},
+		{raw: "", want: ""},
+	}
+	for _, tc := range cases {
+		got, err := domain.ParsePhoneNumber(tc.raw, tc.region)
+		if err != nil {
+			t.Fatalf("parse %q (%s): expected no error, got %v", tc.raw, tc.region, err)
+		}
+		if got.E164 != tc.want {
+			t.Fatalf("parse %q (%s): expected %q, got %q", tc.raw, tc.region, tc.want, got.E164)
+		}
+	}
+}
+
+func TestParsePhoneNumberKeepsRawInput(t *testing.T) {
+	t.Parallel()
+
+	got, err := domain.ParsePhoneNumber("  (512) 555-0100 ", "USA")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Raw != "(512) 555-0100" {
+		t.Fatalf("unexpected raw input: %q", got.Raw)
+	}
+}
+
+func TestParsePhoneNumberWithoutRegionRejectsNationalNumber(t *testing.T) {
+	t.Parallel()
+
+	for _, region := range []string{"", "Atlantis"} {
+		if _, err := domain.ParsePhoneNumber(" 111-111-1111 ", region); err != domain.ErrInvalidPhoneNumber {
+			t.Fatalf("parse without region %q: expected ErrInvalidPhoneNumber, got %v", region, err)
+		}
+	}
+}
+
+func TestParsePhoneRegion(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"DE", "deu", " Germany "} {
+		region, err := domain.ParsePhoneRegion(value)
+		if err != nil || region != "DE" {
+			t.Fatalf("parse %q: expected DE, got %q, %v", value, region, err)
+		}
+	}
+	if region, err := domain.ParsePhoneRegion(""); err != nil || region != "" {
+		t.Fatalf("expected an empty region to stay unset, got %q, %v", region, err)
+	}
+	if _, err := domain.ParsePhoneRegion("Atlantis"); err != domain.ErrInvalidPhoneRegion {
+		t.Fatalf("expected ErrInvalidPhoneRegion, got %v", err)
+	}
+}
+
+func TestParsePhoneNumberInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		raw    string
+		region string
+	}{
+		{raw: "call me", region: "USA"},
+		{raw: "+1 512 555 01"},
+		{raw: "+999 1234 5678"},
+		{raw: "+49 1234 5678 9012 3456"},
+		{raw: "12", region: "France"},
+		{raw: "+1 512 555 0100 ext 12"},
+		{raw: "555+0100", region: "USA"},
+	}
+	for _, tc := range cases {
+		if _, err := domain.ParsePhoneNumber(tc.raw, tc.region); err != domain.ErrInvalidPhoneNumber {
+			t.Fatalf("parse %q (%s): expected ErrInvalidPhoneNumber, got %v", tc.raw, tc.region, err)
+		}
+	}
+}
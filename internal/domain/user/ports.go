@@ -6,19 +6,33 @@ import (
 )
 
 type ImportJobRepository interface {
-	Enqueue(ctx context.Context, sourcePath string) (string, error)
+	Enqueue(ctx context.Context, sourcePath string, options ImportOptions) (string, error)
 	ClaimNext(ctx context.Context, leaseDuration time.Duration) (*ImportJob, error)
 	Heartbeat(ctx context.Context, jobID string, leaseDuration time.Duration) error
 	UpdateProgress(ctx context.Context, jobID string, progress ImportProgress) error
 	Complete(ctx context.Context, jobID string, summary ImportSummary) error
 	Requeue(ctx context.Context, jobID string, reason string) error
 	Fail(ctx context.Context, jobID string, reason string) error
+	RecordConflicts(ctx context.Context, jobID string, conflicts []ImportConflict) error
+	Partition(ctx context.Context, jobID string, partitions []ImportPartition) error
+	GetByID(ctx context.Context, jobID string) (*ImportJob, error)
+	ListPartitions(ctx context.Context, jobID string) ([]ImportJob, error)
+	ListConflicts(ctx context.Context, jobID string, limit, offset int) ([]ImportConflict, error)
+}
+
+type ImportProfileRepository interface {
+	Create(ctx context.Context, profile ImportProfile) (*ImportProfile, error)
+	GetByName(ctx context.Context, name string) (*ImportProfile, error)
+	List(ctx context.Context, limit, offset int) ([]ImportProfile, error)
+	Update(ctx context.Context, profile ImportProfile) (*ImportProfile, error)
+	Delete(ctx context.Context, name string) error
 }
 
 type UserBulkImporter interface {
-	ImportChunk(ctx context.Context, jobID string, users []User) (ImportChunkResult, error)
+	ImportChunk(ctx context.Context, jobID string, options ImportOptions, users []User) (ImportChunkResult, error)
 }
 
 type UserQueryRepository interface {
 	GetByID(ctx context.Context, userID string) (*User, error)
+	List(ctx context.Context, filter UserFilter) ([]User, error)
 }
@@ -0,0 +1,21 @@
+package user
+
+import "strings"
+
+type SchemaMode string
+
+const (
+	SchemaModeLenient SchemaMode = "lenient"
+	SchemaModeStrict  SchemaMode = "strict"
+)
+
+func ParseSchemaMode(value string) (SchemaMode, error) {
+	switch mode := SchemaMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case "":
+		return SchemaModeLenient, nil
+	case SchemaModeLenient, SchemaModeStrict:
+		return mode, nil
+	default:
+		return "", ErrInvalidSchemaMode
+	}
+}
@@ -0,0 +1,27 @@
+package user_test
+
+import (
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestParseSchemaMode(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]domain.SchemaMode{
+		"":         domain.SchemaModeLenient,
+		"lenient":  domain.SchemaModeLenient,
+		" STRICT ": domain.SchemaModeStrict,
+	}
+	for input, want := range cases {
+		got, err := domain.ParseSchemaMode(input)
+		if err != nil || got != want {
+			t.Fatalf("ParseSchemaMode(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+
+	if _, err := domain.ParseSchemaMode("loose"); err != domain.ErrInvalidSchemaMode {
+		t.Fatalf("expected ErrInvalidSchemaMode, got %v", err)
+	}
+}
@@ -1,16 +1,41 @@
 package user
 
 import (
-	"net/mail"
 	"strings"
+	"time"
 )
 
+type AddressType string
+
+const (
+	AddressTypeHome     AddressType = "home"
+	AddressTypeWork     AddressType = "work"
+	AddressTypeBilling  AddressType = "billing"
+	AddressTypeShipping AddressType = "shipping"
+)
+
+func ParseAddressType(value string) (AddressType, error) {
+	switch addressType := AddressType(strings.ToLower(strings.TrimSpace(value))); addressType {
+	case "":
+		return AddressTypeHome, nil
+	case AddressTypeHome, AddressTypeWork, AddressTypeBilling, AddressTypeShipping:
+		return addressType, nil
+	default:
+		return "", ErrInvalidAddressType
+	}
+}
+
 type Address struct {
-	Street  string
-	City    string
-	State   string
-	ZipCode string
-	Country string
+	Street    string
+	City      string
+	State     string
+	ZipCode   string
+	Country   string
+	Type      AddressType
+	IsDefault bool
+	// PromotedDefault marks an address made default because no address of its
+	// type was marked. It does not replace a default the user already has.
+	PromotedDefault bool
 }
 
 func (a Address) validate() error {
@@ -28,26 +53,103 @@ type User struct {
 	ID          string
 	Name        string
 	Email       string
+	EmailKey    string
 	PhoneNumber string
 	Addresses   []Address
+
+	PhoneNumberRaw string
+	Attributes     map[string]any
+
+	SourceModifiedAt time.Time
+	SourcePosition   int64
+}
+
+// UserOptions are the per-import settings NewUserWithOptions normalizes with.
+// PhoneRegion is the region of national phone numbers for users whose first
+// address has no known country.
+type UserOptions struct {
+	EmailNormalizer EmailNormalizer
+	PhoneRegion     string
 }
 
 func NewUser(id, name, email, phoneNumber string, addresses []Address) (User, error) {
-	if _, err := mail.ParseAddress(email); err != nil {
-		return User{}, ErrInvalidEmail
+	return NewUserWithOptions(UserOptions{}, id, name, email, phoneNumber, addresses)
+}
+
+func NewUserWithEmailNormalizer(normalizer EmailNormalizer, id, name, email, phoneNumber string, addresses []Address) (User, error) {
+	return NewUserWithOptions(UserOptions{EmailNormalizer: normalizer}, id, name, email, phoneNumber, addresses)
+}
+
+func NewUserWithOptions(options UserOptions, id, name, email, phoneNumber string, addresses []Address) (User, error) {
+	email, emailKey, err := options.EmailNormalizer.Normalize(email)
+	if err != nil {
+		return User{}, err
 	}
 
-	for _, address := range addresses {
-		if err := address.validate(); err != nil {
-			return User{}, err
-		}
+	addresses, err = normalizeAddresses(addresses)
+	if err != nil {
+		return User{}, err
+	}
+
+	defaultRegion := options.PhoneRegion
+	if len(addresses) > 0 && RegionCallingCode(addresses[0].Country) != "" {
+		defaultRegion = addresses[0].Country
+	}
+	phone, err := ParsePhoneNumber(phoneNumber, defaultRegion)
+	if err != nil {
+		return User{}, err
+	}
+
+	if uuid, err := ParseUUID(id); err == nil {
+		id = uuid
 	}
 
 	return User{
 		ID:          id,
 		Name:        name,
 		Email:       email,
-		PhoneNumber: phoneNumber,
+		EmailKey:    emailKey,
+		PhoneNumber: phone.Value(),
 		Addresses:   addresses,
+
+		PhoneNumberRaw: phone.Raw,
 	}, nil
 }
+
+func normalizeAddresses(addresses []Address) ([]Address, error) {
+	if len(addresses) == 0 {
+		return addresses, nil
+	}
+
+	normalized := make([]Address, 0, len(addresses))
+	defaults := make(map[AddressType]bool)
+	for _, address := range addresses {
+		if err := address.validate(); err != nil {
+			return nil, err
+		}
+		addressType, err := ParseAddressType(string(address.Type))
+		if err != nil {
+			return nil, err
+		}
+		address.Type = addressType
+		address.PromotedDefault = false
+		if address.IsDefault {
+			if defaults[addressType] {
+				return nil, ErrMultipleDefaultAddresses
+			}
+			defaults[addressType] = true
+		}
+		normalized = append(normalized, address)
+	}
+
+	// Each type gets exactly one default: the first address of a type without
+	// a marked one is promoted.
+	for i := range normalized {
+		if !defaults[normalized[i].Type] {
+			normalized[i].IsDefault = true
+			normalized[i].PromotedDefault = true
+			defaults[normalized[i].Type] = true
+		}
+	}
+	return normalized, nil
+}
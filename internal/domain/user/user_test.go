@@ -71,3 +71,144 @@ func TestNewUserInvalidAddress(t *testing.T) {
 		t.Fatalf("expected ErrInvalidAddress, got %v", err)
 	}
 }
+
+func TestNewUserNormalizesUUID(t *testing.T) {
+	t.Parallel()
+
+	u, err := domain.NewUser("0190F2A4-7C3B-7D2E-9F1A-2B3C4D5E6F70", "Alice", "alice@example.com", "+12345678901", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.ID != "0190f2a4-7c3b-7d2e-9f1a-2b3c4d5e6f70" {
+		t.Fatalf("unexpected id: %s", u.ID)
+	}
+}
+
+func TestNewUserPhoneRegion(t *testing.T) {
+	t.Parallel()
+
+	options := domain.UserOptions{PhoneRegion: "DE"}
+	u, err := domain.NewUserWithOptions(options, "", "Alice", "alice@example.com", "030 1234567", nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.PhoneNumber != "+49301234567" {
+		t.Fatalf("expected the import region to apply without an address, got %q", u.PhoneNumber)
+	}
+
+	austin := domain.Address{Street: "1 Main", City: "Austin", State: "TX", ZipCode: "78701", Country: "US"}
+	u, err = domain.NewUserWithOptions(options, "", "Alice", "alice@example.com", "(512) 555-0100", []domain.Address{austin})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.PhoneNumber != "+15125550100" {
+		t.Fatalf("expected the address country to take precedence, got %q", u.PhoneNumber)
+	}
+
+	atlantis := domain.Address{Street: "1 Main", City: "Poseidonia", State: "NA", ZipCode: "00001", Country: "Atlantis"}
+	u, err = domain.NewUserWithOptions(options, "", "Alice", "alice@example.com", "030 1234567", []domain.Address{atlantis})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.PhoneNumber != "+49301234567" {
+		t.Fatalf("expected the import region to apply for an unknown country, got %q", u.PhoneNumber)
+	}
+
+	if _, err := domain.NewUser("", "Alice", "alice@example.com", "030 1234567", nil); err != domain.ErrInvalidPhoneNumber {
+		t.Fatalf("expected a national number without region to be rejected, got %v", err)
+	}
+}
+
+func TestNewUserNormalizesEmail(t *testing.T) {
+	t.Parallel()
+
+	u, err := domain.NewUserWithEmailNormalizer(
+		domain.NewEmailNormalizer(domain.GmailEmailRule),
+		"",
+		"Alice",
+		" Alice.Smith+news@GMail.com ",
+		"+12345678901",
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.Email != "Alice.Smith+news@gmail.com" {
+		t.Fatalf("unexpected email: %s", u.Email)
+	}
+	if u.EmailKey != "alicesmith@gmail.com" {
+		t.Fatalf("unexpected email key: %s", u.EmailKey)
+	}
+}
+
+func TestNewUserNormalizesPhoneNumber(t *testing.T) {
+	t.Parallel()
+
+	u, err := domain.NewUser(
+		"",
+		"Alice",
+		"alice@example.com",
+		"01 42 68 53 00",
+		[]domain.Address{{Street: "1 Rue", City: "Paris", State: "IDF", ZipCode: "75001", Country: "France"}},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.PhoneNumber != "+33142685300" {
+		t.Fatalf("unexpected phone number: %s", u.PhoneNumber)
+	}
+	if u.PhoneNumberRaw != "01 42 68 53 00" {
+		t.Fatalf("unexpected raw phone number: %s", u.PhoneNumberRaw)
+	}
+
+	if _, err := domain.NewUser("", "Alice", "alice@example.com", "call me", nil); err != domain.ErrInvalidPhoneNumber {
+		t.Fatalf("expected ErrInvalidPhoneNumber, got %v", err)
+	}
+}
+
+func TestNewUserDefaultAddress(t *testing.T) {
+	t.Parallel()
+
+	home := domain.Address{Street: "1 Main", City: "Austin", State: "TX", ZipCode: "78701", Country: "US"}
+	secondHome := domain.Address{Street: "2 Side", City: "Austin", State: "TX", ZipCode: "78701", Country: "US", Type: "home"}
+	work := domain.Address{Street: "5 Office", City: "Austin", State: "TX", ZipCode: "78702", Country: "US", Type: " Work "}
+
+	u, err := domain.NewUser("", "Alice", "alice@example.com", "", []domain.Address{home, secondHome, work})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !u.Addresses[0].IsDefault || !u.Addresses[0].PromotedDefault || u.Addresses[1].IsDefault || !u.Addresses[2].IsDefault || !u.Addresses[2].PromotedDefault {
+		t.Fatalf("expected the first address of each type to be promoted, got %+v", u.Addresses)
+	}
+	if u.Addresses[0].Type != domain.AddressTypeHome || u.Addresses[2].Type != domain.AddressTypeWork {
+		t.Fatalf("unexpected address types: %+v", u.Addresses)
+	}
+
+	secondHome.IsDefault = true
+	u, err = domain.NewUser("", "Alice", "alice@example.com", "", []domain.Address{home, secondHome, work})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if u.Addresses[0].IsDefault || !u.Addresses[1].IsDefault || u.Addresses[1].PromotedDefault || !u.Addresses[2].PromotedDefault {
+		t.Fatalf("expected the marked home address to stay the only home default, got %+v", u.Addresses)
+	}
+
+	work.IsDefault = true
+	u, err = domain.NewUser("", "Alice", "alice@example.com", "", []domain.Address{home, secondHome, work})
+	if err != nil {
+		t.Fatalf("expected one default per type to be accepted, got %v", err)
+	}
+	if !u.Addresses[2].IsDefault || u.Addresses[2].PromotedDefault {
+		t.Fatalf("expected the marked work address to stay marked, got %+v", u.Addresses)
+	}
+
+	home.IsDefault = true
+	if _, err := domain.NewUser("", "Alice", "alice@example.com", "", []domain.Address{home, secondHome, work}); err != domain.ErrMultipleDefaultAddresses {
+		t.Fatalf("expected ErrMultipleDefaultAddresses, got %v", err)
+	}
+
+	work.Type = "vacation"
+	if _, err := domain.NewUser("", "Alice", "alice@example.com", "", []domain.Address{work}); err != domain.ErrInvalidAddressType {
+		t.Fatalf("expected ErrInvalidAddressType, got %v", err)
+	}
+}
@@ -0,0 +1,26 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+)
+
+// UUIDPattern matches RFC 9562 UUIDs of versions 1-8 as well as the nil and max
+// UUIDs. It is also used as a PostgreSQL regular expression.
+const UUIDPattern = "^(?:[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-8][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}" +
+	"|00000000-0000-0000-0000-000000000000" +
+	"|[fF]{8}-[fF]{4}-[fF]{4}-[fF]{4}-[fF]{12})$"
+
+var uuidPattern = regexp.MustCompile(UUIDPattern)
+
+func IsUUID(value string) bool {
+	return uuidPattern.MatchString(value)
+}
+
+func ParseUUID(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !IsUUID(value) {
+		return "", ErrInvalidUUID
+	}
+	return strings.ToLower(value), nil
+}
@@ -0,0 +1,43 @@
+package user_test
+
+import (
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func TestParseUUID(t *testing.T) {
+	t.Parallel()
+
+	valid := map[string]string{
+		"a3f91a91-7fdd-43bf-bfd2-00bc02f6c53e":   "a3f91a91-7fdd-43bf-bfd2-00bc02f6c53e",
+		"1EF21D2F-1207-6660-8C4F-419EFBD44D48":   "1ef21d2f-1207-6660-8c4f-419efbd44d48",
+		" 0190f2a4-7c3b-7d2e-9f1a-2b3c4d5e6f70 ": "0190f2a4-7c3b-7d2e-9f1a-2b3c4d5e6f70",
+		"320c3d4d-cc00-875b-8ec9-32d5f69181c0":   "320c3d4d-cc00-875b-8ec9-32d5f69181c0",
+		"00000000-0000-0000-0000-000000000000":   "00000000-0000-0000-0000-000000000000",
+		"FFFFFFFF-FFFF-FFFF-FFFF-FFFFFFFFFFFF":   "ffffffff-ffff-ffff-ffff-ffffffffffff",
+	}
+	for input, want := range valid {
+		got, err := domain.ParseUUID(input)
+		if err != nil {
+			t.Fatalf("parse %q: unexpected error %v", input, err)
+		}
+		if got != want {
+			t.Fatalf("parse %q: expected %s, got %s", input, want, got)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"not-a-uuid",
+		"00000000-0000-0000-0000-000000000001",
+		"0190f2a4-7c3b-9d2e-9f1a-2b3c4d5e6f70",
+		"0190f2a4-7c3b-7d2e-cf1a-2b3c4d5e6f70",
+		"0190f2a47c3b7d2e9f1a2b3c4d5e6f70",
+	}
+	for _, input := range invalid {
+		if _, err := domain.ParseUUID(input); err != domain.ErrInvalidUUID {
+			t.Fatalf("parse %q: expected ErrInvalidUUID, got %v", input, err)
+		}
+	}
+}
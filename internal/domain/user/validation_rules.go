@@ -0,0 +1,250 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type ValidationRuleType string
+
+const (
+	ValidationRuleRequired ValidationRuleType = "required"
+	ValidationRuleRegex    ValidationRuleType = "regex"
+	ValidationRuleLength   ValidationRuleType = "length"
+	ValidationRuleEnum     ValidationRuleType = "enum"
+)
+
+const (
+	RuleFieldID            = "id"
+	RuleFieldName          = "name"
+	RuleFieldEmail         = "email"
+	RuleFieldEmailDomain   = "email_domain"
+	RuleFieldPhoneNumber   = "phone_number"
+	RuleFieldAddresses     = "addresses"
+	RuleFieldStreet        = "addresses.street"
+	RuleFieldCity          = "addresses.city"
+	RuleFieldState         = "addresses.state"
+	RuleFieldZipCode       = "addresses.zip_code"
+	RuleFieldCountry       = "addresses.country"
+	RuleFieldAddressType   = "addresses.type"
+	addressRuleFieldPrefix = "addresses."
+)
+
+var ruleFields = map[string]struct{}{
+	RuleFieldID:          {},
+	RuleFieldName:        {},
+	RuleFieldEmail:       {},
+	RuleFieldEmailDomain: {},
+	RuleFieldPhoneNumber: {},
+	RuleFieldAddresses:   {},
+	RuleFieldStreet:      {},
+	RuleFieldCity:        {},
+	RuleFieldState:       {},
+	RuleFieldZipCode:     {},
+	RuleFieldCountry:     {},
+	RuleFieldAddressType: {},
+}
+
+type RuleCondition struct {
+	Field  string
+	Equals []string
+}
+
+type ValidationRule struct {
+	ID      string
+	Type    ValidationRuleType
+	Field   string
+	Pattern string
+	Min     *int
+	Max     *int
+	Values  []string
+	When    *RuleCondition
+}
+
+type RuleViolation struct {
+	RuleID string
+	Field  string
+}
+
+type RuleSet struct {
+	rules []compiledRule
+}
+
+type compiledRule struct {
+	ValidationRule
+	pattern *regexp.Regexp
+}
+
+func NewRuleSet(rules []ValidationRule) (RuleSet, error) {
+	seen := make(map[string]struct{}, len(rules))
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		rule.ID = strings.TrimSpace(rule.ID)
+		if rule.ID == "" {
+			return RuleSet{}, ErrInvalidValidationRule
+		}
+		if _, ok := seen[rule.ID]; ok {
+			return RuleSet{}, ErrInvalidValidationRule
+		}
+		seen[rule.ID] = struct{}{}
+
+		if _, ok := ruleFields[rule.Field]; !ok {
+			return RuleSet{}, ErrInvalidValidationRule
+		}
+		if rule.When != nil {
+			if _, ok := ruleFields[rule.When.Field]; !ok || rule.When.Field == RuleFieldAddresses {
+				return RuleSet{}, ErrInvalidValidationRule
+			}
+		}
+
+		entry := compiledRule{ValidationRule: rule}
+		switch rule.Type {
+		case ValidationRuleRequired:
+		case ValidationRuleRegex:
+			pattern, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				return RuleSet{}, ErrInvalidValidationRule
+			}
+			entry.pattern = pattern
+		case ValidationRuleLength:
+			if rule.Min == nil && rule.Max == nil {
+				return RuleSet{}, ErrInvalidValidationRule
+			}
+			if rule.Min != nil && rule.Max != nil && *rule.Min > *rule.Max {
+				return RuleSet{}, ErrInvalidValidationRule
+			}
+		case ValidationRuleEnum:
+			if len(rule.Values) == 0 {
+				return RuleSet{}, ErrInvalidValidationRule
+			}
+		default:
+			return RuleSet{}, ErrInvalidValidationRule
+		}
+		if rule.Field == RuleFieldAddresses && rule.Type != ValidationRuleRequired && rule.Type != ValidationRuleLength {
+			return RuleSet{}, ErrInvalidValidationRule
+		}
+
+		compiled = append(compiled, entry)
+	}
+	return RuleSet{rules: compiled}, nil
+}
+
+func (s RuleSet) Empty() bool {
+	return len(s.rules) == 0
+}
+
+func (s RuleSet) Evaluate(u User) []RuleViolation {
+	var violations []RuleViolation
+	for _, rule := range s.rules {
+		if rule.When != nil && !rule.conditionHolds(u) {
+			continue
+		}
+		if !rule.check(u) {
+			violations = append(violations, RuleViolation{RuleID: rule.ID, Field: rule.Field})
+		}
+	}
+	return violations
+}
+
+func (r compiledRule) conditionHolds(u User) bool {
+	for _, value := range ruleFieldValues(u, r.When.Field) {
+		for _, expected := range r.When.Equals {
+			if strings.EqualFold(value, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r compiledRule) check(u User) bool {
+	if r.Field == RuleFieldAddresses {
+		count := len(u.Addresses)
+		if r.Type == ValidationRuleRequired {
+			return count > 0
+		}
+		return r.lengthWithin(count)
+	}
+
+	for _, value := range ruleFieldValues(u, r.Field) {
+		if !r.checkValue(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r compiledRule) checkValue(value string) bool {
+	value = strings.TrimSpace(value)
+	switch r.Type {
+	case ValidationRuleRequired:
+		return value != ""
+	case ValidationRuleRegex:
+		return value == "" || r.pattern.MatchString(value)
+	case ValidationRuleLength:
+		return value == "" || r.lengthWithin(utf8.RuneCountInString(value))
+	case ValidationRuleEnum:
+		if value == "" {
+			return true
+		}
+		for _, allowed := range r.Values {
+			if strings.EqualFold(value, allowed) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func (r compiledRule) lengthWithin(n int) bool {
+	if r.Min != nil && n < *r.Min {
+		return false
+	}
+	if r.Max != nil && n > *r.Max {
+		return false
+	}
+	return true
+}
+
+func ruleFieldValues(u User, field string) []string {
+	switch field {
+	case RuleFieldID:
+		return []string{u.ID}
+	case RuleFieldName:
+		return []string{u.Name}
+	case RuleFieldEmail:
+		return []string{u.Email}
+	case RuleFieldEmailDomain:
+		if at := strings.LastIndex(u.Email, "@"); at >= 0 {
+			return []string{u.Email[at+1:]}
+		}
+		return []string{""}
+	case RuleFieldPhoneNumber:
+		return []string{u.PhoneNumber}
+	}
+
+	if !strings.HasPrefix(field, addressRuleFieldPrefix) {
+		return nil
+	}
+	values := make([]string, 0, len(u.Addresses))
+	for _, address := range u.Addresses {
+		switch field {
+		case RuleFieldStreet:
+			values = append(values, address.Street)
+		case RuleFieldCity:
+			values = append(values, address.City)
+		case RuleFieldState:
+			values = append(values, address.State)
+		case RuleFieldZipCode:
+			values = append(values, address.ZipCode)
+		case RuleFieldCountry:
+			values = append(values, address.Country)
+		case RuleFieldAddressType:
+			values = append(values, string(address.Type))
+		}
+	}
+	return values
+}
@@ -0,0 +1,80 @@
+package user_test
+
+import (
+	"reflect"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestRuleSetEvaluate(t *testing.T) {
+	t.Parallel()
+
+	rules, err := domain.NewRuleSet([]domain.ValidationRule{
+		{ID: "phone-required", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldPhoneNumber},
+		{ID: "corporate-email", Type: domain.ValidationRuleEnum, Field: domain.RuleFieldEmailDomain, Values: []string{"example.com"}},
+		{ID: "max-addresses", Type: domain.ValidationRuleLength, Field: domain.RuleFieldAddresses, Max: intPtr(1)},
+		{ID: "name-length", Type: domain.ValidationRuleLength, Field: domain.RuleFieldName, Min: intPtr(2), Max: intPtr(5)},
+		{ID: "us-zip", Type: domain.ValidationRuleRegex, Field: domain.RuleFieldZipCode, Pattern: `^\d{5}$`, When: &domain.RuleCondition{Field: domain.RuleFieldCountry, Equals: []string{"US"}}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	valid := domain.User{
+		Name:        "Alice",
+		Email:       "alice@Example.com",
+		PhoneNumber: "+15125550100",
+		Addresses:   []domain.Address{{ZipCode: "78701", Country: "US"}},
+	}
+	if violations := rules.Evaluate(valid); len(violations) != 0 {
+		t.Fatalf("expected no violations, got %+v", violations)
+	}
+
+	invalid := domain.User{
+		Name:      "Bartholomew",
+		Email:     "bart@other.org",
+		Addresses: []domain.Address{{ZipCode: "K1A 0B1", Country: "US"}, {ZipCode: "K1A 0B1", Country: "CA"}},
+	}
+	var got []string
+	for _, violation := range rules.Evaluate(invalid) {
+		got = append(got, violation.RuleID)
+	}
+	want := []string{"phone-required", "corporate-email", "max-addresses", "name-length", "us-zip"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected violations %v, got %v", want, got)
+	}
+
+	invalid.Addresses = []domain.Address{{ZipCode: "K1A 0B1", Country: "CA"}}
+	for _, violation := range rules.Evaluate(invalid) {
+		if violation.RuleID == "us-zip" {
+			t.Fatal("expected conditional rule to be skipped for other countries")
+		}
+	}
+}
+
+func TestNewRuleSetInvalid(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string][]domain.ValidationRule{
+		"missing id":      {{Type: domain.ValidationRuleRequired, Field: domain.RuleFieldName}},
+		"duplicate id":    {{ID: "a", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldName}, {ID: "a", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldEmail}},
+		"unknown field":   {{ID: "a", Type: domain.ValidationRuleRequired, Field: "nickname"}},
+		"unknown type":    {{ID: "a", Type: "unique", Field: domain.RuleFieldName}},
+		"bad regex":       {{ID: "a", Type: domain.ValidationRuleRegex, Field: domain.RuleFieldName, Pattern: "("}},
+		"empty length":    {{ID: "a", Type: domain.ValidationRuleLength, Field: domain.RuleFieldName}},
+		"inverted length": {{ID: "a", Type: domain.ValidationRuleLength, Field: domain.RuleFieldName, Min: intPtr(3), Max: intPtr(1)}},
+		"empty enum":      {{ID: "a", Type: domain.ValidationRuleEnum, Field: domain.RuleFieldName}},
+		"addresses regex": {{ID: "a", Type: domain.ValidationRuleRegex, Field: domain.RuleFieldAddresses, Pattern: "x"}},
+		"unknown when":    {{ID: "a", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldName, When: &domain.RuleCondition{Field: "nickname"}}},
+	}
+	for name, rules := range cases {
+		if _, err := domain.NewRuleSet(rules); err != domain.ErrInvalidValidationRule {
+			t.Fatalf("%s: expected ErrInvalidValidationRule, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	xmlNamePattern   = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_.-]*:)?[A-Za-z_][A-Za-z0-9_.-]*$`)
+	xmlPrefixPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+)
+
+type XMLMapping struct {
+	RecordPath    string
+	Fields        map[string]string
+	AddressPath   string
+	AddressFields map[string]string
+	Namespaces    map[string]string
+}
+
+func (m XMLMapping) Empty() bool {
+	return m.RecordPath == "" && m.AddressPath == "" && len(m.Fields) == 0 && len(m.AddressFields) == 0 && len(m.Namespaces) == 0
+}
+
+func ParseXMLMapping(mapping XMLMapping, format ImportFormat) (XMLMapping, error) {
+	if mapping.Empty() {
+		return XMLMapping{}, nil
+	}
+	if format != ImportFormatXML {
+		return XMLMapping{}, ErrInvalidXMLMapping
+	}
+
+	parsed := XMLMapping{
+		RecordPath:  strings.TrimSpace(mapping.RecordPath),
+		AddressPath: strings.TrimSpace(mapping.AddressPath),
+	}
+	if len(mapping.Namespaces) > 0 {
+		parsed.Namespaces = make(map[string]string, len(mapping.Namespaces))
+		for prefix, uri := range mapping.Namespaces {
+			prefix, uri = strings.TrimSpace(prefix), strings.TrimSpace(uri)
+			if !xmlPrefixPattern.MatchString(prefix) || uri == "" {
+				return XMLMapping{}, ErrInvalidXMLMapping
+			}
+			parsed.Namespaces[prefix] = uri
+		}
+	}
+
+	if parsed.RecordPath != "" {
+		if !strings.HasPrefix(parsed.RecordPath, "/") || !validXMLPath(parsed.RecordPath[1:], parsed.Namespaces, false) {
+			return XMLMapping{}, ErrInvalidXMLMapping
+		}
+	}
+	if parsed.AddressPath != "" && !validXMLPath(parsed.AddressPath, parsed.Namespaces, false) {
+		return XMLMapping{}, ErrInvalidXMLMapping
+	}
+
+	fields, err := parseXMLFieldPaths(mapping.Fields, parsed.Namespaces)
+	if err != nil {
+		return XMLMapping{}, err
+	}
+	addressFields, err := parseXMLFieldPaths(mapping.AddressFields, parsed.Namespaces)
+	if err != nil {
+		return XMLMapping{}, err
+	}
+	parsed.Fields = fields
+	parsed.AddressFields = addressFields
+	return parsed, nil
+}
+
+func parseXMLFieldPaths(fields map[string]string, namespaces map[string]string) (map[string]string, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	parsed := make(map[string]string, len(fields))
+	for field, path := range fields {
+		field, path = strings.TrimSpace(field), strings.TrimSpace(path)
+		if !attributeKeyPattern.MatchString(field) || !validXMLPath(path, namespaces, true) {
+			return nil, ErrInvalidXMLMapping
+		}
+		parsed[field] = path
+	}
+	return parsed, nil
+}
+
+func validXMLPath(path string, namespaces map[string]string, allowAttribute bool) bool {
+	if path == "" {
+		return false
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, "@") {
+			if !allowAttribute || i != len(segments)-1 {
+				return false
+			}
+			segment = segment[1:]
+		}
+		if !xmlNamePattern.MatchString(segment) {
+			return false
+		}
+		if prefix, _, ok := strings.Cut(segment, ":"); ok {
+			if _, declared := namespaces[prefix]; !declared {
+				return false
+			}
+		}
+	}
+	return true
+}
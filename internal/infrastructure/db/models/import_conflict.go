@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type ImportConflict struct {
+	ID          int64  `gorm:"primaryKey"`
+	JobID       string `gorm:"type:uuid;not null"`
+	RowIndex    int64  `gorm:"not null"`
+	ExternalID  string `gorm:"type:text;not null"`
+	IDUserID    string `gorm:"type:uuid;not null"`
+	EmailUserID string `gorm:"type:uuid;not null"`
+	Resolution  string `gorm:"type:text;not null"`
+
+	LocatorRow    int64 `gorm:"not null;default:0"`
+	LocatorLine   int64 `gorm:"not null;default:0"`
+	LocatorOffset int64 `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+}
+
+func (ImportConflict) TableName() string {
+	return "import_conflicts"
+}
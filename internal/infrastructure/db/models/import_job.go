@@ -3,17 +3,27 @@ package models
 import "time"
 
 type ImportJob struct {
-	ID                string  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	SourcePath        string  `gorm:"type:text;not null"`
-	Status            string  `gorm:"type:text;not null"`
-	ProgressProcessed int64   `gorm:"not null;default:0"`
-	ProgressTotal     int64   `gorm:"not null;default:0"`
-	ImportedCount     int64   `gorm:"not null;default:0"`
-	UpdatedCount      int64   `gorm:"not null;default:0"`
-	SkippedCount      int64   `gorm:"not null;default:0"`
-	FailedCount       int64   `gorm:"not null;default:0"`
-	Attempts          int     `gorm:"not null;default:0"`
-	MaxAttempts       int     `gorm:"not null;default:5"`
+	ID                string               `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	SourcePath        string               `gorm:"type:text;not null"`
+	Status            string               `gorm:"type:text;not null"`
+	ProgressProcessed int64                `gorm:"not null;default:0"`
+	ProgressTotal     int64                `gorm:"not null;default:0"`
+	ImportedCount     int64                `gorm:"not null;default:0"`
+	UpdatedCount      int64                `gorm:"not null;default:0"`
+	SkippedCount      int64                `gorm:"not null;default:0"`
+	FailedCount       int64                `gorm:"not null;default:0"`
+	WarningCount      int64                `gorm:"not null;default:0"`
+	Attempts          int                  `gorm:"not null;default:0"`
+	MaxAttempts       int                  `gorm:"not null;default:5"`
+	Options           ImportJobOptions     `gorm:"type:jsonb;not null;default:'{}'"`
+	UnknownFields     ImportJobFieldCounts `gorm:"type:jsonb;not null;default:'{}'"`
+	Metadata          ImportJobMetadata    `gorm:"type:jsonb;not null;default:'{}'"`
+	ChunkStats        ImportJobChunkStats  `gorm:"type:jsonb;not null;default:'{}'"`
+	ExpectedCount     *int64
+	ParentID          *string `gorm:"type:uuid"`
+	PartitionIndex    *int
+	RangeStart        *int64
+	RangeEnd          *int64
 	ErrorMessage      *string `gorm:"type:text"`
 	HeartbeatAt       *time.Time
 	LeaseExpiresAt    *time.Time
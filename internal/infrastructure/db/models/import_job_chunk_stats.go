@@ -0,0 +1,45 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type ImportJobChunkStats struct {
+	Chunks        int64 `json:"chunks"`
+	Rows          int64 `json:"rows"`
+	Bytes         int64 `json:"bytes"`
+	MaxChunkRows  int64 `json:"max_chunk_rows"`
+	MaxChunkBytes int64 `json:"max_chunk_bytes"`
+	WriteMillis   int64 `json:"write_ms"`
+	MaxHeapBytes  int64 `json:"max_heap_bytes"`
+}
+
+func (s ImportJobChunkStats) Value() (driver.Value, error) {
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return nil, fmt.Errorf("marshal import job chunk stats: %w", err)
+	}
+	return string(payload), nil
+}
+
+func (s *ImportJobChunkStats) Scan(value any) error {
+	var payload []byte
+	switch v := value.(type) {
+	case nil:
+		*s = ImportJobChunkStats{}
+		return nil
+	case []byte:
+		payload = v
+	case string:
+		payload = []byte(v)
+	default:
+		return fmt.Errorf("scan import job chunk stats: unsupported type %T", value)
+	}
+
+	if err := json.Unmarshal(payload, s); err != nil {
+		return fmt.Errorf("unmarshal import job chunk stats: %w", err)
+	}
+	return nil
+}
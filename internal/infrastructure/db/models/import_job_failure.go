@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ImportJobFailure is a row a job failed, skipped or warned about. The key
+// makes rewriting the same entries on every progress checkpoint a no-op.
+type ImportJobFailure struct {
+	JobID         string `gorm:"type:uuid;primaryKey"`
+	Kind          string `gorm:"type:text;primaryKey"`
+	RowIndex      int64  `gorm:"primaryKey"`
+	Reason        string `gorm:"type:text;primaryKey"`
+	RuleID        string `gorm:"type:text;primaryKey"`
+	Field         string `gorm:"type:text;primaryKey"`
+	LocatorRow    int64  `gorm:"not null;default:0"`
+	LocatorLine   int64  `gorm:"not null;default:0"`
+	LocatorOffset int64  `gorm:"not null;default:0"`
+	CreatedAt     time.Time
+}
+
+func (ImportJobFailure) TableName() string {
+	return "import_job_failures"
+}
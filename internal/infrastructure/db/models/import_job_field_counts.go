@@ -0,0 +1,42 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type ImportJobFieldCounts map[string]int64
+
+func (c ImportJobFieldCounts) Value() (driver.Value, error) {
+	if c == nil {
+		return "{}", nil
+	}
+	payload, err := json.Marshal(map[string]int64(c))
+	if err != nil {
+		return nil, fmt.Errorf("marshal import job field counts: %w", err)
+	}
+	return string(payload), nil
+}
+
+func (c *ImportJobFieldCounts) Scan(value any) error {
+	var payload []byte
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		payload = v
+	case string:
+		payload = []byte(v)
+	default:
+		return fmt.Errorf("scan import job field counts: unsupported type %T", value)
+	}
+
+	var counts map[string]int64
+	if err := json.Unmarshal(payload, &counts); err != nil {
+		return fmt.Errorf("unmarshal import job field counts: %w", err)
+	}
+	*c = counts
+	return nil
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type ImportJobMetadata map[string]any
+
+func (m ImportJobMetadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	payload, err := json.Marshal(map[string]any(m))
+	if err != nil {
+		return nil, fmt.Errorf("marshal import job metadata: %w", err)
+	}
+	return string(payload), nil
+}
+
+func (m *ImportJobMetadata) Scan(value any) error {
+	var payload []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		payload = v
+	case string:
+		payload = []byte(v)
+	default:
+		return fmt.Errorf("scan import job metadata: unsupported type %T", value)
+	}
+
+	var metadata map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &metadata); err != nil {
+		return fmt.Errorf("unmarshal import job metadata: %w", err)
+	}
+	if len(metadata) == 0 {
+		*m = nil
+		return nil
+	}
+	*m = make(ImportJobMetadata, len(metadata))
+	for key, raw := range metadata {
+		(*m)[key] = raw
+	}
+	return nil
+}
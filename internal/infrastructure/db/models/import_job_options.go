@@ -0,0 +1,99 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type ImportJobOptions struct {
+	AddressStrategy string                  `json:"address_strategy,omitempty"`
+	UpdatePolicies  ImportJobUpdatePolicies `json:"update_policies,omitempty"`
+	Source          string                  `json:"source,omitempty"`
+	ConflictPolicy  string                  `json:"conflict_policy,omitempty"`
+	Profile         string                  `json:"profile,omitempty"`
+
+	AddressValidation ImportJobAddressValidation `json:"address_validation,omitempty"`
+	OversizePolicy    string                     `json:"oversize_policy,omitempty"`
+	PhoneRegion       string                     `json:"phone_region,omitempty"`
+	AttributeStrategy string                     `json:"attribute_strategy,omitempty"`
+	AttributeFields   []string                   `json:"attribute_fields,omitempty"`
+	SchemaMode        string                     `json:"schema_mode,omitempty"`
+
+	Format           string               `json:"format,omitempty"`
+	Encoding         string               `json:"encoding,omitempty"`
+	Sheet            string               `json:"sheet,omitempty"`
+	XML              ImportJobXMLMapping  `json:"xml,omitempty"`
+	FieldMapping     map[string]string    `json:"field_mapping,omitempty"`
+	Transforms       []ImportJobTransform `json:"field_transforms,omitempty"`
+	Rules            ImportProfileRules   `json:"rules,omitempty"`
+	ChunkSize        int                  `json:"chunk_size,omitempty"`
+	ChunkConcurrency int                  `json:"chunk_concurrency,omitempty"`
+	Partitions       int                  `json:"partitions,omitempty"`
+	MaxAttempts      int                  `json:"max_attempts,omitempty"`
+
+	RecordsPointer string            `json:"records_pointer,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+type ImportJobTransform struct {
+	Target    string            `json:"target"`
+	Source    string            `json:"source,omitempty"`
+	Sources   []string          `json:"sources,omitempty"`
+	Separator string            `json:"separator,omitempty"`
+	Split     string            `json:"split,omitempty"`
+	Index     int               `json:"index,omitempty"`
+	Trim      bool              `json:"trim,omitempty"`
+	Case      string            `json:"case,omitempty"`
+	Lookup    map[string]string `json:"lookup,omitempty"`
+	Default   *string           `json:"default,omitempty"`
+}
+
+type ImportJobXMLMapping struct {
+	RecordPath    string            `json:"record_path,omitempty"`
+	Fields        map[string]string `json:"fields,omitempty"`
+	AddressPath   string            `json:"address_path,omitempty"`
+	AddressFields map[string]string `json:"address_fields,omitempty"`
+	Namespaces    map[string]string `json:"namespaces,omitempty"`
+}
+
+type ImportJobUpdatePolicies struct {
+	Name        string `json:"name,omitempty"`
+	Email       string `json:"email,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+}
+
+type ImportJobAddressValidation struct {
+	Country     string `json:"country,omitempty"`
+	PostalCode  string `json:"postal_code,omitempty"`
+	Subdivision string `json:"subdivision,omitempty"`
+	Normalize   bool   `json:"normalize,omitempty"`
+}
+
+func (o ImportJobOptions) Value() (driver.Value, error) {
+	payload, err := json.Marshal(o)
+	if err != nil {
+		return nil, fmt.Errorf("marshal import job options: %w", err)
+	}
+	return string(payload), nil
+}
+
+func (o *ImportJobOptions) Scan(value any) error {
+	var payload []byte
+	switch v := value.(type) {
+	case nil:
+		*o = ImportJobOptions{}
+		return nil
+	case []byte:
+		payload = v
+	case string:
+		payload = []byte(v)
+	default:
+		return fmt.Errorf("scan import job options: unsupported type %T", value)
+	}
+
+	if err := json.Unmarshal(payload, o); err != nil {
+		return fmt.Errorf("unmarshal import job options: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type ImportProfile struct {
+	ID        string             `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name      string             `gorm:"type:text;not null;uniqueIndex"`
+	Rules     ImportProfileRules `gorm:"type:jsonb;not null;default:'[]'"`
+	Options   ImportJobOptions   `gorm:"type:jsonb;not null;default:'{}'"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (ImportProfile) TableName() string {
+	return "import_profiles"
+}
+
+type ImportProfileRules []ImportProfileRule
+
+type ImportProfileRule struct {
+	ID      string                      `json:"id"`
+	Type    string                      `json:"type"`
+	Field   string                      `json:"field"`
+	Pattern string                      `json:"pattern,omitempty"`
+	Min     *int                        `json:"min,omitempty"`
+	Max     *int                        `json:"max,omitempty"`
+	Values  []string                    `json:"values,omitempty"`
+	When    *ImportProfileRuleCondition `json:"when,omitempty"`
+}
+
+type ImportProfileRuleCondition struct {
+	Field  string   `json:"field"`
+	Equals []string `json:"equals"`
+}
+
+func (r ImportProfileRules) Value() (driver.Value, error) {
+	if r == nil {
+		r = ImportProfileRules{}
+	}
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return nil, fmt.Errorf("marshal import profile rules: %w", err)
+	}
+	return string(payload), nil
+}
+
+func (r *ImportProfileRules) Scan(value any) error {
+	var payload []byte
+	switch v := value.(type) {
+	case nil:
+		*r = nil
+		return nil
+	case []byte:
+		payload = v
+	case string:
+		payload = []byte(v)
+	default:
+		return fmt.Errorf("scan import profile rules: unsupported type %T", value)
+	}
+
+	if err := json.Unmarshal(payload, r); err != nil {
+		return fmt.Errorf("unmarshal import profile rules: %w", err)
+	}
+	return nil
+}
@@ -5,11 +5,16 @@ import "time"
 type User struct {
 	ID          string    `gorm:"type:uuid;primaryKey"`
 	Name        string    `gorm:"size:255;not null"`
-	Email       string    `gorm:"size:320;not null;uniqueIndex"`
+	Email       string    `gorm:"size:320"`
+	EmailKey    string    `gorm:"size:320;uniqueIndex"`
 	PhoneNumber string    `gorm:"size:32;not null"`
 	Addresses   []Address `gorm:"foreignKey:UserID"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+
+	PhoneNumberRaw   *string
+	Attributes       UserAttributes `gorm:"type:jsonb;not null;default:'{}'"`
+	SourceModifiedAt *time.Time
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
 }
 
 func (User) TableName() string {
@@ -24,6 +29,8 @@ type Address struct {
 	State     string `gorm:"size:120;not null"`
 	ZipCode   string `gorm:"size:20;not null"`
 	Country   string `gorm:"size:120;not null"`
+	Type      string `gorm:"size:20;not null;default:home"`
+	IsDefault bool   `gorm:"not null;default:false"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+type UserAttributes map[string]any
+
+func (a UserAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return "{}", nil
+	}
+	payload, err := json.Marshal(map[string]any(a))
+	if err != nil {
+		return nil, fmt.Errorf("marshal user attributes: %w", err)
+	}
+	return string(payload), nil
+}
+
+func (a *UserAttributes) Scan(value any) error {
+	var payload []byte
+	switch v := value.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		payload = v
+	case string:
+		payload = []byte(v)
+	default:
+		return fmt.Errorf("scan user attributes: unsupported type %T", value)
+	}
+
+	var attributes map[string]any
+	if err := json.Unmarshal(payload, &attributes); err != nil {
+		return fmt.Errorf("unmarshal user attributes: %w", err)
+	}
+	*a = attributes
+	return nil
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+type UserExternalID struct {
+	Source     string `gorm:"type:text;primaryKey"`
+	ExternalID string `gorm:"type:text;primaryKey"`
+	UserID     string `gorm:"type:uuid;index;not null"`
+	CreatedAt  time.Time
+}
+
+func (UserExternalID) TableName() string {
+	return "user_external_ids"
+}
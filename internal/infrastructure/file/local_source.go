@@ -22,14 +22,48 @@ func NewLocalSource(baseDir string) *LocalSource {
 func (s *LocalSource) Open(ctx context.Context, sourcePath string) (io.ReadCloser, error) {
 	_ = ctx
 
-	path := sourcePath
-	if !filepath.IsAbs(path) {
-		path = filepath.Join(s.BaseDir, sourcePath)
+	path := s.resolve(sourcePath)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open file %s: %w", path, err)
 	}
+	return file, nil
+}
 
+func (s *LocalSource) Size(ctx context.Context, sourcePath string) (int64, error) {
+	_ = ctx
+
+	path := s.resolve(sourcePath)
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("stat file %s: %w", path, err)
+	}
+	return info.Size(), nil
+}
+
+// OpenRange returns the bytes of the file in [start, end).
+func (s *LocalSource) OpenRange(ctx context.Context, sourcePath string, start, end int64) (io.ReadCloser, error) {
+	_ = ctx
+
+	path := s.resolve(sourcePath)
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("open file %s: %w", path, err)
 	}
-	return file, nil
+	return &rangeReader{
+		Reader: io.NewSectionReader(file, start, max(end-start, 0)),
+		Closer: file,
+	}, nil
+}
+
+func (s *LocalSource) resolve(sourcePath string) string {
+	if filepath.IsAbs(sourcePath) {
+		return sourcePath
+	}
+	return filepath.Join(s.BaseDir, sourcePath)
+}
+
+type rangeReader struct {
+	io.Reader
+	io.Closer
 }
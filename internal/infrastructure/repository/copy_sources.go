@@ -0,0 +1,104 @@
+package repository
+
+import (
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+var (
+	stagedUserColumns    = []string{"job_id", "row_index", "external_id", "name", "email", "email_key", "phone_number", "phone_number_raw", "source_modified_at", "attributes", "source_position"}
+	stagedAddressColumns = []string{"job_id", "row_index", "user_external_id", "user_email", "street", "city", "state", "zip_code", "country", "type", "is_default", "default_promoted"}
+)
+
+// userCopySource streams a chunk into stg_users without building a row slice
+// per user. Values reuses one buffer, which pgx encodes before calling Next.
+type userCopySource struct {
+	jobID string
+	users []domain.User
+	index int
+	row   []any
+}
+
+func newUserCopySource(jobID string, users []domain.User) *userCopySource {
+	return &userCopySource{jobID: jobID, users: users, index: -1, row: make([]any, len(stagedUserColumns))}
+}
+
+func (s *userCopySource) Next() bool {
+	s.index++
+	return s.index < len(s.users)
+}
+
+func (s *userCopySource) Values() ([]any, error) {
+	user := &s.users[s.index]
+	s.row[0] = s.jobID
+	s.row[1] = int64(s.index)
+	s.row[2] = nullableText(user.ID)
+	s.row[3] = user.Name
+	s.row[4] = user.Email
+	s.row[5] = emailKey(*user)
+	s.row[6] = user.PhoneNumber
+	s.row[7] = nullableText(user.PhoneNumberRaw)
+	s.row[8] = nullableTime(user.SourceModifiedAt)
+	s.row[9] = nullableAttributes(user.Attributes)
+	s.row[10] = user.SourcePosition
+	return s.row, nil
+}
+
+func (s *userCopySource) Err() error {
+	return nil
+}
+
+// addressCopySource streams the addresses of a chunk into stg_addresses.
+type addressCopySource struct {
+	jobID   string
+	users   []domain.User
+	user    int
+	address int
+	row     []any
+}
+
+func newAddressCopySource(jobID string, users []domain.User) *addressCopySource {
+	return &addressCopySource{jobID: jobID, users: users, address: -1, row: make([]any, len(stagedAddressColumns))}
+}
+
+func (s *addressCopySource) Next() bool {
+	s.address++
+	for s.user < len(s.users) {
+		if s.address < len(s.users[s.user].Addresses) {
+			return true
+		}
+		s.user++
+		s.address = 0
+	}
+	return false
+}
+
+func (s *addressCopySource) Values() ([]any, error) {
+	user := &s.users[s.user]
+	address := &user.Addresses[s.address]
+	s.row[0] = s.jobID
+	s.row[1] = int64(s.user)
+	s.row[2] = nullableText(user.ID)
+	s.row[3] = user.Email
+	s.row[4] = address.Street
+	s.row[5] = address.City
+	s.row[6] = address.State
+	s.row[7] = address.ZipCode
+	s.row[8] = address.Country
+	s.row[9] = addressType(*address)
+	s.row[10] = address.IsDefault
+	s.row[11] = address.PromotedDefault
+	return s.row, nil
+}
+
+func (s *addressCopySource) Err() error {
+	return nil
+}
+
+func hasAddresses(users []domain.User) bool {
+	for _, user := range users {
+		if len(user.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
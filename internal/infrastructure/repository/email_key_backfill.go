@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+)
+
+const emailKeyBackfillBatchSize = 10000
+
+// EmailKeyCollision is a key shared by several users. The first user, the
+// oldest, keeps the key and the others are left without one.
+type EmailKeyCollision struct {
+	EmailKey string
+	UserIDs  []string
+	Emails   []string
+}
+
+type EmailKeyBackfillResult struct {
+	Users   int64
+	Rekeyed int64
+	// InvalidUserIDs lists users whose email no longer normalizes; their key is
+	// left as it was.
+	InvalidUserIDs []string
+	Collisions     []EmailKeyCollision
+}
+
+// EmailKeyBackfill recomputes users.email_key with the normalizer the importer
+// runs with, so that rows matched by email find users stored before the
+// normalization rules changed.
+type EmailKeyBackfill struct {
+	pool *pgxpool.Pool
+}
+
+func NewEmailKeyBackfill(pool *pgxpool.Pool) *EmailKeyBackfill {
+	return &EmailKeyBackfill{pool: pool}
+}
+
+func (b *EmailKeyBackfill) Run(ctx context.Context, normalizer domain.EmailNormalizer) (EmailKeyBackfillResult, error) {
+	tx, err := b.pool.Begin(ctx)
+	if err != nil {
+		return EmailKeyBackfillResult{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Imports cannot change emails while the keys are recomputed.
+	if _, err := tx.Exec(ctx, `LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return EmailKeyBackfillResult{}, fmt.Errorf("lock users: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `CREATE TEMP TABLE email_keys (id UUID PRIMARY KEY, email_key TEXT NOT NULL) ON COMMIT DROP`); err != nil {
+		return EmailKeyBackfillResult{}, fmt.Errorf("create email keys: %w", err)
+	}
+
+	result, err := computeEmailKeys(ctx, tx, normalizer)
+	if err != nil {
+		return EmailKeyBackfillResult{}, err
+	}
+	if _, err := tx.Exec(ctx, `CREATE INDEX ON email_keys (email_key)`); err != nil {
+		return EmailKeyBackfillResult{}, fmt.Errorf("index email keys: %w", err)
+	}
+
+	result.Collisions, err = recordEmailKeyCollisions(ctx, tx)
+	if err != nil {
+		return EmailKeyBackfillResult{}, err
+	}
+	result.Rekeyed, err = applyEmailKeys(ctx, tx)
+	if err != nil {
+		return EmailKeyBackfillResult{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return EmailKeyBackfillResult{}, fmt.Errorf("commit tx: %w", err)
+	}
+	return result, nil
+}
+
+// computeEmailKeys walks the users in batches and copies the key of every
+// user into email_keys. Users whose email no longer normalizes keep their
+// current key so that it still counts towards collisions.
+func computeEmailKeys(ctx context.Context, tx pgx.Tx, normalizer domain.EmailNormalizer) (EmailKeyBackfillResult, error) {
+	var result EmailKeyBackfillResult
+
+	if _, err := tx.Exec(ctx, `DECLARE backfill_users NO SCROLL CURSOR FOR SELECT id::text, email, email_key FROM users WHERE email IS NOT NULL`); err != nil {
+		return result, fmt.Errorf("declare users cursor: %w", err)
+	}
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf(`FETCH FORWARD %d FROM backfill_users`, emailKeyBackfillBatchSize))
+		if err != nil {
+			return result, fmt.Errorf("fetch users: %w", err)
+		}
+
+		var keys [][]any
+		for rows.Next() {
+			var id, email string
+			var current *string
+			if err := rows.Scan(&id, &email, &current); err != nil {
+				rows.Close()
+				return result, fmt.Errorf("scan user: %w", err)
+			}
+			result.Users++
+
+			_, key, err := normalizer.Normalize(email)
+			if err != nil {
+				result.InvalidUserIDs = append(result.InvalidUserIDs, id)
+				if current == nil {
+					continue
+				}
+				key = *current
+			}
+			keys = append(keys, []any{id, key})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return result, fmt.Errorf("fetch users: %w", err)
+		}
+		if len(keys) == 0 {
+			return result, nil
+		}
+
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"email_keys"}, []string{"id", "email_key"}, pgx.CopyFromRows(keys)); err != nil {
+			return result, fmt.Errorf("copy email keys: %w", err)
+		}
+	}
+}
+
+// recordEmailKeyCollisions replaces the collisions of the recomputed keys in
+// user_email_key_collisions, dropping the entries that no longer collide.
+func recordEmailKeyCollisions(ctx context.Context, tx pgx.Tx) ([]EmailKeyCollision, error) {
+	if _, err := tx.Exec(ctx, `
+DELETE FROM user_email_key_collisions
+WHERE email_key NOT IN (SELECT email_key FROM email_keys GROUP BY email_key HAVING COUNT(*) > 1)
+`); err != nil {
+		return nil, fmt.Errorf("delete resolved email key collisions: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, `
+INSERT INTO user_email_key_collisions (email_key, user_ids, emails)
+SELECT k.email_key, array_agg(u.id ORDER BY u.created_at, u.id), array_agg(u.email ORDER BY u.created_at, u.id)
+FROM email_keys k
+JOIN users u ON u.id = k.id
+GROUP BY k.email_key
+HAVING COUNT(*) > 1
+ON CONFLICT (email_key) DO UPDATE
+  SET user_ids = EXCLUDED.user_ids,
+      emails = EXCLUDED.emails,
+      detected_at = NOW()
+RETURNING email_key, user_ids::text[], emails
+`)
+	if err != nil {
+		return nil, fmt.Errorf("record email key collisions: %w", err)
+	}
+	defer rows.Close()
+
+	var collisions []EmailKeyCollision
+	for rows.Next() {
+		var collision EmailKeyCollision
+		if err := rows.Scan(&collision.EmailKey, &collision.UserIDs, &collision.Emails); err != nil {
+			return nil, fmt.Errorf("scan email key collision: %w", err)
+		}
+		collisions = append(collisions, collision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("record email key collisions: %w", err)
+	}
+	return collisions, nil
+}
+
+// applyEmailKeys clears every key that changes or is lost to a collision
+// before setting the new keys, so the unique index never sees a key twice.
+func applyEmailKeys(ctx context.Context, tx pgx.Tx) (int64, error) {
+	if _, err := tx.Exec(ctx, `
+UPDATE users u
+SET email_key = NULL
+FROM email_keys k
+WHERE u.id = k.id
+  AND u.email_key IS NOT NULL
+  AND (
+    u.email_key <> k.email_key
+    OR EXISTS (SELECT 1 FROM user_email_key_collisions c WHERE c.email_key = k.email_key AND c.user_ids[1] <> k.id)
+  )
+`); err != nil {
+		return 0, fmt.Errorf("clear email keys: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+UPDATE users u
+SET email_key = k.email_key
+FROM email_keys k
+WHERE u.id = k.id
+  AND u.email_key IS NULL
+  AND NOT EXISTS (SELECT 1 FROM user_email_key_collisions c WHERE c.email_key = k.email_key AND c.user_ids[1] <> k.id)
+`)
+	if err != nil {
+		return 0, fmt.Errorf("set email keys: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,93 @@
+package repository_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+)
+
+func TestEmailKeyBackfillRecomputesKeysIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	const (
+		olderJane = "8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b01"
+		newerJane = "8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b02"
+		bob       = "8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b03"
+	)
+	seedSQL := `
+    INSERT INTO users (id, name, email, email_key, phone_number, created_at) VALUES
+      ('8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b01', 'Jane', 'Jane.Doe@gmail.com', 'jane.doe@gmail.com', '+15125550100', NOW() - INTERVAL '2 days'),
+      ('8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b02', 'Jane', 'janedoe+news@gmail.com', 'janedoe+news@gmail.com', '+15125550100', NOW() - INTERVAL '1 day'),
+      ('8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b03', 'Bob', 'Bob@Example.com', NULL, '+15125550100', NOW());
+    INSERT INTO user_email_key_collisions (email_key, user_ids, emails)
+    VALUES ('resolved@example.com', ARRAY['8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b03']::uuid[], ARRAY['Bob@Example.com']);
+    `
+	if err := gdb.Exec(seedSQL).Error; err != nil {
+		t.Fatalf("seed users failed: %v", err)
+	}
+
+	backfill := repository.NewEmailKeyBackfill(pool)
+	result, err := backfill.Run(context.Background(), domain.NewEmailNormalizer(domain.GmailEmailRule))
+	if err != nil {
+		t.Fatalf("backfill failed: %v", err)
+	}
+	wantCollisions := []repository.EmailKeyCollision{{
+		EmailKey: "janedoe@gmail.com",
+		UserIDs:  []string{olderJane, newerJane},
+		Emails:   []string{"Jane.Doe@gmail.com", "janedoe+news@gmail.com"},
+	}}
+	if result.Users != 3 || result.Rekeyed != 2 || !reflect.DeepEqual(result.Collisions, wantCollisions) {
+		t.Fatalf("unexpected backfill result: %+v", result)
+	}
+
+	var keys []struct {
+		ID       string
+		EmailKey *string
+	}
+	if err := gdb.Raw("SELECT id, email_key FROM users ORDER BY id").Scan(&keys).Error; err != nil {
+		t.Fatalf("select keys failed: %v", err)
+	}
+	if len(keys) != 3 || keys[0].EmailKey == nil || *keys[0].EmailKey != "janedoe@gmail.com" || keys[1].EmailKey != nil || keys[2].EmailKey == nil || *keys[2].EmailKey != "bob@example.com" {
+		t.Fatalf("expected the older user to keep the gmail key and bob to be keyed, got %+v", keys)
+	}
+
+	var stored []string
+	if err := gdb.Raw("SELECT email_key FROM user_email_key_collisions ORDER BY email_key").Scan(&stored).Error; err != nil {
+		t.Fatalf("select collisions failed: %v", err)
+	}
+	if !reflect.DeepEqual(stored, []string{"janedoe@gmail.com"}) {
+		t.Fatalf("expected only the current collision to be listed, got %v", stored)
+	}
+
+	again, err := backfill.Run(context.Background(), domain.NewEmailNormalizer(domain.GmailEmailRule))
+	if err != nil {
+		t.Fatalf("second backfill failed: %v", err)
+	}
+	if again.Rekeyed != 0 || len(again.Collisions) != 1 {
+		t.Fatalf("expected a second run to change nothing, got %+v", again)
+	}
+
+	// The key owner takes the exact email of the user left without a key.
+	importer := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	if _, err := importer.ImportChunk(context.Background(), "8b1f0e59-0c1d-4a8e-9a5e-0d6f1c7a2b10", domain.ImportOptions{}, []domain.User{{
+		Name:        "Jane Doe",
+		Email:       "janedoe+news@gmail.com",
+		EmailKey:    "janedoe@gmail.com",
+		PhoneNumber: "+15125550100",
+	}}); err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	var owner struct {
+		Name  string
+		Email string
+	}
+	if err := gdb.Raw("SELECT name, email FROM users WHERE id = ?", olderJane).Scan(&owner).Error; err != nil {
+		t.Fatalf("select owner failed: %v", err)
+	}
+	if owner.Name != "Jane Doe" || owner.Email != "janedoe+news@gmail.com" {
+		t.Fatalf("expected the key owner to be updated, got %+v", owner)
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"fmt"
+	"sync"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/db/models"
+	"gorm.io/gorm/schema"
+)
+
+func UserFieldLimits() (domain.FieldLimits, error) {
+	cache := &sync.Map{}
+	userSchema, err := schema.Parse(&models.User{}, cache, schema.NamingStrategy{})
+	if err != nil {
+		return domain.FieldLimits{}, fmt.Errorf("parse user schema: %w", err)
+	}
+	addressSchema, err := schema.Parse(&models.Address{}, cache, schema.NamingStrategy{})
+	if err != nil {
+		return domain.FieldLimits{}, fmt.Errorf("parse address schema: %w", err)
+	}
+
+	size := func(s *schema.Schema, column string) int {
+		if field := s.LookUpField(column); field != nil {
+			return field.Size
+		}
+		return 0
+	}
+
+	return domain.FieldLimits{
+		Name:        size(userSchema, "name"),
+		Email:       size(userSchema, "email"),
+		PhoneNumber: size(userSchema, "phone_number"),
+		Street:      size(addressSchema, "street"),
+		City:        size(addressSchema, "city"),
+		State:       size(addressSchema, "state"),
+		ZipCode:     size(addressSchema, "zip_code"),
+		Country:     size(addressSchema, "country"),
+	}, nil
+}
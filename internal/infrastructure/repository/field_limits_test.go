@@ -0,0 +1,22 @@
+package repository_test
+
+import (
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+)
+
+func TestUserFieldLimits(t *testing.T) {
+	t.Parallel()
+
+	limits, err := repository.UserFieldLimits()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	want := domain.FieldLimits{Name: 255, Email: 320, PhoneNumber: 32, Street: 255, City: 120, State: 120, ZipCode: 20, Country: 120}
+	if limits != want {
+		t.Fatalf("expected %+v, got %+v", want, limits)
+	}
+}
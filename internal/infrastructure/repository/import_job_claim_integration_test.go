@@ -37,6 +37,7 @@ func TestImportJobRepositoryClaimAndLifecycleIntegration(t *testing.T) {
       failed_count BIGINT NOT NULL DEFAULT 0,
       attempts INT NOT NULL DEFAULT 0,
       max_attempts INT NOT NULL DEFAULT 5,
+      options JSONB NOT NULL DEFAULT '{}'::jsonb,
       error_message TEXT,
       heartbeat_at TIMESTAMPTZ,
       lease_expires_at TIMESTAMPTZ,
@@ -46,6 +47,32 @@ func TestImportJobRepositoryClaimAndLifecycleIntegration(t *testing.T) {
       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       CHECK (status IN ('queued','running','succeeded','failed'))
     );
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '{}'::jsonb;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS warning_count BIGINT NOT NULL DEFAULT 0;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS unknown_fields JSONB NOT NULL DEFAULT '{}'::jsonb;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS expected_count BIGINT;
+    ALTER TABLE import_jobs DROP CONSTRAINT IF EXISTS import_jobs_status_check;
+    ALTER TABLE import_jobs ADD CONSTRAINT import_jobs_status_check
+      CHECK (status IN ('queued','running','waiting','succeeded','failed'));
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES import_jobs(id) ON DELETE CASCADE;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS partition_index INT;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS range_start BIGINT;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS range_end BIGINT;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS chunk_stats JSONB NOT NULL DEFAULT '{}'::jsonb;
+    CREATE TABLE IF NOT EXISTS import_job_failures (
+      job_id UUID NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+      kind TEXT NOT NULL,
+      row_index BIGINT NOT NULL,
+      reason TEXT NOT NULL,
+      rule_id TEXT NOT NULL DEFAULT '',
+      field TEXT NOT NULL DEFAULT '',
+      locator_row BIGINT NOT NULL DEFAULT 0,
+      locator_line BIGINT NOT NULL DEFAULT 0,
+      locator_offset BIGINT NOT NULL DEFAULT 0,
+      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+      PRIMARY KEY (job_id, kind, row_index, reason, rule_id, field)
+    );
     `
 	if err := db.Exec(createSQL).Error; err != nil {
 		t.Fatalf("failed to create table: %v", err)
@@ -56,7 +83,7 @@ func TestImportJobRepositoryClaimAndLifecycleIntegration(t *testing.T) {
 
 	repo := repository.NewImportJobRepository(db)
 
-	jobID, err := repo.Enqueue(context.Background(), "users_data.json")
+	jobID, err := repo.Enqueue(context.Background(), "users_data.json", domain.ImportOptions{AddressStrategy: domain.AddressStrategyMerge})
 	if err != nil {
 		t.Fatalf("enqueue failed: %v", err)
 	}
@@ -71,6 +98,9 @@ func TestImportJobRepositoryClaimAndLifecycleIntegration(t *testing.T) {
 	if claimed.ID != jobID {
 		t.Fatalf("unexpected job id: %s", claimed.ID)
 	}
+	if claimed.Options.AddressStrategy != domain.AddressStrategyMerge {
+		t.Fatalf("unexpected address strategy: %q", claimed.Options.AddressStrategy)
+	}
 
 	if err := repo.Heartbeat(context.Background(), claimed.ID, 30*time.Second); err != nil {
 		t.Fatalf("heartbeat failed: %v", err)
@@ -97,4 +127,52 @@ func TestImportJobRepositoryClaimAndLifecycleIntegration(t *testing.T) {
 	if err := repo.Complete(context.Background(), claimed.ID, summary); err != nil {
 		t.Fatalf("complete failed: %v", err)
 	}
+
+	parentID, err := repo.Enqueue(context.Background(), "users_data.ndjson", domain.ImportOptions{Format: domain.ImportFormatNDJSON, Partitions: 2})
+	if err != nil {
+		t.Fatalf("enqueue partitioned job failed: %v", err)
+	}
+	parent, err := repo.ClaimNext(context.Background(), 30*time.Second)
+	if err != nil || parent == nil || parent.ID != parentID {
+		t.Fatalf("claim partitioned job failed: %+v, %v", parent, err)
+	}
+	if err := repo.Partition(context.Background(), parent.ID, []domain.ImportPartition{
+		{Index: 0, Start: 0, End: 100},
+		{Index: 1, Start: 100, End: 180},
+	}); err != nil {
+		t.Fatalf("partition failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		child, err := repo.ClaimNext(context.Background(), 30*time.Second)
+		if err != nil || child == nil {
+			t.Fatalf("claim partition %d failed: %+v, %v", i, child, err)
+		}
+		if child.ParentID != parentID || child.RootID() != parentID || child.Partition == nil {
+			t.Fatalf("unexpected partition job: %+v", child)
+		}
+		if err := repo.Complete(context.Background(), child.ID, domain.ImportSummary{ProcessedCount: 5, ImportedCount: 4, SkippedCount: 1}); err != nil {
+			t.Fatalf("complete partition %d failed: %v", i, err)
+		}
+
+		aggregated, err := repo.GetByID(context.Background(), parentID)
+		if err != nil {
+			t.Fatalf("get parent failed: %v", err)
+		}
+		wantStatus := domain.ImportJobStatusWaiting
+		if i == 1 {
+			wantStatus = domain.ImportJobStatusSucceeded
+		}
+		if aggregated.Status != wantStatus || aggregated.Progress.ProcessedCount != int64(5*(i+1)) || aggregated.Progress.ImportedCount != int64(4*(i+1)) {
+			t.Fatalf("unexpected parent after partition %d: %+v", i, aggregated)
+		}
+	}
+
+	partitions, err := repo.ListPartitions(context.Background(), parentID)
+	if err != nil {
+		t.Fatalf("list partitions failed: %v", err)
+	}
+	if len(partitions) != 2 || partitions[1].Partition.Start != 100 || partitions[1].Partition.End != 180 {
+		t.Fatalf("unexpected partitions: %+v", partitions)
+	}
 }
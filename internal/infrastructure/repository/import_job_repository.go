@@ -2,12 +2,20 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 	"github.com/mohammadpnp/user-import/internal/infrastructure/db/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	failureKindFailure = "failure"
+	failureKindSkip    = "skip"
+	failureKindWarning = "warning"
 )
 
 type ImportJobRepository struct {
@@ -18,10 +26,12 @@ func NewImportJobRepository(db *gorm.DB) *ImportJobRepository {
 	return &ImportJobRepository{db: db}
 }
 
-func (r *ImportJobRepository) Enqueue(ctx context.Context, sourcePath string) (string, error) {
+func (r *ImportJobRepository) Enqueue(ctx context.Context, sourcePath string, options domain.ImportOptions) (string, error) {
 	job := models.ImportJob{
-		SourcePath: sourcePath,
-		Status:     "queued",
+		SourcePath:  sourcePath,
+		Status:      "queued",
+		MaxAttempts: options.MaxAttempts,
+		Options:     toImportJobOptionsModel(options),
 	}
 
 	if err := r.db.WithContext(ctx).Create(&job).Error; err != nil {
@@ -46,9 +56,18 @@ WITH candidate AS (
     WHERE
       (status = 'queued' OR (status = 'running' AND lease_expires_at < NOW()))
       AND attempts < max_attempts
+      AND (
+        parent_id IS NULL
+        OR EXISTS (SELECT 1 FROM import_jobs p WHERE p.id = import_jobs.parent_id AND p.status = 'waiting')
+      )
     ORDER BY created_at
     FOR UPDATE SKIP LOCKED
     LIMIT 1
+),
+cleared AS (
+    DELETE FROM import_job_failures f
+    USING candidate
+    WHERE f.job_id = candidate.id
 )
 UPDATE import_jobs j
 SET
@@ -72,13 +91,7 @@ RETURNING j.*;
 		return nil, nil
 	}
 
-	return &domain.ImportJob{
-		ID:          job.ID,
-		SourcePath:  job.SourcePath,
-		Status:      job.Status,
-		Attempts:    job.Attempts,
-		MaxAttempts: job.MaxAttempts,
-	}, nil
+	return toDomainImportJob(job), nil
 }
 
 func (r *ImportJobRepository) Heartbeat(ctx context.Context, jobID string, leaseDuration time.Duration) error {
@@ -105,7 +118,13 @@ WHERE id = ? AND status = 'running'
 }
 
 func (r *ImportJobRepository) UpdateProgress(ctx context.Context, jobID string, progress domain.ImportProgress) error {
-	result := r.db.WithContext(ctx).Exec(`
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parent, err := lockParentJob(tx, jobID)
+		if err != nil {
+			return fmt.Errorf("update import job progress: %w", err)
+		}
+
+		result := tx.Exec(`
 UPDATE import_jobs
 SET
   progress_processed = ?,
@@ -114,20 +133,32 @@ SET
   updated_count = ?,
   skipped_count = ?,
   failed_count = ?,
+  warning_count = ?,
+  chunk_stats = ?,
   updated_at = NOW()
 WHERE id = ?
-`, progress.ProcessedCount, progress.ProcessedCount, progress.ImportedCount, progress.UpdatedCount, progress.SkippedCount, progress.FailedCount, jobID)
-	if result.Error != nil {
-		return fmt.Errorf("update import job progress: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("update import job progress: job not found")
-	}
-	return nil
+`, progress.ProcessedCount, progress.ProcessedCount, progress.ImportedCount, progress.UpdatedCount, progress.SkippedCount, progress.FailedCount, progress.WarningCount, toChunkStatsModel(progress.ChunkStats), jobID)
+		if result.Error != nil {
+			return fmt.Errorf("update import job progress: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("update import job progress: job not found")
+		}
+		if err := recordFailures(tx, jobID, progress.Failures, progress.Skipped, progress.Warnings); err != nil {
+			return fmt.Errorf("update import job progress: %w", err)
+		}
+		return aggregatePartitions(tx, parent)
+	})
 }
 
 func (r *ImportJobRepository) Complete(ctx context.Context, jobID string, summary domain.ImportSummary) error {
-	result := r.db.WithContext(ctx).Exec(`
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parent, err := lockParentJob(tx, jobID)
+		if err != nil {
+			return fmt.Errorf("complete import job: %w", err)
+		}
+
+		result := tx.Exec(`
 UPDATE import_jobs
 SET
   status = 'succeeded',
@@ -137,20 +168,29 @@ SET
   updated_count = ?,
   skipped_count = ?,
   failed_count = ?,
+  warning_count = ?,
+  unknown_fields = ?,
+  metadata = ?,
+  expected_count = ?,
+  chunk_stats = ?,
   error_message = NULL,
   lease_expires_at = NULL,
   heartbeat_at = NOW(),
   finished_at = NOW(),
   updated_at = NOW()
 WHERE id = ?
-`, summary.ProcessedCount, summary.ProcessedCount, summary.ImportedCount, summary.UpdatedCount, summary.SkippedCount, summary.FailedCount, jobID)
-	if result.Error != nil {
-		return fmt.Errorf("complete import job: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("complete import job: job not found")
-	}
-	return nil
+`, summary.ProcessedCount, summary.ProcessedCount, summary.ImportedCount, summary.UpdatedCount, summary.SkippedCount, summary.FailedCount, summary.WarningCount, models.ImportJobFieldCounts(summary.UnknownFields), models.ImportJobMetadata(summary.Metadata), summary.ExpectedCount, toChunkStatsModel(summary.ChunkStats), jobID)
+		if result.Error != nil {
+			return fmt.Errorf("complete import job: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("complete import job: job not found")
+		}
+		if err := recordFailures(tx, jobID, summary.Failures, summary.Skipped, summary.Warnings); err != nil {
+			return fmt.Errorf("complete import job: %w", err)
+		}
+		return aggregatePartitions(tx, parent)
+	})
 }
 
 func (r *ImportJobRepository) Requeue(ctx context.Context, jobID string, reason string) error {
@@ -174,7 +214,13 @@ WHERE id = ?
 }
 
 func (r *ImportJobRepository) Fail(ctx context.Context, jobID string, reason string) error {
-	result := r.db.WithContext(ctx).Exec(`
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		parent, err := lockParentJob(tx, jobID)
+		if err != nil {
+			return fmt.Errorf("fail import job: %w", err)
+		}
+
+		result := tx.Exec(`
 UPDATE import_jobs
 SET
   status = 'failed',
@@ -185,11 +231,577 @@ SET
   updated_at = NOW()
 WHERE id = ?
 `, reason, jobID)
-	if result.Error != nil {
-		return fmt.Errorf("fail import job: %w", result.Error)
+		if result.Error != nil {
+			return fmt.Errorf("fail import job: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("fail import job: job not found")
+		}
+		if parent == nil {
+			return nil
+		}
+
+		if err := aggregatePartitions(tx, parent); err != nil {
+			return err
+		}
+		if err := tx.Exec(`
+UPDATE import_jobs
+SET
+  status = 'failed',
+  error_message = ?,
+  finished_at = NOW(),
+  updated_at = NOW()
+WHERE id = ? AND status = 'waiting'
+`, fmt.Sprintf("partition %d failed: %s", parent.partitionIndex, reason), parent.id).Error; err != nil {
+			return fmt.Errorf("fail parent import job: %w", err)
+		}
+		if err := tx.Exec(`
+UPDATE import_jobs
+SET
+  status = 'failed',
+  error_message = 'parent import job failed',
+  finished_at = NOW(),
+  updated_at = NOW()
+WHERE parent_id = ? AND status = 'queued'
+`, parent.id).Error; err != nil {
+			return fmt.Errorf("cancel queued partitions: %w", err)
+		}
+		return nil
+	})
+}
+
+func (r *ImportJobRepository) Partition(ctx context.Context, jobID string, partitions []domain.ImportPartition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var parent models.ImportJob
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, "id = ?", jobID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return domain.ErrImportJobNotFound
+			}
+			return fmt.Errorf("partition import job: %w", err)
+		}
+
+		children := make([]models.ImportJob, 0, len(partitions))
+		for _, partition := range partitions {
+			index, start, end := partition.Index, partition.Start, partition.End
+			children = append(children, models.ImportJob{
+				SourcePath:     parent.SourcePath,
+				Status:         "queued",
+				MaxAttempts:    parent.MaxAttempts,
+				Options:        parent.Options,
+				ParentID:       &parent.ID,
+				PartitionIndex: &index,
+				RangeStart:     &start,
+				RangeEnd:       &end,
+			})
+		}
+		if err := tx.Create(&children).Error; err != nil {
+			return fmt.Errorf("create import job partitions: %w", err)
+		}
+
+		result := tx.Exec(`
+UPDATE import_jobs
+SET
+  status = 'waiting',
+  lease_expires_at = NULL,
+  heartbeat_at = NOW(),
+  error_message = NULL,
+  updated_at = NOW()
+WHERE id = ? AND status = 'running'
+`, jobID)
+		if result.Error != nil {
+			return fmt.Errorf("partition import job: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("partition import job: job not running")
+		}
+		return nil
+	})
+}
+
+func (r *ImportJobRepository) RecordConflicts(ctx context.Context, jobID string, conflicts []domain.ImportConflict) error {
+	if len(conflicts) == 0 {
+		return nil
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("fail import job: job not found")
+
+	rows := make([]models.ImportConflict, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		rows = append(rows, models.ImportConflict{
+			JobID:       jobID,
+			RowIndex:    conflict.RowIndex,
+			ExternalID:  conflict.ExternalID,
+			IDUserID:    conflict.IDUserID,
+			EmailUserID: conflict.EmailUserID,
+			Resolution:  string(conflict.Resolution),
+
+			LocatorRow:    conflict.Locator.Row,
+			LocatorLine:   conflict.Locator.Line,
+			LocatorOffset: conflict.Locator.Offset,
+		})
+	}
+
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&rows).Error; err != nil {
+		return fmt.Errorf("record import conflicts: %w", err)
+	}
+	return nil
+}
+
+func (r *ImportJobRepository) GetByID(ctx context.Context, jobID string) (*domain.ImportJob, error) {
+	var job models.ImportJob
+
+	if err := r.db.WithContext(ctx).First(&job, "id = ?", jobID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrImportJobNotFound
+		}
+		return nil, fmt.Errorf("get import job: %w", err)
+	}
+
+	return toDomainImportJob(job), nil
+}
+
+// ListConflicts pages through the conflicts of a job and of its partitions, in
+// source order.
+func (r *ImportJobRepository) ListConflicts(ctx context.Context, jobID string, limit, offset int) ([]domain.ImportConflict, error) {
+	var rows []models.ImportConflict
+
+	if err := r.db.WithContext(ctx).
+		Where("job_id IN (SELECT id FROM import_jobs WHERE id = ? OR parent_id = ?)", jobID, jobID).
+		Order("locator_offset, locator_row, row_index, id").
+		Limit(limit).
+		Offset(offset).
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list import conflicts: %w", err)
+	}
+
+	conflicts := make([]domain.ImportConflict, 0, len(rows))
+	for _, row := range rows {
+		conflicts = append(conflicts, domain.ImportConflict{
+			RowIndex:    row.RowIndex,
+			Locator:     domain.RecordLocator{Row: row.LocatorRow, Line: row.LocatorLine, Offset: row.LocatorOffset},
+			ExternalID:  row.ExternalID,
+			IDUserID:    row.IDUserID,
+			EmailUserID: row.EmailUserID,
+			Resolution:  domain.IdentityConflictPolicy(row.Resolution),
+		})
+	}
+	return conflicts, nil
+}
+
+// ListFailures returns the failed rows of a job and of its partitions, in
+// source order.
+func (r *ImportJobRepository) ListFailures(ctx context.Context, jobID string) ([]domain.ImportFailure, error) {
+	rows, err := r.listFailureRows(ctx, jobID, failureKindFailure)
+	if err != nil {
+		return nil, fmt.Errorf("list import job failures: %w", err)
+	}
+
+	failures := make([]domain.ImportFailure, 0, len(rows))
+	for _, row := range rows {
+		failures = append(failures, domain.ImportFailure{
+			RowIndex: row.RowIndex,
+			Locator:  toDomainLocator(row),
+			Reason:   row.Reason,
+			RuleID:   row.RuleID,
+			Field:    row.Field,
+		})
+	}
+	return failures, nil
+}
+
+// ListSkipped returns the skipped rows of a job and of its partitions, in
+// source order.
+func (r *ImportJobRepository) ListSkipped(ctx context.Context, jobID string) ([]domain.ImportSkip, error) {
+	rows, err := r.listFailureRows(ctx, jobID, failureKindSkip)
+	if err != nil {
+		return nil, fmt.Errorf("list import job skipped rows: %w", err)
+	}
+
+	skipped := make([]domain.ImportSkip, 0, len(rows))
+	for _, row := range rows {
+		skipped = append(skipped, domain.ImportSkip{
+			RowIndex: row.RowIndex,
+			Locator:  toDomainLocator(row),
+			Reason:   row.Reason,
+		})
+	}
+	return skipped, nil
+}
+
+// ListWarnings returns the rows of a job and of its partitions that were
+// imported with warnings, in source order.
+func (r *ImportJobRepository) ListWarnings(ctx context.Context, jobID string) ([]domain.ImportWarning, error) {
+	rows, err := r.listFailureRows(ctx, jobID, failureKindWarning)
+	if err != nil {
+		return nil, fmt.Errorf("list import job warnings: %w", err)
+	}
+
+	warnings := make([]domain.ImportWarning, 0, len(rows))
+	for _, row := range rows {
+		warnings = append(warnings, domain.ImportWarning{
+			RowIndex: row.RowIndex,
+			Locator:  toDomainLocator(row),
+			Reason:   row.Reason,
+			Field:    row.Field,
+		})
+	}
+	return warnings, nil
+}
+
+func (r *ImportJobRepository) listFailureRows(ctx context.Context, jobID, kind string) ([]models.ImportJobFailure, error) {
+	var rows []models.ImportJobFailure
+	err := r.db.WithContext(ctx).
+		Where("kind = ? AND job_id IN (SELECT id FROM import_jobs WHERE id = ? OR parent_id = ?)", kind, jobID, jobID).
+		Order("locator_offset, locator_row, row_index, reason, rule_id, field").
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *ImportJobRepository) ListPartitions(ctx context.Context, jobID string) ([]domain.ImportJob, error) {
+	var rows []models.ImportJob
+
+	if err := r.db.WithContext(ctx).
+		Where("parent_id = ?", jobID).
+		Order("partition_index").
+		Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list import job partitions: %w", err)
+	}
+
+	partitions := make([]domain.ImportJob, 0, len(rows))
+	for _, row := range rows {
+		partitions = append(partitions, *toDomainImportJob(row))
+	}
+	return partitions, nil
+}
+
+// recordFailures stores the rows reported so far. Entries already stored by an
+// earlier checkpoint of the same attempt are left as they are.
+func recordFailures(tx *gorm.DB, jobID string, failures []domain.ImportFailure, skipped []domain.ImportSkip, warnings []domain.ImportWarning) error {
+	rows := make([]models.ImportJobFailure, 0, len(failures)+len(skipped)+len(warnings))
+	for _, failure := range failures {
+		rows = append(rows, failureRow(jobID, failureKindFailure, failure.RowIndex, failure.Locator, failure.Reason, failure.RuleID, failure.Field))
+	}
+	for _, skip := range skipped {
+		rows = append(rows, failureRow(jobID, failureKindSkip, skip.RowIndex, skip.Locator, skip.Reason, "", ""))
+	}
+	for _, warning := range warnings {
+		rows = append(rows, failureRow(jobID, failureKindWarning, warning.RowIndex, warning.Locator, warning.Reason, "", warning.Field))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		return fmt.Errorf("record import job failures: %w", err)
 	}
 	return nil
 }
+
+func failureRow(jobID, kind string, rowIndex int64, locator domain.RecordLocator, reason, ruleID, field string) models.ImportJobFailure {
+	return models.ImportJobFailure{
+		JobID:         jobID,
+		Kind:          kind,
+		RowIndex:      rowIndex,
+		Reason:        reason,
+		RuleID:        ruleID,
+		Field:         field,
+		LocatorRow:    locator.Row,
+		LocatorLine:   locator.Line,
+		LocatorOffset: locator.Offset,
+	}
+}
+
+func toDomainLocator(row models.ImportJobFailure) domain.RecordLocator {
+	return domain.RecordLocator{Row: row.LocatorRow, Line: row.LocatorLine, Offset: row.LocatorOffset}
+}
+
+type parentJob struct {
+	id             string
+	partitionIndex int
+}
+
+// lockParentJob locks the parent of a partition so that concurrent partitions
+// aggregate their counters one at a time. It returns nil for other jobs.
+func lockParentJob(tx *gorm.DB, jobID string) (*parentJob, error) {
+	var row struct {
+		ParentID       *string
+		PartitionIndex *int
+	}
+	if err := tx.Raw(`SELECT parent_id, partition_index FROM import_jobs WHERE id = ?`, jobID).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	if row.ParentID == nil {
+		return nil, nil
+	}
+
+	if err := tx.Exec(`SELECT 1 FROM import_jobs WHERE id = ? FOR UPDATE`, *row.ParentID).Error; err != nil {
+		return nil, err
+	}
+	parent := &parentJob{id: *row.ParentID}
+	if row.PartitionIndex != nil {
+		parent.partitionIndex = *row.PartitionIndex
+	}
+	return parent, nil
+}
+
+func aggregatePartitions(tx *gorm.DB, parent *parentJob) error {
+	if parent == nil {
+		return nil
+	}
+
+	if err := tx.Exec(`
+UPDATE import_jobs p
+SET
+  progress_processed = c.processed,
+  progress_total = c.processed,
+  imported_count = c.imported,
+  updated_count = c.updated,
+  skipped_count = c.skipped,
+  failed_count = c.failed,
+  warning_count = c.warnings,
+  unknown_fields = c.unknown_fields,
+  chunk_stats = c.chunk_stats,
+  status = CASE WHEN c.pending = 0 THEN 'succeeded' ELSE p.status END,
+  finished_at = CASE WHEN c.pending = 0 THEN NOW() ELSE p.finished_at END,
+  updated_at = NOW()
+FROM (
+    SELECT
+      COALESCE(SUM(k.progress_processed), 0) AS processed,
+      COALESCE(SUM(k.imported_count), 0) AS imported,
+      COALESCE(SUM(k.updated_count), 0) AS updated,
+      COALESCE(SUM(k.skipped_count), 0) AS skipped,
+      COALESCE(SUM(k.failed_count), 0) AS failed,
+      COALESCE(SUM(k.warning_count), 0) AS warnings,
+      COUNT(*) FILTER (WHERE k.status <> 'succeeded') AS pending,
+      jsonb_build_object(
+        'chunks', COALESCE(SUM((k.chunk_stats->>'chunks')::bigint), 0),
+        'rows', COALESCE(SUM((k.chunk_stats->>'rows')::bigint), 0),
+        'bytes', COALESCE(SUM((k.chunk_stats->>'bytes')::bigint), 0),
+        'max_chunk_rows', COALESCE(MAX((k.chunk_stats->>'max_chunk_rows')::bigint), 0),
+        'max_chunk_bytes', COALESCE(MAX((k.chunk_stats->>'max_chunk_bytes')::bigint), 0),
+        'write_ms', COALESCE(SUM((k.chunk_stats->>'write_ms')::bigint), 0),
+        'max_heap_bytes', COALESCE(MAX((k.chunk_stats->>'max_heap_bytes')::bigint), 0)
+      ) AS chunk_stats,
+      COALESCE((
+          SELECT jsonb_object_agg(f.key, f.total)
+          FROM (
+              SELECT e.key, SUM(e.value::bigint) AS total
+              FROM import_jobs u
+              CROSS JOIN LATERAL jsonb_each_text(u.unknown_fields) e
+              WHERE u.parent_id = ?
+              GROUP BY e.key
+          ) f
+      ), '{}'::jsonb) AS unknown_fields
+    FROM import_jobs k
+    WHERE k.parent_id = ?
+) c
+WHERE p.id = ? AND p.status = 'waiting'
+`, parent.id, parent.id, parent.id).Error; err != nil {
+		return fmt.Errorf("aggregate import job partitions: %w", err)
+	}
+	return nil
+}
+
+func toDomainImportJob(job models.ImportJob) *domain.ImportJob {
+	errorMessage := ""
+	if job.ErrorMessage != nil {
+		errorMessage = *job.ErrorMessage
+	}
+	parentID := ""
+	if job.ParentID != nil {
+		parentID = *job.ParentID
+	}
+	var partition *domain.ImportPartition
+	if job.PartitionIndex != nil && job.RangeStart != nil && job.RangeEnd != nil {
+		partition = &domain.ImportPartition{
+			Index: *job.PartitionIndex,
+			Start: *job.RangeStart,
+			End:   *job.RangeEnd,
+		}
+	}
+
+	return &domain.ImportJob{
+		ID:          job.ID,
+		ParentID:    parentID,
+		Partition:   partition,
+		SourcePath:  job.SourcePath,
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		Options:     toDomainImportOptions(job.Options),
+		Progress: domain.ImportProgress{
+			ProcessedCount: job.ProgressProcessed,
+			ImportedCount:  job.ImportedCount,
+			UpdatedCount:   job.UpdatedCount,
+			SkippedCount:   job.SkippedCount,
+			FailedCount:    job.FailedCount,
+			WarningCount:   job.WarningCount,
+			ChunkStats:     toDomainChunkStats(job.ChunkStats),
+		},
+		ErrorMessage:  errorMessage,
+		UnknownFields: job.UnknownFields,
+		Metadata:      job.Metadata,
+		ExpectedCount: job.ExpectedCount,
+		CreatedAt:     job.CreatedAt,
+		StartedAt:     job.StartedAt,
+		FinishedAt:    job.FinishedAt,
+	}
+}
+
+func toChunkStatsModel(stats domain.ImportChunkStats) models.ImportJobChunkStats {
+	return models.ImportJobChunkStats{
+		Chunks:        stats.Chunks,
+		Rows:          stats.Rows,
+		Bytes:         stats.Bytes,
+		MaxChunkRows:  stats.MaxChunkRows,
+		MaxChunkBytes: stats.MaxChunkBytes,
+		WriteMillis:   stats.WriteDuration.Milliseconds(),
+		MaxHeapBytes:  stats.MaxHeapBytes,
+	}
+}
+
+func toDomainChunkStats(stats models.ImportJobChunkStats) domain.ImportChunkStats {
+	return domain.ImportChunkStats{
+		Chunks:        stats.Chunks,
+		Rows:          stats.Rows,
+		Bytes:         stats.Bytes,
+		MaxChunkRows:  stats.MaxChunkRows,
+		MaxChunkBytes: stats.MaxChunkBytes,
+		WriteDuration: time.Duration(stats.WriteMillis) * time.Millisecond,
+		MaxHeapBytes:  stats.MaxHeapBytes,
+	}
+}
+
+func toImportJobOptionsModel(options domain.ImportOptions) models.ImportJobOptions {
+	return models.ImportJobOptions{
+		AddressStrategy: string(options.AddressStrategy),
+		UpdatePolicies: models.ImportJobUpdatePolicies{
+			Name:        string(options.UpdatePolicies.Name),
+			Email:       string(options.UpdatePolicies.Email),
+			PhoneNumber: string(options.UpdatePolicies.PhoneNumber),
+		},
+		Source:         options.Source,
+		ConflictPolicy: string(options.ConflictPolicy),
+		Profile:        options.Profile,
+		AddressValidation: models.ImportJobAddressValidation{
+			Country:     string(options.AddressValidation.Country),
+			PostalCode:  string(options.AddressValidation.PostalCode),
+			Subdivision: string(options.AddressValidation.Subdivision),
+			Normalize:   options.AddressValidation.Normalize,
+		},
+		OversizePolicy:    string(options.OversizePolicy),
+		PhoneRegion:       options.PhoneRegion,
+		AttributeStrategy: string(options.AttributeStrategy),
+		AttributeFields:   options.AttributeFields,
+		SchemaMode:        string(options.SchemaMode),
+		Format:            string(options.Format),
+		Encoding:          string(options.Encoding),
+		Sheet:             options.Sheet,
+		XML: models.ImportJobXMLMapping{
+			RecordPath:    options.XML.RecordPath,
+			Fields:        options.XML.Fields,
+			AddressPath:   options.XML.AddressPath,
+			AddressFields: options.XML.AddressFields,
+			Namespaces:    options.XML.Namespaces,
+		},
+		FieldMapping:     options.FieldMapping,
+		Transforms:       toTransformModels(options.Transforms),
+		Rules:            toValidationRuleModels(options.Rules),
+		ChunkSize:        options.ChunkSize,
+		ChunkConcurrency: options.ChunkConcurrency,
+		Partitions:       options.Partitions,
+		MaxAttempts:      options.MaxAttempts,
+		RecordsPointer:   options.RecordsPointer,
+		Metadata:         options.Metadata,
+	}
+}
+
+func toDomainImportOptions(options models.ImportJobOptions) domain.ImportOptions {
+	return domain.ImportOptions{
+		AddressStrategy: domain.AddressStrategy(options.AddressStrategy),
+		UpdatePolicies: domain.FieldUpdatePolicies{
+			Name:        domain.FieldUpdatePolicy(options.UpdatePolicies.Name),
+			Email:       domain.FieldUpdatePolicy(options.UpdatePolicies.Email),
+			PhoneNumber: domain.FieldUpdatePolicy(options.UpdatePolicies.PhoneNumber),
+		},
+		Source:         options.Source,
+		ConflictPolicy: domain.IdentityConflictPolicy(options.ConflictPolicy),
+		Profile:        options.Profile,
+		AddressValidation: domain.AddressValidationRules{
+			Country:     domain.ValidationSeverity(options.AddressValidation.Country),
+			PostalCode:  domain.ValidationSeverity(options.AddressValidation.PostalCode),
+			Subdivision: domain.ValidationSeverity(options.AddressValidation.Subdivision),
+			Normalize:   options.AddressValidation.Normalize,
+		},
+		OversizePolicy:    domain.OversizePolicy(options.OversizePolicy),
+		PhoneRegion:       options.PhoneRegion,
+		AttributeStrategy: domain.AttributeStrategy(options.AttributeStrategy),
+		AttributeFields:   options.AttributeFields,
+		SchemaMode:        domain.SchemaMode(options.SchemaMode),
+		Format:            domain.ImportFormat(options.Format),
+		Encoding:          domain.ImportEncoding(options.Encoding),
+		Sheet:             options.Sheet,
+		XML: domain.XMLMapping{
+			RecordPath:    options.XML.RecordPath,
+			Fields:        options.XML.Fields,
+			AddressPath:   options.XML.AddressPath,
+			AddressFields: options.XML.AddressFields,
+			Namespaces:    options.XML.Namespaces,
+		},
+		FieldMapping:     options.FieldMapping,
+		Transforms:       toDomainTransforms(options.Transforms),
+		Rules:            toDomainValidationRules(options.Rules),
+		ChunkSize:        options.ChunkSize,
+		ChunkConcurrency: options.ChunkConcurrency,
+		Partitions:       options.Partitions,
+		MaxAttempts:      options.MaxAttempts,
+		RecordsPointer:   options.RecordsPointer,
+		Metadata:         options.Metadata,
+	}
+}
+
+func toTransformModels(transforms []domain.FieldTransform) []models.ImportJobTransform {
+	if len(transforms) == 0 {
+		return nil
+	}
+
+	out := make([]models.ImportJobTransform, 0, len(transforms))
+	for _, transform := range transforms {
+		out = append(out, models.ImportJobTransform{
+			Target:    transform.Target,
+			Source:    transform.Source,
+			Sources:   transform.Sources,
+			Separator: transform.Separator,
+			Split:     transform.Split,
+			Index:     transform.Index,
+			Trim:      transform.Trim,
+			Case:      string(transform.Case),
+			Lookup:    transform.Lookup,
+			Default:   transform.Default,
+		})
+	}
+	return out
+}
+
+func toDomainTransforms(transforms []models.ImportJobTransform) []domain.FieldTransform {
+	if len(transforms) == 0 {
+		return nil
+	}
+
+	out := make([]domain.FieldTransform, 0, len(transforms))
+	for _, transform := range transforms {
+		out = append(out, domain.FieldTransform{
+			Target:    transform.Target,
+			Source:    transform.Source,
+			Sources:   transform.Sources,
+			Separator: transform.Separator,
+			Split:     transform.Split,
+			Index:     transform.Index,
+			Trim:      transform.Trim,
+			Case:      domain.FieldCase(transform.Case),
+			Lookup:    transform.Lookup,
+			Default:   transform.Default,
+		})
+	}
+	return out
+}
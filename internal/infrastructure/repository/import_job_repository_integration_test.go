@@ -3,15 +3,18 @@ package repository_test
 import (
 	"context"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-func TestImportJobRepositoryEnqueueIntegration(t *testing.T) {
+func setupImportJobIntegration(t *testing.T) *gorm.DB {
 	dsn := os.Getenv("TEST_DATABASE_URL")
 	if dsn == "" {
 		t.Skip("TEST_DATABASE_URL is not set")
@@ -36,6 +39,7 @@ func TestImportJobRepositoryEnqueueIntegration(t *testing.T) {
       failed_count BIGINT NOT NULL DEFAULT 0,
       attempts INT NOT NULL DEFAULT 0,
       max_attempts INT NOT NULL DEFAULT 5,
+      options JSONB NOT NULL DEFAULT '{}'::jsonb,
       error_message TEXT,
       heartbeat_at TIMESTAMPTZ,
       lease_expires_at TIMESTAMPTZ,
@@ -45,6 +49,46 @@ func TestImportJobRepositoryEnqueueIntegration(t *testing.T) {
       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       CHECK (status IN ('queued','running','succeeded','failed'))
     );
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '{}'::jsonb;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS warning_count BIGINT NOT NULL DEFAULT 0;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS unknown_fields JSONB NOT NULL DEFAULT '{}'::jsonb;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS expected_count BIGINT;
+    ALTER TABLE import_jobs DROP CONSTRAINT IF EXISTS import_jobs_status_check;
+    ALTER TABLE import_jobs ADD CONSTRAINT import_jobs_status_check
+      CHECK (status IN ('queued','running','waiting','succeeded','failed'));
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES import_jobs(id) ON DELETE CASCADE;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS partition_index INT;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS range_start BIGINT;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS range_end BIGINT;
+    ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS chunk_stats JSONB NOT NULL DEFAULT '{}'::jsonb;
+    CREATE TABLE IF NOT EXISTS import_conflicts (
+      id BIGSERIAL PRIMARY KEY,
+      job_id UUID NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+      row_index BIGINT NOT NULL,
+      external_id TEXT NOT NULL,
+      id_user_id UUID NOT NULL,
+      email_user_id UUID NOT NULL,
+      resolution TEXT NOT NULL,
+      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+      UNIQUE (job_id, row_index)
+    );
+    ALTER TABLE import_conflicts ADD COLUMN IF NOT EXISTS locator_row BIGINT NOT NULL DEFAULT 0;
+    ALTER TABLE import_conflicts ADD COLUMN IF NOT EXISTS locator_line BIGINT NOT NULL DEFAULT 0;
+    ALTER TABLE import_conflicts ADD COLUMN IF NOT EXISTS locator_offset BIGINT NOT NULL DEFAULT 0;
+    CREATE TABLE IF NOT EXISTS import_job_failures (
+      job_id UUID NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+      kind TEXT NOT NULL,
+      row_index BIGINT NOT NULL,
+      reason TEXT NOT NULL,
+      rule_id TEXT NOT NULL DEFAULT '',
+      field TEXT NOT NULL DEFAULT '',
+      locator_row BIGINT NOT NULL DEFAULT 0,
+      locator_line BIGINT NOT NULL DEFAULT 0,
+      locator_offset BIGINT NOT NULL DEFAULT 0,
+      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+      PRIMARY KEY (job_id, kind, row_index, reason, rule_id, field)
+    );
     `
 	if err := db.Exec(createSQL).Error; err != nil {
 		t.Fatalf("failed to create table: %v", err)
@@ -53,13 +97,236 @@ func TestImportJobRepositoryEnqueueIntegration(t *testing.T) {
 		t.Fatalf("failed to cleanup import_jobs: %v", err)
 	}
 
+	return db
+}
+
+func TestImportJobRepositoryEnqueueIntegration(t *testing.T) {
+	db := setupImportJobIntegration(t)
+
 	repo := repository.NewImportJobRepository(db)
 
-	jobID, err := repo.Enqueue(context.Background(), "users_data.json")
+	jobID, err := repo.Enqueue(context.Background(), "users_data.json", domain.ImportOptions{})
 	if err != nil {
 		t.Fatalf("enqueue failed: %v", err)
 	}
 	if strings.TrimSpace(jobID) == "" {
 		t.Fatal("expected non-empty job id")
 	}
+
+	job, err := repo.GetByID(context.Background(), jobID)
+	if err != nil {
+		t.Fatalf("get by id failed: %v", err)
+	}
+	if job.Status != "queued" || job.SourcePath != "users_data.json" {
+		t.Fatalf("unexpected job: %+v", job)
+	}
+
+	conflict := domain.ImportConflict{
+		RowIndex:    7,
+		ExternalID:  "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+		IDUserID:    "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+		EmailUserID: "d5987b5f-506d-4d84-934f-d5b5535a64e8",
+		Resolution:  domain.IdentityConflictReject,
+	}
+	for i := 0; i < 2; i++ {
+		if err := repo.RecordConflicts(context.Background(), jobID, []domain.ImportConflict{conflict}); err != nil {
+			t.Fatalf("record conflicts failed: %v", err)
+		}
+	}
+
+	conflicts, err := repo.ListConflicts(context.Background(), jobID, 10, 0)
+	if err != nil {
+		t.Fatalf("list conflicts failed: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != conflict {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+
+	snapshotID, err := repo.Enqueue(context.Background(), "users_data.json", domain.ImportOptions{
+		Profile:      "crm",
+		Format:       domain.ImportFormatJSON,
+		FieldMapping: map[string]string{"fullName": "name"},
+		Rules:        []domain.ValidationRule{{ID: "phone-required", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldPhoneNumber}},
+		ChunkSize:    500,
+		MaxAttempts:  2,
+	})
+	if err != nil {
+		t.Fatalf("enqueue snapshot failed: %v", err)
+	}
+	snapshot, err := repo.GetByID(context.Background(), snapshotID)
+	if err != nil {
+		t.Fatalf("get snapshot job failed: %v", err)
+	}
+	if snapshot.MaxAttempts != 2 || snapshot.Options.ChunkSize != 500 || snapshot.Options.FieldMapping["fullName"] != "name" {
+		t.Fatalf("unexpected snapshot job: %+v", snapshot)
+	}
+	if len(snapshot.Options.Rules) != 1 || snapshot.Options.Rules[0].ID != "phone-required" {
+		t.Fatalf("unexpected snapshot rules: %+v", snapshot.Options.Rules)
+	}
+
+	if _, err := repo.GetByID(context.Background(), "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2aff"); err != domain.ErrImportJobNotFound {
+		t.Fatalf("expected ErrImportJobNotFound, got %v", err)
+	}
+}
+
+func TestImportJobRepositoryListsPartitionConflictsIntegration(t *testing.T) {
+	db := setupImportJobIntegration(t)
+	repo := repository.NewImportJobRepository(db)
+	ctx := context.Background()
+
+	parentID, err := repo.Enqueue(ctx, "users.ndjson", domain.ImportOptions{Format: domain.ImportFormatNDJSON, Partitions: 2})
+	if err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+	if _, err := repo.ClaimNext(ctx, 30*time.Second); err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+	if err := repo.Partition(ctx, parentID, []domain.ImportPartition{{Index: 0, Start: 0, End: 100}, {Index: 1, Start: 100, End: 180}}); err != nil {
+		t.Fatalf("partition failed: %v", err)
+	}
+	partitions, err := repo.ListPartitions(ctx, parentID)
+	if err != nil || len(partitions) != 2 {
+		t.Fatalf("list partitions failed: %v %+v", err, partitions)
+	}
+
+	conflict := func(rowIndex, row, offset int64) domain.ImportConflict {
+		return domain.ImportConflict{
+			RowIndex:    rowIndex,
+			Locator:     domain.RecordLocator{Row: row, Line: row, Offset: offset},
+			ExternalID:  "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+			IDUserID:    "ab5e6ab5-ae1a-4a52-94f3-9c266d266c79",
+			EmailUserID: "d5987b5f-506d-4d84-934f-d5b5535a64e8",
+			Resolution:  domain.IdentityConflictReject,
+		}
+	}
+	// Both partitions number their rows from zero; the locators place them in
+	// the whole file.
+	second := []domain.ImportConflict{conflict(0, 1, 100)}
+	first := []domain.ImportConflict{conflict(0, 1, 0), conflict(1, 2, 50)}
+	if err := repo.RecordConflicts(ctx, partitions[1].ID, second); err != nil {
+		t.Fatalf("record conflicts failed: %v", err)
+	}
+	if err := repo.RecordConflicts(ctx, partitions[0].ID, first); err != nil {
+		t.Fatalf("record conflicts failed: %v", err)
+	}
+
+	conflicts, err := repo.ListConflicts(ctx, parentID, 10, 0)
+	if err != nil {
+		t.Fatalf("list conflicts failed: %v", err)
+	}
+	if want := append(first, second...); !reflect.DeepEqual(conflicts, want) {
+		t.Fatalf("expected the partitions' conflicts in source order %+v, got %+v", want, conflicts)
+	}
+
+	page, err := repo.ListConflicts(ctx, parentID, 1, 2)
+	if err != nil {
+		t.Fatalf("list conflicts page failed: %v", err)
+	}
+	if len(page) != 1 || page[0] != second[0] {
+		t.Fatalf("unexpected conflicts page: %+v", page)
+	}
+
+	own, err := repo.ListConflicts(ctx, partitions[0].ID, 10, 0)
+	if err != nil {
+		t.Fatalf("list partition conflicts failed: %v", err)
+	}
+	if !reflect.DeepEqual(own, first) {
+		t.Fatalf("expected a partition to list only its own conflicts, got %+v", own)
+	}
+}
+
+func TestImportJobRepositoryStoresFailuresIntegration(t *testing.T) {
+	db := setupImportJobIntegration(t)
+	repo := repository.NewImportJobRepository(db)
+	ctx := context.Background()
+
+	jobID, err := repo.Enqueue(ctx, "users_data.json", domain.ImportOptions{})
+	if err != nil {
+		t.Fatalf("enqueue failed: %v", err)
+	}
+	if _, err := repo.ClaimNext(ctx, 30*time.Second); err != nil {
+		t.Fatalf("claim failed: %v", err)
+	}
+
+	failure := domain.ImportFailure{
+		RowIndex: 1,
+		Locator:  domain.RecordLocator{Row: 2, Offset: 57},
+		Reason:   domain.FailureReasonRuleViolation,
+		RuleID:   "phone-required",
+		Field:    "phone_number",
+	}
+	unknownFields := []domain.ImportFailure{
+		{RowIndex: 2, Locator: domain.RecordLocator{Row: 3, Offset: 96}, Reason: domain.FailureReasonUnknownField, Field: "phoneNumber"},
+		{RowIndex: 2, Locator: domain.RecordLocator{Row: 3, Offset: 96}, Reason: domain.FailureReasonUnknownField, Field: "addresses[0].zipCode"},
+	}
+	skip := domain.ImportSkip{
+		RowIndex: 3,
+		Locator:  domain.RecordLocator{Row: 4, Offset: 130},
+		Reason:   domain.SkipReasonStaleSourceTimestamp,
+	}
+	warning := domain.ImportWarning{
+		RowIndex: 0,
+		Locator:  domain.RecordLocator{Row: 1, Offset: 1},
+		Reason:   domain.WarningReasonValueTruncated,
+		Field:    "name",
+	}
+	progress := domain.ImportProgress{
+		ProcessedCount: 2,
+		FailedCount:    1,
+		WarningCount:   1,
+		Failures:       []domain.ImportFailure{failure},
+		Warnings:       []domain.ImportWarning{warning},
+	}
+	if err := repo.UpdateProgress(ctx, jobID, progress); err != nil {
+		t.Fatalf("update progress failed: %v", err)
+	}
+	summary := domain.ImportSummary{
+		ProcessedCount: 4,
+		FailedCount:    1,
+		SkippedCount:   2,
+		WarningCount:   1,
+		Failures:       append([]domain.ImportFailure{failure}, unknownFields...),
+		Skipped:        []domain.ImportSkip{skip},
+		Warnings:       []domain.ImportWarning{warning},
+	}
+	if err := repo.Complete(ctx, jobID, summary); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+
+	failures, err := repo.ListFailures(ctx, jobID)
+	if err != nil {
+		t.Fatalf("list failures failed: %v", err)
+	}
+	wantFailures := []domain.ImportFailure{failure, unknownFields[1], unknownFields[0]}
+	if !reflect.DeepEqual(failures, wantFailures) {
+		t.Fatalf("expected one entry per failure and unknown field %+v, got %+v", wantFailures, failures)
+	}
+	skipped, err := repo.ListSkipped(ctx, jobID)
+	if err != nil {
+		t.Fatalf("list skipped failed: %v", err)
+	}
+	if len(skipped) != 1 || skipped[0] != skip {
+		t.Fatalf("unexpected skipped rows: %+v", skipped)
+	}
+	warnings, err := repo.ListWarnings(ctx, jobID)
+	if err != nil {
+		t.Fatalf("list warnings failed: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0] != warning {
+		t.Fatalf("unexpected warnings: %+v", warnings)
+	}
+
+	if err := repo.Requeue(ctx, jobID, "lease lost"); err != nil {
+		t.Fatalf("requeue failed: %v", err)
+	}
+	if _, err := repo.ClaimNext(ctx, 30*time.Second); err != nil {
+		t.Fatalf("reclaim failed: %v", err)
+	}
+	failures, err = repo.ListFailures(ctx, jobID)
+	if err != nil {
+		t.Fatalf("list failures failed: %v", err)
+	}
+	if len(failures) != 0 {
+		t.Fatalf("expected a new attempt to clear stored failures, got %+v", failures)
+	}
 }
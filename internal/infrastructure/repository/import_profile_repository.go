@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/db/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ImportProfileRepository struct {
+	db *gorm.DB
+}
+
+func NewImportProfileRepository(db *gorm.DB) *ImportProfileRepository {
+	return &ImportProfileRepository{db: db}
+}
+
+func (r *ImportProfileRepository) Create(ctx context.Context, profile domain.ImportProfile) (*domain.ImportProfile, error) {
+	model := toImportProfileModel(profile)
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&model)
+	if result.Error != nil {
+		return nil, fmt.Errorf("create import profile: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrImportProfileExists
+	}
+
+	return toDomainImportProfile(model), nil
+}
+
+func (r *ImportProfileRepository) GetByName(ctx context.Context, name string) (*domain.ImportProfile, error) {
+	var profile models.ImportProfile
+
+	if err := r.db.WithContext(ctx).First(&profile, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrImportProfileNotFound
+		}
+		return nil, fmt.Errorf("get import profile: %w", err)
+	}
+
+	return toDomainImportProfile(profile), nil
+}
+
+func (r *ImportProfileRepository) List(ctx context.Context, limit, offset int) ([]domain.ImportProfile, error) {
+	var rows []models.ImportProfile
+
+	if err := r.db.WithContext(ctx).Order("name").Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list import profiles: %w", err)
+	}
+
+	profiles := make([]domain.ImportProfile, 0, len(rows))
+	for _, row := range rows {
+		profiles = append(profiles, *toDomainImportProfile(row))
+	}
+	return profiles, nil
+}
+
+func (r *ImportProfileRepository) Update(ctx context.Context, profile domain.ImportProfile) (*domain.ImportProfile, error) {
+	model := toImportProfileModel(profile)
+
+	result := r.db.WithContext(ctx).
+		Model(&model).
+		Clauses(clause.Returning{}).
+		Where("name = ?", profile.Name).
+		Updates(map[string]any{
+			"rules":      model.Rules,
+			"options":    model.Options,
+			"updated_at": gorm.Expr("NOW()"),
+		})
+	if result.Error != nil {
+		return nil, fmt.Errorf("update import profile: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrImportProfileNotFound
+	}
+
+	return toDomainImportProfile(model), nil
+}
+
+func (r *ImportProfileRepository) Delete(ctx context.Context, name string) error {
+	result := r.db.WithContext(ctx).Where("name = ?", name).Delete(&models.ImportProfile{})
+	if result.Error != nil {
+		return fmt.Errorf("delete import profile: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrImportProfileNotFound
+	}
+	return nil
+}
+
+func toImportProfileModel(profile domain.ImportProfile) models.ImportProfile {
+	options := profile.Options
+	options.Rules = nil
+
+	rules := toValidationRuleModels(profile.Rules)
+	if rules == nil {
+		rules = models.ImportProfileRules{}
+	}
+
+	return models.ImportProfile{
+		Name:    profile.Name,
+		Rules:   rules,
+		Options: toImportJobOptionsModel(options),
+	}
+}
+
+func toDomainImportProfile(profile models.ImportProfile) *domain.ImportProfile {
+	rules := toDomainValidationRules(profile.Rules)
+	if rules == nil {
+		rules = []domain.ValidationRule{}
+	}
+
+	return &domain.ImportProfile{
+		ID:        profile.ID,
+		Name:      profile.Name,
+		Options:   toDomainImportOptions(profile.Options),
+		Rules:     rules,
+		CreatedAt: profile.CreatedAt,
+		UpdatedAt: profile.UpdatedAt,
+	}
+}
+
+func toValidationRuleModels(rules []domain.ValidationRule) models.ImportProfileRules {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	out := make(models.ImportProfileRules, 0, len(rules))
+	for _, rule := range rules {
+		var when *models.ImportProfileRuleCondition
+		if rule.When != nil {
+			when = &models.ImportProfileRuleCondition{Field: rule.When.Field, Equals: rule.When.Equals}
+		}
+		out = append(out, models.ImportProfileRule{
+			ID:      rule.ID,
+			Type:    string(rule.Type),
+			Field:   rule.Field,
+			Pattern: rule.Pattern,
+			Min:     rule.Min,
+			Max:     rule.Max,
+			Values:  rule.Values,
+			When:    when,
+		})
+	}
+	return out
+}
+
+func toDomainValidationRules(rules models.ImportProfileRules) []domain.ValidationRule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	out := make([]domain.ValidationRule, 0, len(rules))
+	for _, rule := range rules {
+		var when *domain.RuleCondition
+		if rule.When != nil {
+			when = &domain.RuleCondition{Field: rule.When.Field, Equals: rule.When.Equals}
+		}
+		out = append(out, domain.ValidationRule{
+			ID:      rule.ID,
+			Type:    domain.ValidationRuleType(rule.Type),
+			Field:   rule.Field,
+			Pattern: rule.Pattern,
+			Min:     rule.Min,
+			Max:     rule.Max,
+			Values:  rule.Values,
+			When:    when,
+		})
+	}
+	return out
+}
@@ -0,0 +1,167 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestImportProfileRepositoryGetByNameIntegration(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect db: %v", err)
+	}
+
+	schemaSQL := `
+    CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+    CREATE TABLE IF NOT EXISTS import_profiles (
+      id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+      name TEXT NOT NULL UNIQUE,
+      rules JSONB NOT NULL DEFAULT '[]'::jsonb,
+      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+      updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+    ALTER TABLE import_profiles ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '{}'::jsonb;
+    `
+	if err := db.Exec(schemaSQL).Error; err != nil {
+		t.Fatalf("failed schema setup: %v", err)
+	}
+	if err := db.Exec("DELETE FROM import_profiles WHERE name = ?", "integration-crm").Error; err != nil {
+		t.Fatalf("cleanup import_profiles failed: %v", err)
+	}
+
+	rules := `[
+      {"id":"phone-required","type":"required","field":"phone_number"},
+      {"id":"us-zip","type":"regex","field":"addresses.zip_code","pattern":"^\\d{5}$","when":{"field":"addresses.country","equals":["US"]}}
+    ]`
+	if err := db.Exec("INSERT INTO import_profiles (name, rules) VALUES (?, ?::jsonb)", "integration-crm", rules).Error; err != nil {
+		t.Fatalf("insert import profile failed: %v", err)
+	}
+
+	repo := repository.NewImportProfileRepository(db)
+
+	profile, err := repo.GetByName(context.Background(), "integration-crm")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(profile.Rules) != 2 {
+		t.Fatalf("expected 2 rules, got %+v", profile.Rules)
+	}
+	zipRule := profile.Rules[1]
+	if zipRule.Type != domain.ValidationRuleRegex || zipRule.Pattern != `^\d{5}$` || zipRule.When == nil || zipRule.When.Field != domain.RuleFieldCountry {
+		t.Fatalf("unexpected rule: %+v", zipRule)
+	}
+	if _, err := domain.NewRuleSet(profile.Rules); err != nil {
+		t.Fatalf("expected stored rules to compile, got %v", err)
+	}
+
+	if _, err := repo.GetByName(context.Background(), "integration-missing"); !errors.Is(err, domain.ErrImportProfileNotFound) {
+		t.Fatalf("expected ErrImportProfileNotFound, got %v", err)
+	}
+}
+
+func TestImportProfileRepositoryCRUDIntegration(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("failed to connect db: %v", err)
+	}
+
+	schemaSQL := `
+    CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+    CREATE TABLE IF NOT EXISTS import_profiles (
+      id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+      name TEXT NOT NULL UNIQUE,
+      rules JSONB NOT NULL DEFAULT '[]'::jsonb,
+      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+      updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+    ALTER TABLE import_profiles ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '{}'::jsonb;
+    `
+	if err := db.Exec(schemaSQL).Error; err != nil {
+		t.Fatalf("failed schema setup: %v", err)
+	}
+	if err := db.Exec("DELETE FROM import_profiles WHERE name = ?", "integration-partner").Error; err != nil {
+		t.Fatalf("cleanup import_profiles failed: %v", err)
+	}
+
+	repo := repository.NewImportProfileRepository(db)
+	ctx := context.Background()
+
+	profile := domain.ImportProfile{
+		Name: "integration-partner",
+		Options: domain.ImportOptions{
+			AddressStrategy: domain.AddressStrategyMerge,
+			FieldMapping:    map[string]string{"fullName": "name"},
+			ChunkSize:       500,
+			MaxAttempts:     2,
+		},
+		Rules: []domain.ValidationRule{{ID: "phone-required", Type: domain.ValidationRuleRequired, Field: domain.RuleFieldPhoneNumber}},
+	}
+
+	created, err := repo.Create(ctx, profile)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if created.ID == "" || created.Options.ChunkSize != 500 {
+		t.Fatalf("unexpected created profile: %+v", created)
+	}
+	if _, err := repo.Create(ctx, profile); !errors.Is(err, domain.ErrImportProfileExists) {
+		t.Fatalf("expected ErrImportProfileExists, got %v", err)
+	}
+
+	profile.Options.AddressStrategy = domain.AddressStrategyAppend
+	profile.Rules = nil
+	updated, err := repo.Update(ctx, profile)
+	if err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if updated.Options.AddressStrategy != domain.AddressStrategyAppend || len(updated.Rules) != 0 {
+		t.Fatalf("unexpected updated profile: %+v", updated)
+	}
+
+	stored, err := repo.GetByName(ctx, "integration-partner")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if stored.Options.AddressStrategy != domain.AddressStrategyAppend || stored.Options.FieldMapping["fullName"] != "name" || stored.Options.MaxAttempts != 2 {
+		t.Fatalf("unexpected stored profile: %+v", stored)
+	}
+
+	profiles, err := repo.List(ctx, 1000, 0)
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	found := false
+	for _, p := range profiles {
+		found = found || p.Name == "integration-partner"
+	}
+	if !found {
+		t.Fatalf("expected listed profiles to include integration-partner, got %+v", profiles)
+	}
+
+	if err := repo.Delete(ctx, "integration-partner"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := repo.Delete(ctx, "integration-partner"); !errors.Is(err, domain.ErrImportProfileNotFound) {
+		t.Fatalf("expected ErrImportProfileNotFound, got %v", err)
+	}
+	if _, err := repo.Update(ctx, profile); !errors.Is(err, domain.ErrImportProfileNotFound) {
+		t.Fatalf("expected ErrImportProfileNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type StagingTables string
+
+const (
+	// StagingTablesTemporary stages each chunk in temporary tables that shadow
+	// stg_users and stg_addresses and are dropped when the chunk commits.
+	StagingTablesTemporary StagingTables = "temporary"
+	// StagingTablesShared stages chunks of all jobs in the shared unlogged tables.
+	StagingTablesShared StagingTables = "shared"
+)
+
+var ErrInvalidStagingTables = errors.New("invalid staging tables")
+
+func ParseStagingTables(value string) (StagingTables, error) {
+	switch staging := StagingTables(strings.ToLower(strings.TrimSpace(value))); staging {
+	case "":
+		return StagingTablesTemporary, nil
+	case StagingTablesTemporary, StagingTablesShared:
+		return staging, nil
+	default:
+		return "", ErrInvalidStagingTables
+	}
+}
+
+// createTemporaryStaging creates empty copies of the staging tables that only
+// this transaction sees. Unqualified names resolve to them first, so the merge
+// statements run unchanged.
+func createTemporaryStaging(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, `
+CREATE TEMP TABLE stg_users (LIKE stg_users INCLUDING DEFAULTS) ON COMMIT DROP;
+CREATE TEMP TABLE stg_addresses (LIKE stg_addresses INCLUDING DEFAULTS) ON COMMIT DROP;
+`); err != nil {
+		return fmt.Errorf("create temporary staging tables: %w", err)
+	}
+	return nil
+}
+
+// indexTemporaryStaging indexes a loaded chunk for the address joins and the
+// DISTINCT ON orderings of the upserts. Temporary tables are never analyzed by
+// autovacuum, so statistics are gathered here as well.
+func indexTemporaryStaging(ctx context.Context, tx pgx.Tx) error {
+	if _, err := tx.Exec(ctx, `
+CREATE INDEX ON stg_users (row_index);
+CREATE INDEX ON stg_users (user_id, source_modified_at DESC NULLS LAST, row_index DESC) WHERE user_id IS NOT NULL;
+CREATE INDEX ON stg_users (external_id, source_modified_at DESC NULLS LAST, row_index DESC) WHERE user_id IS NULL;
+CREATE INDEX ON stg_users (email_key, source_modified_at DESC NULLS LAST, row_index DESC) WHERE user_id IS NULL;
+CREATE INDEX ON stg_addresses (row_index);
+ANALYZE stg_users;
+ANALYZE stg_addresses;
+`); err != nil {
+		return fmt.Errorf("index temporary staging tables: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+)
+
+func TestParseStagingTables(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]repository.StagingTables{
+		"":            repository.StagingTablesTemporary,
+		" Temporary ": repository.StagingTablesTemporary,
+		"shared":      repository.StagingTablesShared,
+	}
+	for input, want := range cases {
+		if got, err := repository.ParseStagingTables(input); err != nil || got != want {
+			t.Fatalf("parse %q: expected %q, got %q, %v", input, want, got, err)
+		}
+	}
+	if _, err := repository.ParseStagingTables("partitioned"); err != repository.ErrInvalidStagingTables {
+		t.Fatalf("expected ErrInvalidStagingTables, got %v", err)
+	}
+}
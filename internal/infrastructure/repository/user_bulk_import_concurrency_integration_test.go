@@ -0,0 +1,114 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+)
+
+// concurrencyUsers builds a chunk of unique users plus the users shared by
+// every chunk, listed in chunk-specific order so concurrent chunks reach the
+// shared users in different orders.
+func concurrencyUsers(prefix string, chunk, size, shared int) []domain.User {
+	users := make([]domain.User, 0, size+shared)
+	for i := range size {
+		users = append(users, domain.User{
+			Name:           fmt.Sprintf("User %d-%d", chunk, i),
+			Email:          fmt.Sprintf("%s-%d-%d@example.com", prefix, chunk, i),
+			PhoneNumber:    "+15125550100",
+			SourcePosition: int64(chunk*(size+shared) + i),
+		})
+	}
+	for i := range shared {
+		index := (i + chunk) % shared
+		if chunk%2 == 1 {
+			index = shared - 1 - index
+		}
+		users = append(users, domain.User{
+			Name:           fmt.Sprintf("Shared %d from chunk %d", index, chunk),
+			Email:          fmt.Sprintf("%s-shared-%d@example.com", prefix, index),
+			PhoneNumber:    "+15125550100",
+			SourcePosition: int64(chunk*(size+shared) + size + i),
+		})
+	}
+	return users
+}
+
+func concurrencyJobID(writers int, run int64) string {
+	return fmt.Sprintf("00000000-0000-4000-9%03d-%012d", writers, run)
+}
+
+func importConcurrently(ctx context.Context, repo *repository.UserBulkImportRepository, jobID, prefix string, writers, size, shared int) error {
+	options := domain.ImportOptions{ChunkConcurrency: writers}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers)
+	for chunk := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := repo.ImportChunk(ctx, jobID, options, concurrencyUsers(prefix, chunk, size, shared)); err != nil {
+				errs <- fmt.Errorf("chunk %d: %w", chunk, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	return <-errs
+}
+
+func TestUserBulkImportRepositoryConcurrentChunksShareUsersIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	const writers, shared = 4, 20
+
+	for run := range int64(3) {
+		if err := importConcurrently(context.Background(), repo, concurrencyJobID(writers, run), "share", writers, 50, shared); err != nil {
+			t.Fatalf("run %d: %v", run, err)
+		}
+	}
+
+	var users int64
+	if err := gdb.Raw("SELECT COUNT(*) FROM users WHERE email LIKE 'share-%'").Scan(&users).Error; err != nil {
+		t.Fatalf("count users failed: %v", err)
+	}
+	if users != writers*50+shared {
+		t.Fatalf("expected %d users, got %d", writers*50+shared, users)
+	}
+
+	// The last chunk of the source lists the shared users last, so its rows win
+	// whichever chunk commits last.
+	var stale int64
+	if err := gdb.Raw("SELECT COUNT(*) FROM users WHERE email LIKE 'share-shared-%' AND name NOT LIKE ?", fmt.Sprintf("%% from chunk %d", writers-1)).Scan(&stale).Error; err != nil {
+		t.Fatalf("count stale users failed: %v", err)
+	}
+	if stale != 0 {
+		t.Fatalf("expected every shared user to hold the last chunk's row, got %d stale users", stale)
+	}
+}
+
+// BenchmarkUserBulkImportConcurrencyIntegration measures the rows merged per
+// second as more chunks of one job are written at once. Every chunk shares a
+// few users with the others, so the chunks contend on those rows only.
+func BenchmarkUserBulkImportConcurrencyIntegration(b *testing.B) {
+	_, pool := setupBulkImportIntegration(b)
+
+	const chunkSize, shared = 1000, 20
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	for _, writers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("writers=%d", writers), func(b *testing.B) {
+			for run := range int64(b.N) {
+				prefix := fmt.Sprintf("bench-%d-%d", writers, run)
+				if err := importConcurrently(context.Background(), repo, concurrencyJobID(writers, run), prefix, writers, chunkSize, shared); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(b.N*writers*(chunkSize+shared))/b.Elapsed().Seconds(), "rows/s")
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package repository_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+)
+
+// partitionJobs stands in for the job queue so that partition jobs can be run
+// through the worker against the real importer.
+type partitionJobs struct {
+	summaries map[string]domain.ImportSummary
+}
+
+func (p *partitionJobs) ClaimNext(ctx context.Context, leaseDuration time.Duration) (*domain.ImportJob, error) {
+	return nil, nil
+}
+
+func (p *partitionJobs) Heartbeat(ctx context.Context, jobID string, leaseDuration time.Duration) error {
+	return nil
+}
+
+func (p *partitionJobs) UpdateProgress(ctx context.Context, jobID string, progress domain.ImportProgress) error {
+	return nil
+}
+
+func (p *partitionJobs) Complete(ctx context.Context, jobID string, summary domain.ImportSummary) error {
+	p.summaries[jobID] = summary
+	return nil
+}
+
+func (p *partitionJobs) Requeue(ctx context.Context, jobID string, reason string) error {
+	return nil
+}
+
+func (p *partitionJobs) Fail(ctx context.Context, jobID string, reason string) error {
+	return nil
+}
+
+func (p *partitionJobs) RecordConflicts(ctx context.Context, jobID string, conflicts []domain.ImportConflict) error {
+	return nil
+}
+
+func (p *partitionJobs) Partition(ctx context.Context, jobID string, partitions []domain.ImportPartition) error {
+	return nil
+}
+
+type stringRangeSource string
+
+func (s stringRangeSource) Open(ctx context.Context, sourcePath string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(string(s))), nil
+}
+
+func (s stringRangeSource) Size(ctx context.Context, sourcePath string) (int64, error) {
+	return int64(len(s)), nil
+}
+
+func (s stringRangeSource) OpenRange(ctx context.Context, sourcePath string, start, end int64) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(string(s[start:end]))), nil
+}
+
+func TestUserBulkImportRepositoryPartitionsKeepSourceOrderIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	const source = `{"id":"","name":"Older Frank","email":"frank@example.com","phone_number":"+15125550100"}
+{"id":"","name":"Newer Frank","email":"frank@example.com","phone_number":"+15125550101"}
+`
+	split := int64(strings.Index(source, "\n") + 1)
+	const parentID = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c31"
+	childIDs := []string{"9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c32", "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c33"}
+
+	jobs := &partitionJobs{summaries: make(map[string]domain.ImportSummary)}
+	importer := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	worker := app.NewImportWorker(jobs, stringRangeSource(source), importer, app.ImportWorkerConfig{ChunkSize: 10, LeaseDuration: 30 * time.Second})
+
+	options := domain.ImportOptions{Format: domain.ImportFormatNDJSON, Partitions: 2}
+	partitions := []domain.ImportPartition{
+		{Index: 0, Start: 0, End: split},
+		{Index: 1, Start: split, End: int64(len(source))},
+	}
+
+	// The partition later in the source commits first.
+	for _, partition := range []domain.ImportPartition{partitions[1], partitions[0]} {
+		job := domain.ImportJob{
+			ID:          childIDs[partition.Index],
+			ParentID:    parentID,
+			Partition:   &partition,
+			SourcePath:  "users.ndjson",
+			Attempts:    1,
+			MaxAttempts: 1,
+			Options:     options,
+		}
+		if err := worker.ProcessJob(context.Background(), job); err != nil {
+			t.Fatalf("partition %d failed: %v", partition.Index, err)
+		}
+	}
+
+	earlier := jobs.summaries[childIDs[0]]
+	if len(earlier.Skipped) != 1 || earlier.Skipped[0].Reason != domain.SkipReasonSupersededInSource {
+		t.Fatalf("expected the earlier partition's row to be superseded, got %+v", earlier)
+	}
+
+	var user struct {
+		Name               string
+		LastImportJobID    string
+		LastImportPosition int64
+	}
+	if err := gdb.Raw("SELECT name, last_import_job_id, last_import_position FROM users WHERE email = ?", "frank@example.com").Scan(&user).Error; err != nil {
+		t.Fatalf("select user failed: %v", err)
+	}
+	if user.Name != "Newer Frank" || user.LastImportJobID != parentID || user.LastImportPosition != split {
+		t.Fatalf("expected the row later in the source to win under the parent job, got %+v", user)
+	}
+}
@@ -2,235 +2,1147 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 )
 
-const uuidRegex = "^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-5][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$"
+const uuidRegex = domain.UUIDPattern
+
+// importChunkAttempts bounds the retries of a chunk chosen as a deadlock victim.
+// Existing users are locked in id order, so only inserts of the same new users
+// by concurrent chunks can still deadlock.
+const importChunkAttempts = 3
+
+type UserBulkImportConfig struct {
+	GenerateUUIDv7 bool
+	StagingTables  StagingTables
+}
 
 type UserBulkImportRepository struct {
 	pool *pgxpool.Pool
+	cfg  UserBulkImportConfig
 }
 
-func NewUserBulkImportRepository(pool *pgxpool.Pool) *UserBulkImportRepository {
-	return &UserBulkImportRepository{pool: pool}
+func NewUserBulkImportRepository(pool *pgxpool.Pool, cfg UserBulkImportConfig) *UserBulkImportRepository {
+	if cfg.StagingTables == "" {
+		cfg.StagingTables = StagingTablesTemporary
+	}
+	return &UserBulkImportRepository{pool: pool, cfg: cfg}
 }
 
-func (r *UserBulkImportRepository) ImportChunk(ctx context.Context, jobID string, users []domain.User) (domain.ImportChunkResult, error) {
+func (r *UserBulkImportRepository) ImportChunk(ctx context.Context, jobID string, options domain.ImportOptions, users []domain.User) (domain.ImportChunkResult, error) {
 	if len(users) == 0 {
 		return domain.ImportChunkResult{}, nil
 	}
 
+	for attempt := 1; ; attempt++ {
+		result, err := r.importChunk(ctx, jobID, options, users)
+		if err == nil || attempt == importChunkAttempts || !isDeadlock(err) {
+			return result, err
+		}
+	}
+}
+
+func isDeadlock(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "40P01"
+}
+
+func (r *UserBulkImportRepository) importChunk(ctx context.Context, jobID string, options domain.ImportOptions, users []domain.User) (domain.ImportChunkResult, error) {
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return domain.ImportChunkResult{}, fmt.Errorf("begin tx: %w", err)
 	}
 	defer tx.Rollback(ctx)
 
-	userRows := make([][]any, 0, len(users))
-	addressRows := make([][]any, 0)
-	for i, user := range users {
-		userRows = append(userRows, []any{jobID, int64(i), nullableText(user.ID), user.Name, user.Email, user.PhoneNumber})
-		for _, address := range user.Addresses {
-			addressRows = append(addressRows, []any{
-				jobID,
-				int64(i),
-				nullableText(user.ID),
-				user.Email,
-				address.Street,
-				address.City,
-				address.State,
-				address.ZipCode,
-				address.Country,
-			})
-		}
-	}
-
-	if _, err := tx.CopyFrom(
-		ctx,
-		pgx.Identifier{"stg_users"},
-		[]string{"job_id", "row_index", "external_id", "name", "email", "phone_number"},
-		pgx.CopyFromRows(userRows),
-	); err != nil {
+	temporary := r.cfg.StagingTables == StagingTablesTemporary
+	if temporary {
+		if err := createTemporaryStaging(ctx, tx); err != nil {
+			return domain.ImportChunkResult{}, err
+		}
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"stg_users"}, stagedUserColumns, newUserCopySource(jobID, users)); err != nil {
 		return domain.ImportChunkResult{}, fmt.Errorf("copy users staging: %w", err)
 	}
 
-	if len(addressRows) > 0 {
-		if _, err := tx.CopyFrom(
-			ctx,
-			pgx.Identifier{"stg_addresses"},
-			[]string{"job_id", "row_index", "user_external_id", "user_email", "street", "city", "state", "zip_code", "country"},
-			pgx.CopyFromRows(addressRows),
-		); err != nil {
+	if hasAddresses(users) {
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{"stg_addresses"}, stagedAddressColumns, newAddressCopySource(jobID, users)); err != nil {
 			return domain.ImportChunkResult{}, fmt.Errorf("copy addresses staging: %w", err)
 		}
 	}
+	if temporary {
+		if err := indexTemporaryStaging(ctx, tx); err != nil {
+			return domain.ImportChunkResult{}, err
+		}
+	}
+
+	policies := options.UpdatePolicies.WithDefaults()
+	// Partitions and concurrent chunks of one job commit in any order, so rows of
+	// the same job are ordered by their position in the source file instead.
+	ordered := options.Partitions > 1 || options.ChunkConcurrency > 1
+
+	if options.Source != "" {
+		if err := resolveMappedUsers(ctx, tx, jobID, options.Source); err != nil {
+			return domain.ImportChunkResult{}, err
+		}
+		if err := unifyExternalIDEmails(ctx, tx, jobID); err != nil {
+			return domain.ImportChunkResult{}, err
+		}
+	}
+	if err := lockStagedUsers(ctx, tx, jobID); err != nil {
+		return domain.ImportChunkResult{}, err
+	}
+
+	conflicts, err := detectIdentityConflicts(ctx, tx, jobID, policies.Email, ordered)
+	if err != nil {
+		return domain.ImportChunkResult{}, err
+	}
+	if err := resolveIdentityConflicts(ctx, tx, jobID, options.ConflictPolicy, conflicts); err != nil {
+		return domain.ImportChunkResult{}, err
+	}
+
+	stagedConflicts, err := detectStagedIdentityConflicts(ctx, tx, jobID, policies.Email)
+	if err != nil {
+		return domain.ImportChunkResult{}, err
+	}
+	if err := resolveStagedIdentityConflicts(ctx, tx, jobID, options.ConflictPolicy, policies.Email, stagedConflicts); err != nil {
+		return domain.ImportChunkResult{}, err
+	}
+	conflicts = append(conflicts, stagedConflicts...)
 
-	imported, updated, err := upsertUsersByExternalID(ctx, tx, jobID)
+	var outcome upsertOutcome
+	resolved, err := updateResolvedUsers(ctx, tx, jobID, policies, options.AttributeStrategy, ordered)
 	if err != nil {
 		return domain.ImportChunkResult{}, err
 	}
+	outcome.add(resolved)
 
-	importedByEmail, updatedByEmail, err := upsertUsersByEmail(ctx, tx, jobID)
+	byExternalID, err := upsertUsersByExternalID(ctx, tx, jobID, policies, options.AttributeStrategy, ordered)
 	if err != nil {
 		return domain.ImportChunkResult{}, err
 	}
-	imported += importedByEmail
-	updated += updatedByEmail
+	outcome.add(byExternalID)
 
-	if err := replaceAddresses(ctx, tx, jobID); err != nil {
+	byEmail, err := upsertUsersByEmail(ctx, tx, jobID, policies, options.AttributeStrategy, r.cfg.GenerateUUIDv7, ordered)
+	if err != nil {
 		return domain.ImportChunkResult{}, err
 	}
+	outcome.add(byEmail)
 
-	if _, err := tx.Exec(ctx, "DELETE FROM stg_addresses WHERE job_id = $1", jobID); err != nil {
-		return domain.ImportChunkResult{}, fmt.Errorf("cleanup stg_addresses: %w", err)
+	if err := discardStagedRows(ctx, tx, jobID, outcome.discarded); err != nil {
+		return domain.ImportChunkResult{}, err
 	}
-	if _, err := tx.Exec(ctx, "DELETE FROM stg_users WHERE job_id = $1", jobID); err != nil {
-		return domain.ImportChunkResult{}, fmt.Errorf("cleanup stg_users: %w", err)
+
+	if err := resolveStagedUsers(ctx, tx, jobID); err != nil {
+		return domain.ImportChunkResult{}, err
+	}
+
+	if options.Source != "" {
+		if err := recordExternalIDs(ctx, tx, jobID, options.Source); err != nil {
+			return domain.ImportChunkResult{}, err
+		}
+	}
+
+	if err := applyAddresses(ctx, tx, jobID, options.AddressStrategy); err != nil {
+		return domain.ImportChunkResult{}, err
+	}
+
+	if !temporary {
+		if _, err := tx.Exec(ctx, "DELETE FROM stg_addresses WHERE job_id = $1", jobID); err != nil {
+			return domain.ImportChunkResult{}, fmt.Errorf("cleanup stg_addresses: %w", err)
+		}
+		if _, err := tx.Exec(ctx, "DELETE FROM stg_users WHERE job_id = $1", jobID); err != nil {
+			return domain.ImportChunkResult{}, fmt.Errorf("cleanup stg_users: %w", err)
+		}
 	}
 
 	if err := tx.Commit(ctx); err != nil {
 		return domain.ImportChunkResult{}, fmt.Errorf("commit import chunk: %w", err)
 	}
 
+	skipped := make([]domain.ImportSkip, 0, len(outcome.stale)+len(outcome.superseded))
+	for _, rowIndex := range outcome.stale {
+		skipped = append(skipped, domain.ImportSkip{
+			RowIndex: rowIndex,
+			Reason:   domain.SkipReasonStaleSourceTimestamp,
+		})
+	}
+	for _, rowIndex := range outcome.superseded {
+		skipped = append(skipped, domain.ImportSkip{
+			RowIndex: rowIndex,
+			Reason:   domain.SkipReasonSupersededInSource,
+		})
+	}
+
+	var failures []domain.ImportFailure
+	for _, conflict := range conflicts {
+		if conflict.Resolution != domain.IdentityConflictReject {
+			continue
+		}
+		failures = append(failures, domain.ImportFailure{
+			RowIndex: conflict.RowIndex,
+			Reason:   domain.FailureReasonIdentityConflict,
+		})
+	}
+
 	return domain.ImportChunkResult{
-		ImportedCount: imported,
-		UpdatedCount:  updated,
-		SkippedCount:  0,
+		ImportedCount: outcome.imported,
+		UpdatedCount:  outcome.updated,
+		SkippedCount:  int64(len(skipped) + len(failures)),
+		FailedCount:   int64(len(failures)),
+		Skipped:       skipped,
+		Failures:      failures,
+		Conflicts:     conflicts,
 	}, nil
 }
 
-func upsertUsersByExternalID(ctx context.Context, tx pgx.Tx, jobID string) (int64, int64, error) {
+type upsertOutcome struct {
+	imported   int64
+	updated    int64
+	stale      []int64
+	superseded []int64
+	// discarded holds every staged row of the stale and superseded users, not
+	// just the row that won the per-user deduplication.
+	discarded []int64
+}
+
+func (o *upsertOutcome) add(other upsertOutcome) {
+	o.imported += other.imported
+	o.updated += other.updated
+	o.stale = append(o.stale, other.stale...)
+	o.superseded = append(o.superseded, other.superseded...)
+	o.discarded = append(o.discarded, other.discarded...)
+}
+
+func resolveMappedUsers(ctx context.Context, tx pgx.Tx, jobID, source string) error {
+	if _, err := tx.Exec(ctx, `
+UPDATE stg_users s
+SET user_id = m.user_id
+FROM user_external_ids m
+WHERE s.job_id = $1
+  AND m.source = $2
+  AND m.external_id = s.external_id
+`, jobID, source); err != nil {
+		return fmt.Errorf("resolve mapped users: %w", err)
+	}
+	return nil
+}
+
+// unifyExternalIDEmails gives every unmapped row of a non-UUID external id the
+// email of its latest row, so the email upsert creates one user per id.
+func unifyExternalIDEmails(ctx context.Context, tx pgx.Tx, jobID string) error {
+	if _, err := tx.Exec(ctx, `
+WITH latest AS (
+    SELECT DISTINCT ON (external_id) external_id, email, email_key
+    FROM stg_users
+    WHERE job_id = $1
+      AND user_id IS NULL
+      AND external_id IS NOT NULL
+      AND external_id <> ''
+      AND NOT (external_id ~* $2)
+    ORDER BY external_id, source_modified_at DESC NULLS LAST, row_index DESC
+)
+UPDATE stg_users s
+SET email = l.email, email_key = l.email_key
+FROM latest l
+WHERE s.job_id = $1
+  AND s.user_id IS NULL
+  AND s.external_id = l.external_id
+  AND s.email_key IS DISTINCT FROM l.email_key
+`, jobID, uuidRegex); err != nil {
+		return fmt.Errorf("unify external id emails: %w", err)
+	}
+	return nil
+}
+
+// lockStagedUsers locks every existing user the chunk can match, in id order,
+// before any of them is read. Chunks sharing users wait for each other on the
+// first shared row instead of deadlocking, while chunks with disjoint users
+// merge in parallel.
+func lockStagedUsers(ctx context.Context, tx pgx.Tx, jobID string) error {
+	if _, err := tx.Exec(ctx, `
+SELECT u.id
+FROM users u
+WHERE u.id IN (
+    SELECT user_id FROM stg_users WHERE job_id = $1 AND user_id IS NOT NULL
+    UNION
+    SELECT CASE WHEN external_id ~* $2 THEN external_id::uuid END FROM stg_users WHERE job_id = $1
+  )
+  OR u.email_key IN (SELECT email_key FROM stg_users WHERE job_id = $1 AND email_key IS NOT NULL)
+ORDER BY u.id
+FOR UPDATE
+`, jobID, uuidRegex); err != nil {
+		return fmt.Errorf("lock staged users: %w", err)
+	}
+	return nil
+}
+
+// keepsExistingEmail holds when the email policy ($3) leaves the email of the
+// existing user a untouched, so the row cannot take another user's email.
+const keepsExistingEmail = `($3::text = 'never' OR ($3::text = 'fill_empty' AND btrim(a.email) <> ''))`
+
+func detectIdentityConflicts(ctx context.Context, tx pgx.Tx, jobID string, emailPolicy domain.FieldUpdatePolicy, ordered bool) ([]domain.ImportConflict, error) {
+	rows, err := tx.Query(ctx, `
+WITH staged AS (
+    SELECT
+      row_index,
+      COALESCE(external_id, '') AS external_id,
+      email_key,
+      source_position,
+      COALESCE(user_id, CASE WHEN external_id ~* $2 THEN external_id::uuid ELSE NULL END) AS target_id
+    FROM stg_users
+    WHERE job_id = $1
+)
+SELECT
+  s.row_index,
+  s.external_id,
+  s.target_id::text,
+  b.id::text,
+  $4 AND b.created_import_job_id IS NOT DISTINCT FROM $1::uuid,
+  COALESCE(b.last_import_job_id = $1::uuid AND b.last_import_position > s.source_position, FALSE)
+FROM staged s
+JOIN users b ON b.email_key = s.email_key AND b.id <> s.target_id
+WHERE s.target_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM users a WHERE a.id = s.target_id AND `+keepsExistingEmail+`)
+ORDER BY s.row_index
+`, jobID, uuidRegex, string(emailPolicy), ordered)
+	if err != nil {
+		return nil, fmt.Errorf("detect identity conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []domain.ImportConflict
+	for rows.Next() {
+		var conflict domain.ImportConflict
+		var sameJob, later bool
+		if err := rows.Scan(&conflict.RowIndex, &conflict.ExternalID, &conflict.IDUserID, &conflict.EmailUserID, &sameJob, &later); err != nil {
+			return nil, fmt.Errorf("scan identity conflict: %w", err)
+		}
+		// When this ordered job created the email owner, the row later in the
+		// source keeps the email, whichever chunk reaches it first. Owners that
+		// predate the job get the job's policy.
+		switch {
+		case sameJob && later:
+			conflict.Resolution = domain.IdentityConflictSuperseded
+		case sameJob:
+			conflict.Resolution = domain.IdentityConflictReassignEmail
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("detect identity conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+func resolveIdentityConflicts(ctx context.Context, tx pgx.Tx, jobID string, policy domain.IdentityConflictPolicy, conflicts []domain.ImportConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if policy == "" {
+		policy = domain.IdentityConflictReject
+	}
+
+	var groups []domain.IdentityConflictPolicy
+	byResolution := make(map[domain.IdentityConflictPolicy][]domain.ImportConflict)
+	for i := range conflicts {
+		if conflicts[i].Resolution == "" {
+			conflicts[i].Resolution = policy
+		}
+		resolution := conflicts[i].Resolution
+		if _, ok := byResolution[resolution]; !ok {
+			groups = append(groups, resolution)
+		}
+		byResolution[resolution] = append(byResolution[resolution], conflicts[i])
+	}
+
+	for _, resolution := range groups {
+		if err := applyConflictResolution(ctx, tx, jobID, resolution, byResolution[resolution]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyConflictResolution(ctx context.Context, tx pgx.Tx, jobID string, resolution domain.IdentityConflictPolicy, conflicts []domain.ImportConflict) error {
+	rowIndexes := make([]int64, 0, len(conflicts))
+	idUserIDs := make([]string, 0, len(conflicts))
+	emailUserIDs := make([]string, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		rowIndexes = append(rowIndexes, conflict.RowIndex)
+		idUserIDs = append(idUserIDs, conflict.IDUserID)
+		emailUserIDs = append(emailUserIDs, conflict.EmailUserID)
+	}
+
+	switch resolution {
+	case domain.IdentityConflictReject:
+		return discardStagedRows(ctx, tx, jobID, rowIndexes)
+	case domain.IdentityConflictReassignEmail:
+		if _, err := tx.Exec(ctx, `
+UPDATE users
+SET email = NULL, email_key = NULL, updated_at = NOW()
+WHERE id = ANY($1::text[]::uuid[])
+`, emailUserIDs); err != nil {
+			return fmt.Errorf("release conflicting emails: %w", err)
+		}
+		return nil
+	case domain.IdentityConflictMerge:
+		return mergeConflictingUsers(ctx, tx, jobID, rowIndexes, idUserIDs, emailUserIDs)
+	case domain.IdentityConflictSuperseded:
+		if _, err := tx.Exec(ctx, `
+UPDATE stg_users
+SET email = NULL, email_key = NULL
+WHERE job_id = $1 AND row_index = ANY($2)
+`, jobID, rowIndexes); err != nil {
+			return fmt.Errorf("drop superseded emails: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("resolve identity conflicts: %w: %q", domain.ErrInvalidIdentityConflictPolicy, resolution)
+	}
+}
+
+// stagedEmailClaims lists the staged rows that will write their email to a
+// known target user, which is where two rows of one chunk can collide.
+const stagedEmailClaims = `
+WITH claims AS (
+    SELECT
+      s.row_index,
+      COALESCE(s.external_id, '') AS external_id,
+      s.user_id,
+      s.email_key,
+      COALESCE(s.user_id, CASE WHEN s.external_id ~* $2 THEN s.external_id::uuid ELSE NULL END) AS target_id
+    FROM stg_users s
+    WHERE s.job_id = $1 AND s.email_key IS NOT NULL
+), staged AS (
+    SELECT c.*
+    FROM claims c
+    WHERE c.target_id IS NOT NULL
+      AND NOT EXISTS (SELECT 1 FROM users a WHERE a.id = c.target_id AND ` + keepsExistingEmail + `)
+)
+`
+
+// detectStagedIdentityConflicts finds rows of the chunk whose email is claimed
+// by an earlier row for a different user. The earlier row owns the email, as it
+// would if the rows had been imported one at a time.
+func detectStagedIdentityConflicts(ctx context.Context, tx pgx.Tx, jobID string, emailPolicy domain.FieldUpdatePolicy) ([]domain.ImportConflict, error) {
+	rows, err := tx.Query(ctx, stagedEmailClaims+`
+SELECT s.row_index, s.external_id, s.target_id::text, o.target_id::text
+FROM staged s
+JOIN LATERAL (
+    SELECT p.target_id
+    FROM staged p
+    WHERE p.email_key = s.email_key
+    ORDER BY p.row_index
+    LIMIT 1
+) o ON o.target_id <> s.target_id
+ORDER BY s.row_index
+`, jobID, uuidRegex, string(emailPolicy))
+	if err != nil {
+		return nil, fmt.Errorf("detect staged identity conflicts: %w", err)
+	}
+	defer rows.Close()
+
+	var conflicts []domain.ImportConflict
+	for rows.Next() {
+		var conflict domain.ImportConflict
+		if err := rows.Scan(&conflict.RowIndex, &conflict.ExternalID, &conflict.IDUserID, &conflict.EmailUserID); err != nil {
+			return nil, fmt.Errorf("scan staged identity conflict: %w", err)
+		}
+		conflicts = append(conflicts, conflict)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("detect staged identity conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+func resolveStagedIdentityConflicts(ctx context.Context, tx pgx.Tx, jobID string, policy domain.IdentityConflictPolicy, emailPolicy domain.FieldUpdatePolicy, conflicts []domain.ImportConflict) error {
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	if policy == "" {
+		policy = domain.IdentityConflictReject
+	}
+
+	rowIndexes := make([]int64, 0, len(conflicts))
+	emailUserIDs := make([]string, 0, len(conflicts))
+	for i := range conflicts {
+		conflicts[i].Resolution = policy
+		rowIndexes = append(rowIndexes, conflicts[i].RowIndex)
+		emailUserIDs = append(emailUserIDs, conflicts[i].EmailUserID)
+	}
+
+	switch policy {
+	case domain.IdentityConflictReject:
+		return discardStagedRows(ctx, tx, jobID, rowIndexes)
+	case domain.IdentityConflictReassignEmail:
+		// The last row claiming an email keeps it; every other user claiming it
+		// in this chunk is staged without an email.
+		if _, err := tx.Exec(ctx, stagedEmailClaims+`
+, keeper AS (
+    SELECT DISTINCT ON (email_key) email_key, target_id
+    FROM staged
+    WHERE email_key IN (SELECT email_key FROM stg_users WHERE job_id = $1 AND row_index = ANY($4))
+    ORDER BY email_key, row_index DESC
+)
+UPDATE stg_users u
+SET email = NULL, email_key = NULL
+FROM staged s
+JOIN keeper k ON k.email_key = s.email_key
+WHERE u.job_id = $1 AND u.row_index = s.row_index AND s.target_id <> k.target_id
+`, jobID, uuidRegex, string(emailPolicy), rowIndexes); err != nil {
+			return fmt.Errorf("release staged conflicting emails: %w", err)
+		}
+		return nil
+	case domain.IdentityConflictMerge:
+		// The later row is applied to the user of the row that owns the email.
+		if _, err := tx.Exec(ctx, `
+UPDATE stg_users s
+SET user_id = o.user_id,
+    external_id = CASE WHEN o.user_id IS NULL THEN o.external_id ELSE s.external_id END
+FROM unnest($2::bigint[], $3::text[]) AS c(row_index, email_user_id)
+JOIN LATERAL (
+    SELECT p.user_id, p.external_id
+    FROM stg_users p
+    WHERE p.job_id = $1
+      AND COALESCE(p.user_id, CASE WHEN p.external_id ~* $4 THEN p.external_id::uuid ELSE NULL END) = c.email_user_id::uuid
+    ORDER BY p.row_index
+    LIMIT 1
+) o ON TRUE
+WHERE s.job_id = $1 AND s.row_index = c.row_index
+`, jobID, rowIndexes, emailUserIDs, uuidRegex); err != nil {
+			return fmt.Errorf("retarget staged conflicting rows: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("resolve staged identity conflicts: %w: %q", domain.ErrInvalidIdentityConflictPolicy, policy)
+	}
+}
+
+func mergeConflictingUsers(ctx context.Context, tx pgx.Tx, jobID string, rowIndexes []int64, idUserIDs, emailUserIDs []string) error {
+	if _, err := tx.Exec(ctx, `
+UPDATE stg_users s
+SET user_id = c.email_user_id::uuid
+FROM unnest($2::bigint[], $3::text[]) AS c(row_index, email_user_id)
+WHERE s.job_id = $1 AND s.row_index = c.row_index
+`, jobID, rowIndexes, emailUserIDs); err != nil {
+		return fmt.Errorf("retarget conflicting rows: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+UPDATE addresses a
+SET user_id = p.to_id, is_default = FALSE, updated_at = NOW()
+FROM (
+    SELECT DISTINCT ON (c.id_user_id) c.id_user_id::uuid AS from_id, c.email_user_id::uuid AS to_id
+    FROM unnest($1::text[], $2::text[]) AS c(id_user_id, email_user_id)
+    ORDER BY c.id_user_id
+) p
+WHERE a.user_id = p.from_id
+`, idUserIDs, emailUserIDs); err != nil {
+		return fmt.Errorf("move merged user addresses: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+UPDATE user_external_ids m
+SET user_id = p.to_id
+FROM (
+    SELECT DISTINCT ON (c.id_user_id) c.id_user_id::uuid AS from_id, c.email_user_id::uuid AS to_id
+    FROM unnest($1::text[], $2::text[]) AS c(id_user_id, email_user_id)
+    ORDER BY c.id_user_id
+) p
+WHERE m.user_id = p.from_id
+`, idUserIDs, emailUserIDs); err != nil {
+		return fmt.Errorf("move merged user external ids: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+UPDATE users u
+SET attributes = f.attributes || u.attributes, updated_at = NOW()
+FROM (
+    SELECT DISTINCT ON (c.id_user_id) c.id_user_id::uuid AS from_id, c.email_user_id::uuid AS to_id
+    FROM unnest($1::text[], $2::text[]) AS c(id_user_id, email_user_id)
+    ORDER BY c.id_user_id
+) p
+JOIN users f ON f.id = p.from_id
+WHERE u.id = p.to_id AND p.from_id <> p.to_id
+`, idUserIDs, emailUserIDs); err != nil {
+		return fmt.Errorf("merge user attributes: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+DELETE FROM users
+WHERE id = ANY($1::text[]::uuid[])
+  AND NOT (id = ANY($2::text[]::uuid[]))
+`, idUserIDs, emailUserIDs); err != nil {
+		return fmt.Errorf("delete merged users: %w", err)
+	}
+
+	return nil
+}
+
+func updateResolvedUsers(ctx context.Context, tx pgx.Tx, jobID string, policies domain.FieldUpdatePolicies, attributes domain.AttributeStrategy, ordered bool) (upsertOutcome, error) {
+	rows, err := tx.Query(ctx, `
+WITH staged AS (
+    SELECT DISTINCT ON (user_id)
+      row_index,
+      user_id,
+      name,
+      email,
+      email_key,
+      phone_number,
+      phone_number_raw,
+      source_modified_at,
+      attributes,
+      source_position
+    FROM stg_users
+    WHERE job_id = $1 AND user_id IS NOT NULL
+    ORDER BY user_id, source_modified_at DESC NULLS LAST, row_index DESC
+), updated AS (
+    UPDATE users u
+    SET name = apply_field_update_policy($2, u.name, s.name),
+        email = apply_field_update_policy($3, u.email, s.email),
+        email_key = CASE
+          WHEN apply_field_update_policy($3, u.email, s.email) IS NOT DISTINCT FROM s.email THEN s.email_key
+          ELSE u.email_key
+        END,
+        phone_number = apply_field_update_policy($4, u.phone_number, s.phone_number),
+        phone_number_raw = CASE
+          WHEN apply_field_update_policy($4, u.phone_number, s.phone_number) IS NOT DISTINCT FROM s.phone_number THEN s.phone_number_raw
+          ELSE u.phone_number_raw
+        END,
+        source_modified_at = COALESCE(s.source_modified_at, u.source_modified_at),
+        attributes = apply_attribute_strategy($5, u.attributes, s.attributes),
+        last_import_job_id = $1,
+        last_import_position = s.source_position,
+        updated_at = NOW()
+    FROM staged s
+    WHERE u.id = s.user_id
+      AND (
+        u.source_modified_at IS NULL
+        OR s.source_modified_at IS NULL
+        OR s.source_modified_at > u.source_modified_at
+      )
+      AND (NOT $6 OR u.last_import_job_id IS DISTINCT FROM $1 OR u.last_import_position <= s.source_position)
+    RETURNING u.id
+)
+SELECT
+  s.row_index,
+  CASE WHEN u.id IS NULL THEN NULL ELSE FALSE END,
+  $6 AND COALESCE(x.last_import_job_id = $1 AND x.last_import_position > s.source_position, FALSE),
+  CASE WHEN u.id IS NULL THEN ARRAY(
+    SELECT r.row_index FROM stg_users r WHERE r.job_id = $1 AND r.user_id = s.user_id
+  ) END
+FROM staged s
+LEFT JOIN updated u ON u.id = s.user_id
+LEFT JOIN users x ON x.id = s.user_id
+`, jobID, string(policies.Name), string(policies.Email), string(policies.PhoneNumber), string(attributes), ordered)
+	if err != nil {
+		return upsertOutcome{}, fmt.Errorf("update resolved users: %w", err)
+	}
+	defer rows.Close()
+
+	return collectUpsertOutcome(rows)
+}
+
+func upsertUsersByExternalID(ctx context.Context, tx pgx.Tx, jobID string, policies domain.FieldUpdatePolicies, attributes domain.AttributeStrategy, ordered bool) (upsertOutcome, error) {
 	rows, err := tx.Query(ctx, `
 WITH staged AS (
     SELECT DISTINCT ON (external_id)
+      row_index,
+      external_id,
       CASE WHEN external_id ~* $2 THEN external_id::uuid ELSE NULL END AS ext_uuid,
       name,
       email,
-      phone_number
+      email_key,
+      phone_number,
+      phone_number_raw,
+      source_modified_at,
+      attributes,
+      source_position
     FROM stg_users
-    WHERE job_id = $1 AND external_id IS NOT NULL AND external_id <> ''
-    ORDER BY external_id, row_index DESC
+    WHERE job_id = $1 AND user_id IS NULL AND external_id IS NOT NULL AND external_id <> ''
+    ORDER BY external_id, source_modified_at DESC NULLS LAST, row_index DESC
 ), upserted AS (
-    INSERT INTO users (id, name, email, phone_number, created_at, updated_at)
-    SELECT ext_uuid, name, email, phone_number, NOW(), NOW()
+    INSERT INTO users (id, name, email, email_key, phone_number, phone_number_raw, source_modified_at, attributes, last_import_job_id, last_import_position, created_import_job_id, created_at, updated_at)
+    SELECT ext_uuid, name, email, email_key, phone_number, phone_number_raw, source_modified_at, COALESCE(attributes, '{}'::jsonb), $1, source_position, $1, NOW(), NOW()
     FROM staged
     WHERE ext_uuid IS NOT NULL
     ON CONFLICT (id) DO UPDATE
-      SET name = EXCLUDED.name,
-          email = EXCLUDED.email,
-          phone_number = EXCLUDED.phone_number,
+      SET name = apply_field_update_policy($3, users.name, EXCLUDED.name),
+          email = apply_field_update_policy($4, users.email, EXCLUDED.email),
+          email_key = CASE
+            WHEN apply_field_update_policy($4, users.email, EXCLUDED.email) IS NOT DISTINCT FROM EXCLUDED.email THEN EXCLUDED.email_key
+            ELSE users.email_key
+          END,
+          phone_number = apply_field_update_policy($5, users.phone_number, EXCLUDED.phone_number),
+          phone_number_raw = CASE
+            WHEN apply_field_update_policy($5, users.phone_number, EXCLUDED.phone_number) IS NOT DISTINCT FROM EXCLUDED.phone_number THEN EXCLUDED.phone_number_raw
+            ELSE users.phone_number_raw
+          END,
+          source_modified_at = COALESCE(EXCLUDED.source_modified_at, users.source_modified_at),
+          attributes = apply_attribute_strategy($6, users.attributes, EXCLUDED.attributes),
+          last_import_job_id = EXCLUDED.last_import_job_id,
+          last_import_position = EXCLUDED.last_import_position,
           updated_at = NOW()
-    RETURNING (xmax = 0) AS inserted
+      WHERE (
+          users.source_modified_at IS NULL
+          OR EXCLUDED.source_modified_at IS NULL
+          OR EXCLUDED.source_modified_at > users.source_modified_at
+        )
+        AND (NOT $7 OR users.last_import_job_id IS DISTINCT FROM $1 OR users.last_import_position <= EXCLUDED.last_import_position)
+    RETURNING id, (xmax = 0) AS inserted
 )
-SELECT inserted FROM upserted
-`, jobID, uuidRegex)
+SELECT
+  s.row_index,
+  u.inserted,
+  $7 AND COALESCE(x.last_import_job_id = $1 AND x.last_import_position > s.source_position, FALSE),
+  CASE WHEN u.id IS NULL THEN ARRAY(
+    SELECT r.row_index FROM stg_users r WHERE r.job_id = $1 AND r.user_id IS NULL AND r.external_id = s.external_id
+  ) END
+FROM staged s
+LEFT JOIN upserted u ON u.id = s.ext_uuid
+LEFT JOIN users x ON x.id = s.ext_uuid
+WHERE s.ext_uuid IS NOT NULL
+`, jobID, uuidRegex, string(policies.Name), string(policies.Email), string(policies.PhoneNumber), string(attributes), ordered)
 	if err != nil {
-		return 0, 0, fmt.Errorf("upsert users by external_id: %w", err)
+		return upsertOutcome{}, fmt.Errorf("upsert users by external_id: %w", err)
 	}
 	defer rows.Close()
 
-	return countInsertedUpdated(rows)
+	return collectUpsertOutcome(rows)
 }
 
-func upsertUsersByEmail(ctx context.Context, tx pgx.Tx, jobID string) (int64, int64, error) {
+func upsertUsersByEmail(ctx context.Context, tx pgx.Tx, jobID string, policies domain.FieldUpdatePolicies, attributes domain.AttributeStrategy, uuidV7, ordered bool) (upsertOutcome, error) {
 	rows, err := tx.Query(ctx, `
 WITH staged AS (
-    SELECT DISTINCT ON (email)
+    SELECT DISTINCT ON (email_key)
+      row_index,
       name,
       email,
-      phone_number
+      email_key,
+      phone_number,
+      phone_number_raw,
+      source_modified_at,
+      attributes,
+      source_position
     FROM stg_users
-    WHERE job_id = $1 AND (external_id IS NULL OR external_id = '' OR NOT (external_id ~* $2))
-    ORDER BY email, row_index DESC
+    WHERE job_id = $1 AND user_id IS NULL AND (external_id IS NULL OR external_id = '' OR NOT (external_id ~* $2))
+    ORDER BY email_key, source_modified_at DESC NULLS LAST, row_index DESC
 ), upserted AS (
-    INSERT INTO users (name, email, phone_number, created_at, updated_at)
-    SELECT name, email, phone_number, NOW(), NOW()
+    INSERT INTO users (id, name, email, email_key, phone_number, phone_number_raw, source_modified_at, attributes, last_import_job_id, last_import_position, created_import_job_id, created_at, updated_at)
+    SELECT
+      CASE WHEN $5 THEN uuid_generate_v7() ELSE uuid_generate_v4() END,
+      name,
+      email,
+      email_key,
+      phone_number,
+      phone_number_raw,
+      source_modified_at,
+      COALESCE(attributes, '{}'::jsonb),
+      $1,
+      source_position,
+      $1,
+      NOW(),
+      NOW()
     FROM staged
-    ON CONFLICT (email) DO UPDATE
-      SET name = EXCLUDED.name,
-          phone_number = EXCLUDED.phone_number,
+    ON CONFLICT (email_key) DO UPDATE
+      SET name = apply_field_update_policy($3, users.name, EXCLUDED.name),
+          phone_number = apply_field_update_policy($4, users.phone_number, EXCLUDED.phone_number),
+          phone_number_raw = CASE
+            WHEN apply_field_update_policy($4, users.phone_number, EXCLUDED.phone_number) IS NOT DISTINCT FROM EXCLUDED.phone_number THEN EXCLUDED.phone_number_raw
+            ELSE users.phone_number_raw
+          END,
+          source_modified_at = COALESCE(EXCLUDED.source_modified_at, users.source_modified_at),
+          attributes = apply_attribute_strategy($6, users.attributes, EXCLUDED.attributes),
+          last_import_job_id = EXCLUDED.last_import_job_id,
+          last_import_position = EXCLUDED.last_import_position,
           updated_at = NOW()
-    RETURNING (xmax = 0) AS inserted
+      WHERE (
+          users.source_modified_at IS NULL
+          OR EXCLUDED.source_modified_at IS NULL
+          OR EXCLUDED.source_modified_at > users.source_modified_at
+        )
+        AND (NOT $7 OR users.last_import_job_id IS DISTINCT FROM $1 OR users.last_import_position <= EXCLUDED.last_import_position)
+    RETURNING email_key, (xmax = 0) AS inserted
 )
-SELECT inserted FROM upserted
-`, jobID, uuidRegex)
+SELECT
+  s.row_index,
+  u.inserted,
+  $7 AND COALESCE(x.last_import_job_id = $1 AND x.last_import_position > s.source_position, FALSE),
+  CASE WHEN u.email_key IS NULL THEN ARRAY(
+    SELECT r.row_index
+    FROM stg_users r
+    WHERE r.job_id = $1
+      AND r.user_id IS NULL
+      AND (r.external_id IS NULL OR r.external_id = '' OR NOT (r.external_id ~* $2))
+      AND r.email_key IS NOT DISTINCT FROM s.email_key
+  ) END
+FROM staged s
+LEFT JOIN upserted u ON u.email_key = s.email_key
+LEFT JOIN users x ON x.email_key = s.email_key
+`, jobID, uuidRegex, string(policies.Name), string(policies.PhoneNumber), uuidV7, string(attributes), ordered)
 	if err != nil {
-		return 0, 0, fmt.Errorf("upsert users by email: %w", err)
+		return upsertOutcome{}, fmt.Errorf("upsert users by email: %w", err)
 	}
 	defer rows.Close()
 
-	return countInsertedUpdated(rows)
+	return collectUpsertOutcome(rows)
+}
+
+func discardStagedRows(ctx context.Context, tx pgx.Tx, jobID string, rowIndexes []int64) error {
+	if len(rowIndexes) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM stg_addresses WHERE job_id = $1 AND row_index = ANY($2)", jobID, rowIndexes); err != nil {
+		return fmt.Errorf("discard stg_addresses rows: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM stg_users WHERE job_id = $1 AND row_index = ANY($2)", jobID, rowIndexes); err != nil {
+		return fmt.Errorf("discard stg_users rows: %w", err)
+	}
+	return nil
+}
+
+func resolveStagedUsers(ctx context.Context, tx pgx.Tx, jobID string) error {
+	if _, err := tx.Exec(ctx, `
+UPDATE stg_users s
+SET user_id = u.id
+FROM users u
+WHERE s.job_id = $1
+  AND s.user_id IS NULL
+  AND s.external_id ~* $2
+  AND u.id = s.external_id::uuid
+`, jobID, uuidRegex); err != nil {
+		return fmt.Errorf("resolve staged users by external_id: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+UPDATE stg_users s
+SET user_id = u.id
+FROM users u
+WHERE s.job_id = $1
+  AND s.user_id IS NULL
+  AND (s.external_id IS NULL OR s.external_id = '' OR NOT (s.external_id ~* $2))
+  AND u.email_key = s.email_key
+`, jobID, uuidRegex); err != nil {
+		return fmt.Errorf("resolve staged users by email: %w", err)
+	}
+
+	return nil
+}
+
+func recordExternalIDs(ctx context.Context, tx pgx.Tx, jobID, source string) error {
+	if _, err := tx.Exec(ctx, `
+INSERT INTO user_external_ids (source, external_id, user_id, created_at)
+SELECT DISTINCT ON (external_id) $2, external_id, user_id, NOW()
+FROM stg_users
+WHERE job_id = $1 AND external_id IS NOT NULL AND external_id <> '' AND user_id IS NOT NULL
+ORDER BY external_id, row_index DESC
+ON CONFLICT (source, external_id) DO NOTHING
+`, jobID, source); err != nil {
+		return fmt.Errorf("record external ids: %w", err)
+	}
+	return nil
+}
+
+func applyAddresses(ctx context.Context, tx pgx.Tx, jobID string, strategy domain.AddressStrategy) error {
+	var err error
+	switch strategy {
+	case domain.AddressStrategyReplace, "":
+		err = replaceAddresses(ctx, tx, jobID)
+	case domain.AddressStrategyAppend:
+		err = appendAddresses(ctx, tx, jobID)
+	case domain.AddressStrategyMerge:
+		err = mergeAddresses(ctx, tx, jobID)
+	case domain.AddressStrategyIgnore:
+		return nil
+	default:
+		return fmt.Errorf("apply addresses: %w: %q", domain.ErrInvalidAddressStrategy, strategy)
+	}
+	if err != nil {
+		return err
+	}
+	if err := applyIncomingDefaultAddresses(ctx, tx, jobID); err != nil {
+		return err
+	}
+	if strategy == domain.AddressStrategyReplace || strategy == "" {
+		return nil
+	}
+	return promoteDefaultAddresses(ctx, tx, jobID)
 }
 
 func replaceAddresses(ctx context.Context, tx pgx.Tx, jobID string) error {
 	if _, err := tx.Exec(ctx, `
 WITH affected_users AS (
-    SELECT DISTINCT u.id
-    FROM users u
-    JOIN stg_users s
-      ON s.job_id = $1
-     AND (
-       (CASE WHEN s.external_id ~* $2 THEN s.external_id::uuid ELSE NULL END) = u.id
-       OR ((s.external_id IS NULL OR s.external_id = '' OR NOT (s.external_id ~* $2)) AND u.email = s.email)
-     )
+    SELECT DISTINCT user_id AS id
+    FROM stg_users
+    WHERE job_id = $1 AND user_id IS NOT NULL
 )
 DELETE FROM addresses a
 USING affected_users af
 WHERE a.user_id = af.id
-`, jobID, uuidRegex); err != nil {
+`, jobID); err != nil {
 		return fmt.Errorf("delete existing addresses: %w", err)
 	}
 
 	if _, err := tx.Exec(ctx, `
-INSERT INTO addresses (user_id, street, city, state, zip_code, country, created_at, updated_at)
+INSERT INTO addresses (user_id, street, city, state, zip_code, country, type, created_at, updated_at)
 SELECT
-  u.id,
+  s.user_id,
   a.street,
   a.city,
   a.state,
   a.zip_code,
   a.country,
+  a.type,
   NOW(),
   NOW()
 FROM stg_addresses a
-JOIN users u
-  ON (
-    (CASE WHEN a.user_external_id ~* $2 THEN a.user_external_id::uuid ELSE NULL END) = u.id
-    OR ((a.user_external_id IS NULL OR a.user_external_id = '' OR NOT (a.user_external_id ~* $2)) AND u.email = a.user_email)
-  )
-WHERE a.job_id = $1
-`, jobID, uuidRegex); err != nil {
+JOIN stg_users s ON s.job_id = a.job_id AND s.row_index = a.row_index
+WHERE a.job_id = $1 AND s.user_id IS NOT NULL
+`, jobID); err != nil {
 		return fmt.Errorf("insert replacement addresses: %w", err)
 	}
 
 	return nil
 }
 
-func countInsertedUpdated(rows pgx.Rows) (int64, int64, error) {
-	var imported int64
-	var updated int64
+func appendAddresses(ctx context.Context, tx pgx.Tx, jobID string) error {
+	if _, err := tx.Exec(ctx, `
+WITH staged AS (
+    SELECT DISTINCT ON (
+      u.user_id,
+      normalize_address_part(a.street),
+      normalize_address_part(a.city),
+      normalize_address_part(a.state),
+      normalize_address_part(a.zip_code),
+      normalize_address_part(a.country)
+    )
+      u.user_id,
+      a.street,
+      a.city,
+      a.state,
+      a.zip_code,
+      a.country,
+      a.type
+    FROM stg_addresses a
+    JOIN stg_users u ON u.job_id = a.job_id AND u.row_index = a.row_index
+    WHERE a.job_id = $1 AND u.user_id IS NOT NULL
+    ORDER BY
+      u.user_id,
+      normalize_address_part(a.street),
+      normalize_address_part(a.city),
+      normalize_address_part(a.state),
+      normalize_address_part(a.zip_code),
+      normalize_address_part(a.country),
+      a.row_index
+)
+INSERT INTO addresses (user_id, street, city, state, zip_code, country, type, created_at, updated_at)
+SELECT s.user_id, s.street, s.city, s.state, s.zip_code, s.country, s.type, NOW(), NOW()
+FROM staged s
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM addresses e
+    WHERE e.user_id = s.user_id
+      AND normalize_address_part(e.street) = normalize_address_part(s.street)
+      AND normalize_address_part(e.city) = normalize_address_part(s.city)
+      AND normalize_address_part(e.state) = normalize_address_part(s.state)
+      AND normalize_address_part(e.zip_code) = normalize_address_part(s.zip_code)
+      AND normalize_address_part(e.country) = normalize_address_part(s.country)
+)
+`, jobID); err != nil {
+		return fmt.Errorf("append addresses: %w", err)
+	}
+
+	return nil
+}
+
+func mergeAddresses(ctx context.Context, tx pgx.Tx, jobID string) error {
+	if _, err := tx.Exec(ctx, `
+WITH staged AS (
+    SELECT DISTINCT ON (
+      u.user_id,
+      normalize_address_part(a.street),
+      normalize_address_part(a.city),
+      normalize_address_part(a.zip_code)
+    )
+      u.user_id,
+      a.street,
+      a.city,
+      a.state,
+      a.zip_code,
+      a.country,
+      a.type
+    FROM stg_addresses a
+    JOIN stg_users u ON u.job_id = a.job_id AND u.row_index = a.row_index
+    WHERE a.job_id = $1 AND u.user_id IS NOT NULL
+    ORDER BY
+      u.user_id,
+      normalize_address_part(a.street),
+      normalize_address_part(a.city),
+      normalize_address_part(a.zip_code),
+      a.row_index DESC
+), updated AS (
+    UPDATE addresses e
+    SET
+      street = s.street,
+      city = s.city,
+      state = s.state,
+      zip_code = s.zip_code,
+      country = s.country,
+      type = s.type,
+      is_default = e.is_default AND e.type = s.type,
+      updated_at = NOW()
+    FROM staged s
+    WHERE e.user_id = s.user_id
+      AND normalize_address_part(e.street) = normalize_address_part(s.street)
+      AND normalize_address_part(e.city) = normalize_address_part(s.city)
+      AND normalize_address_part(e.zip_code) = normalize_address_part(s.zip_code)
+    RETURNING e.id
+)
+INSERT INTO addresses (user_id, street, city, state, zip_code, country, type, created_at, updated_at)
+SELECT s.user_id, s.street, s.city, s.state, s.zip_code, s.country, s.type, NOW(), NOW()
+FROM staged s
+WHERE NOT EXISTS (
+    SELECT 1
+    FROM addresses e
+    WHERE e.user_id = s.user_id
+      AND normalize_address_part(e.street) = normalize_address_part(s.street)
+      AND normalize_address_part(e.city) = normalize_address_part(s.city)
+      AND normalize_address_part(e.zip_code) = normalize_address_part(s.zip_code)
+)
+`, jobID); err != nil {
+		return fmt.Errorf("merge addresses: %w", err)
+	}
+
+	return nil
+}
+
+// incomingDefaultAddressTargets picks, per user and address type, the stored
+// address matching the staged default. Explicit defaults win over promoted
+// ones, which only apply while the user has no default of that type.
+const incomingDefaultAddressTargets = `
+WITH incoming AS (
+    SELECT DISTINCT ON (u.user_id, a.type)
+      u.user_id,
+      a.street,
+      a.city,
+      a.state,
+      a.zip_code,
+      a.country,
+      a.type
+    FROM stg_addresses a
+    JOIN stg_users u ON u.job_id = a.job_id AND u.row_index = a.row_index
+    WHERE a.job_id = $1 AND a.is_default AND u.user_id IS NOT NULL
+      AND (
+        NOT a.default_promoted
+        OR NOT EXISTS (SELECT 1 FROM addresses d WHERE d.user_id = u.user_id AND d.type = a.type AND d.is_default)
+      )
+    ORDER BY u.user_id, a.type, a.default_promoted, a.row_index DESC
+), target AS (
+    SELECT DISTINCT ON (i.user_id, i.type) i.user_id, i.type, e.id
+    FROM incoming i
+    JOIN addresses e ON e.user_id = i.user_id
+      AND e.type = i.type
+      AND normalize_address_part(e.street) = normalize_address_part(i.street)
+      AND normalize_address_part(e.city) = normalize_address_part(i.city)
+      AND normalize_address_part(e.state) = normalize_address_part(i.state)
+      AND normalize_address_part(e.zip_code) = normalize_address_part(i.zip_code)
+      AND normalize_address_part(e.country) = normalize_address_part(i.country)
+    ORDER BY i.user_id, i.type, e.id DESC
+)
+`
+
+func applyIncomingDefaultAddresses(ctx context.Context, tx pgx.Tx, jobID string) error {
+	if _, err := tx.Exec(ctx, incomingDefaultAddressTargets+`
+UPDATE addresses a
+SET is_default = FALSE, updated_at = NOW()
+FROM target t
+WHERE a.user_id = t.user_id AND a.type = t.type AND a.is_default AND a.id <> t.id
+`, jobID); err != nil {
+		return fmt.Errorf("clear previous default addresses: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, incomingDefaultAddressTargets+`
+UPDATE addresses a
+SET is_default = TRUE, updated_at = NOW()
+FROM target t
+WHERE a.id = t.id AND NOT a.is_default
+`, jobID); err != nil {
+		return fmt.Errorf("set default addresses: %w", err)
+	}
+
+	return nil
+}
+
+// promoteDefaultAddresses gives a default to the address types of appended or
+// merged users that lost theirs, such as when a merge changed the type of the
+// stored default. Rows themselves always carry one default per type.
+func promoteDefaultAddresses(ctx context.Context, tx pgx.Tx, jobID string) error {
+	if _, err := tx.Exec(ctx, `
+UPDATE addresses a
+SET is_default = TRUE, updated_at = NOW()
+FROM (
+    SELECT DISTINCT ON (e.user_id, e.type) e.id
+    FROM addresses e
+    WHERE e.user_id IN (
+        SELECT user_id FROM stg_users WHERE job_id = $1 AND user_id IS NOT NULL
+    )
+      AND NOT EXISTS (
+        SELECT 1 FROM addresses d WHERE d.user_id = e.user_id AND d.type = e.type AND d.is_default
+      )
+    ORDER BY e.user_id, e.type, e.id
+) first_address
+WHERE a.id = first_address.id
+`, jobID); err != nil {
+		return fmt.Errorf("promote default addresses: %w", err)
+	}
+	return nil
+}
+
+func collectUpsertOutcome(rows pgx.Rows) (upsertOutcome, error) {
+	var outcome upsertOutcome
 
 	for rows.Next() {
-		var inserted bool
-		if err := rows.Scan(&inserted); err != nil {
-			return 0, 0, err
+		var rowIndex int64
+		var inserted *bool
+		var superseded bool
+		var siblings []int64
+		if err := rows.Scan(&rowIndex, &inserted, &superseded, &siblings); err != nil {
+			return upsertOutcome{}, err
 		}
-		if inserted {
-			imported++
-		} else {
-			updated++
+		switch {
+		case inserted == nil && superseded:
+			outcome.superseded = append(outcome.superseded, rowIndex)
+			outcome.discarded = append(outcome.discarded, siblings...)
+		case inserted == nil:
+			outcome.stale = append(outcome.stale, rowIndex)
+			outcome.discarded = append(outcome.discarded, siblings...)
+		case *inserted:
+			outcome.imported++
+		default:
+			outcome.updated++
 		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return 0, 0, err
+		return upsertOutcome{}, err
 	}
 
-	return imported, updated, nil
+	return outcome, nil
 }
 
 func nullableText(value string) *string {
@@ -239,3 +1151,34 @@ func nullableText(value string) *string {
 	}
 	return &value
 }
+
+func nullableTime(value time.Time) *time.Time {
+	if value.IsZero() {
+		return nil
+	}
+	return &value
+}
+
+func emailKey(user domain.User) string {
+	if user.EmailKey != "" {
+		return user.EmailKey
+	}
+	if _, key, err := (domain.EmailNormalizer{}).Normalize(user.Email); err == nil {
+		return key
+	}
+	return strings.ToLower(strings.TrimSpace(user.Email))
+}
+
+func addressType(address domain.Address) string {
+	if address.Type == "" {
+		return string(domain.AddressTypeHome)
+	}
+	return string(address.Type)
+}
+
+func nullableAttributes(attributes map[string]any) any {
+	if len(attributes) == 0 {
+		return nil
+	}
+	return attributes
+}
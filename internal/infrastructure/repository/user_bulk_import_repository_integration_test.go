@@ -2,8 +2,11 @@ package repository_test
 
 import (
 	"context"
+	"encoding/json"
 	"os"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	domain "github.com/mohammadpnp/user-import/internal/domain/user"
@@ -12,7 +15,9 @@ import (
 	"gorm.io/gorm"
 )
 
-func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
+func setupBulkImportIntegration(t testing.TB) (*gorm.DB, *pgxpool.Pool) {
+	t.Helper()
+
 	dsn := os.Getenv("TEST_DATABASE_URL")
 	if dsn == "" {
 		t.Skip("TEST_DATABASE_URL is not set")
@@ -30,6 +35,7 @@ func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
       name VARCHAR(255) NOT NULL,
       email VARCHAR(320) NOT NULL UNIQUE,
       phone_number VARCHAR(32) NOT NULL,
+      source_modified_at TIMESTAMPTZ,
       created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
     );
@@ -44,13 +50,47 @@ func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
       created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
     );
+    CREATE OR REPLACE FUNCTION normalize_address_part(value TEXT)
+    RETURNS TEXT
+    LANGUAGE SQL
+    IMMUTABLE
+    AS $$ SELECT lower(btrim(regexp_replace(value, '\s+', ' ', 'g'))) $$;
+    CREATE OR REPLACE FUNCTION apply_field_update_policy(policy TEXT, current_value TEXT, incoming_value TEXT)
+    RETURNS TEXT
+    LANGUAGE SQL
+    IMMUTABLE
+    AS $$
+      SELECT CASE policy
+        WHEN 'never' THEN current_value
+        WHEN 'fill_empty' THEN CASE WHEN current_value IS NULL OR btrim(current_value) = '' THEN incoming_value ELSE current_value END
+        WHEN 'if_present' THEN CASE WHEN incoming_value IS NULL OR btrim(incoming_value) = '' THEN current_value ELSE incoming_value END
+        ELSE incoming_value
+      END
+    $$;
+    CREATE OR REPLACE FUNCTION uuid_generate_v7()
+    RETURNS UUID
+    LANGUAGE plpgsql
+    VOLATILE
+    AS $$
+    DECLARE
+      uuid_bytes BYTEA;
+    BEGIN
+      uuid_bytes := substring(int8send(floor(extract(epoch FROM clock_timestamp()) * 1000)::BIGINT) FROM 3)
+        || substring(uuid_send(gen_random_uuid()) FROM 7);
+      uuid_bytes := set_byte(uuid_bytes, 6, (b'0111' || get_byte(uuid_bytes, 6)::BIT(4))::BIT(8)::INT);
+      uuid_bytes := set_byte(uuid_bytes, 8, (b'10' || get_byte(uuid_bytes, 8)::BIT(6))::BIT(8)::INT);
+      RETURN encode(uuid_bytes, 'hex')::UUID;
+    END
+    $$;
     CREATE UNLOGGED TABLE IF NOT EXISTS stg_users (
       job_id UUID NOT NULL,
       row_index BIGINT NOT NULL,
       external_id TEXT,
       name TEXT NOT NULL,
       email TEXT NOT NULL,
-      phone_number TEXT NOT NULL
+      phone_number TEXT NOT NULL,
+      source_modified_at TIMESTAMPTZ,
+      user_id UUID
     );
     CREATE UNLOGGED TABLE IF NOT EXISTS stg_addresses (
       job_id UUID NOT NULL,
@@ -63,13 +103,62 @@ func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
       zip_code TEXT NOT NULL,
       country TEXT NOT NULL
     );
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS source_modified_at TIMESTAMPTZ;
+    ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS source_modified_at TIMESTAMPTZ;
+    ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS user_id UUID;
+    CREATE TABLE IF NOT EXISTS user_external_ids (
+      source TEXT NOT NULL,
+      external_id TEXT NOT NULL,
+      user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+      PRIMARY KEY (source, external_id)
+    );
+    ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS email_key VARCHAR(320);
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number_raw TEXT;
+    ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS email_key TEXT;
+    ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS phone_number_raw TEXT;
+    CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_key ON users (email_key);
+    ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+    CREATE TABLE IF NOT EXISTS user_email_key_collisions (
+      email_key VARCHAR(320) PRIMARY KEY,
+      user_ids UUID[] NOT NULL,
+      emails TEXT[] NOT NULL,
+      detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    );
+    ALTER TABLE addresses ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'home';
+    ALTER TABLE addresses ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
+    DROP INDEX IF EXISTS idx_addresses_user_default;
+    CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_user_type_default ON addresses (user_id, type) WHERE is_default;
+    ALTER TABLE stg_addresses ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'home';
+    ALTER TABLE stg_addresses ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
+    ALTER TABLE stg_addresses ADD COLUMN IF NOT EXISTS default_promoted BOOLEAN NOT NULL DEFAULT FALSE;
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
+    ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS attributes JSONB;
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS last_import_job_id UUID;
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS last_import_position BIGINT;
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS created_import_job_id UUID;
+    ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS source_position BIGINT NOT NULL DEFAULT 0;
+    ALTER TABLE stg_users ALTER COLUMN email DROP NOT NULL;
+    CREATE OR REPLACE FUNCTION apply_attribute_strategy(strategy TEXT, current_value JSONB, incoming_value JSONB)
+    RETURNS JSONB
+    LANGUAGE SQL
+    IMMUTABLE
+    AS $$
+      SELECT CASE strategy
+        WHEN 'replace' THEN COALESCE(incoming_value, '{}'::jsonb)
+        ELSE COALESCE(current_value, '{}'::jsonb) || COALESCE(incoming_value, '{}'::jsonb)
+      END
+    $$;
     `
 	if err := gdb.Exec(schemaSQL).Error; err != nil {
 		t.Fatalf("failed schema setup: %v", err)
 	}
 	cleanupSQL := `
+    DELETE FROM user_external_ids;
     DELETE FROM addresses;
     DELETE FROM users;
+    DELETE FROM user_email_key_collisions;
     DELETE FROM stg_addresses;
     DELETE FROM stg_users;
     `
@@ -81,9 +170,15 @@ func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
 	if err != nil {
 		t.Fatalf("failed to create pgx pool: %v", err)
 	}
-	defer pool.Close()
+	t.Cleanup(pool.Close)
+
+	return gdb, pool
+}
 
-	repo := repository.NewUserBulkImportRepository(pool)
+func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
 
 	users := []domain.User{{
 		ID:          "f7bc5d17-e7b2-49a1-9fd2-061b58f44f85",
@@ -99,7 +194,7 @@ func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
 		}},
 	}}
 
-	result, err := repo.ImportChunk(context.Background(), "4955eb4d-c7f2-42f6-80ca-33838ce37c31", users)
+	result, err := repo.ImportChunk(context.Background(), "4955eb4d-c7f2-42f6-80ca-33838ce37c31", domain.ImportOptions{}, users)
 	if err != nil {
 		t.Fatalf("import chunk failed: %v", err)
 	}
@@ -115,7 +210,7 @@ func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
 		ZipCode: "78702",
 		Country: "USA",
 	}}
-	result, err = repo.ImportChunk(context.Background(), "26a700f4-6765-4dce-b1a7-3a18f2fd4f56", users)
+	result, err = repo.ImportChunk(context.Background(), "26a700f4-6765-4dce-b1a7-3a18f2fd4f56", domain.ImportOptions{}, users)
 	if err != nil {
 		t.Fatalf("import chunk update failed: %v", err)
 	}
@@ -131,3 +226,721 @@ func TestUserBulkImportRepositoryImportChunkIntegration(t *testing.T) {
 		t.Fatalf("expected 1 address after replacement, got %d", addressCount)
 	}
 }
+
+func TestUserBulkImportRepositoryAddressStrategiesIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	users := []domain.User{{
+		ID:          "0b1f7c3e-5d2a-4c4e-9a51-3f0c2b8d7e61",
+		Name:        "Bob",
+		Email:       "bob@example.com",
+		PhoneNumber: "3333333333",
+		Addresses: []domain.Address{{
+			Street:  "1 Main",
+			City:    "Austin",
+			State:   "TX",
+			ZipCode: "78701",
+			Country: "USA",
+		}},
+	}}
+
+	if _, err := repo.ImportChunk(context.Background(), "5b0c7a0e-0a9e-4f0e-8d0b-6c1c1f5e2a01", domain.ImportOptions{}, users); err != nil {
+		t.Fatalf("seed import failed: %v", err)
+	}
+
+	var originalID int64
+	if err := gdb.Raw("SELECT id FROM addresses WHERE user_id = ?", users[0].ID).Scan(&originalID).Error; err != nil {
+		t.Fatalf("select address id failed: %v", err)
+	}
+
+	users[0].Addresses = []domain.Address{
+		{Street: " 1  MAIN ", City: "austin", State: "Texas", ZipCode: "78701", Country: "United States"},
+		{Street: "9 Side", City: "Austin", State: "TX", ZipCode: "78703", Country: "USA"},
+	}
+	if _, err := repo.ImportChunk(context.Background(), "5b0c7a0e-0a9e-4f0e-8d0b-6c1c1f5e2a02", domain.ImportOptions{AddressStrategy: domain.AddressStrategyMerge}, users); err != nil {
+		t.Fatalf("merge import failed: %v", err)
+	}
+
+	var mergedState string
+	if err := gdb.Raw("SELECT state FROM addresses WHERE id = ?", originalID).Scan(&mergedState).Error; err != nil {
+		t.Fatalf("select merged address failed: %v", err)
+	}
+	if mergedState != "Texas" {
+		t.Fatalf("expected address %d to be updated in place, got state %q", originalID, mergedState)
+	}
+
+	var addressCount int64
+	if err := gdb.Raw("SELECT COUNT(*) FROM addresses WHERE user_id = ?", users[0].ID).Scan(&addressCount).Error; err != nil {
+		t.Fatalf("count addresses failed: %v", err)
+	}
+	if addressCount != 2 {
+		t.Fatalf("expected 2 addresses after merge, got %d", addressCount)
+	}
+
+	users[0].Addresses = []domain.Address{{Street: "9 side", City: "AUSTIN", State: "tx", ZipCode: "78703", Country: "usa"}}
+	if _, err := repo.ImportChunk(context.Background(), "5b0c7a0e-0a9e-4f0e-8d0b-6c1c1f5e2a03", domain.ImportOptions{AddressStrategy: domain.AddressStrategyAppend}, users); err != nil {
+		t.Fatalf("append import failed: %v", err)
+	}
+	users[0].Addresses = nil
+	if _, err := repo.ImportChunk(context.Background(), "5b0c7a0e-0a9e-4f0e-8d0b-6c1c1f5e2a04", domain.ImportOptions{AddressStrategy: domain.AddressStrategyIgnore}, users); err != nil {
+		t.Fatalf("ignore import failed: %v", err)
+	}
+
+	if err := gdb.Raw("SELECT COUNT(*) FROM addresses WHERE user_id = ?", users[0].ID).Scan(&addressCount).Error; err != nil {
+		t.Fatalf("count addresses failed: %v", err)
+	}
+	if addressCount != 2 {
+		t.Fatalf("expected append and ignore to keep 2 addresses, got %d", addressCount)
+	}
+}
+
+func TestUserBulkImportRepositoryFieldUpdatePoliciesIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	users := []domain.User{{
+		ID:          "6a2d4f1c-8b3e-4d5a-9c7f-1e2b3c4d5e6f",
+		Name:        "Carol",
+		Email:       "carol@example.com",
+		PhoneNumber: "4444444444",
+	}}
+	if _, err := repo.ImportChunk(context.Background(), "7c1a2b3d-4e5f-4a6b-8c7d-9e0f1a2b3c01", domain.ImportOptions{}, users); err != nil {
+		t.Fatalf("seed import failed: %v", err)
+	}
+
+	users[0].Name = "Caroline"
+	users[0].PhoneNumber = ""
+	options := domain.ImportOptions{UpdatePolicies: domain.FieldUpdatePolicies{
+		Name:        domain.FieldUpdateNever,
+		PhoneNumber: domain.FieldUpdateIfPresent,
+	}}
+	result, err := repo.ImportChunk(context.Background(), "7c1a2b3d-4e5f-4a6b-8c7d-9e0f1a2b3c02", options, users)
+	if err != nil {
+		t.Fatalf("policy import failed: %v", err)
+	}
+	if result.UpdatedCount != 1 {
+		t.Fatalf("expected updated=1, got %d", result.UpdatedCount)
+	}
+
+	var got struct {
+		Name        string
+		PhoneNumber string
+	}
+	if err := gdb.Raw("SELECT name, phone_number FROM users WHERE id = ?", users[0].ID).Scan(&got).Error; err != nil {
+		t.Fatalf("select user failed: %v", err)
+	}
+	if got.Name != "Carol" {
+		t.Fatalf("expected name to be kept, got %q", got.Name)
+	}
+	if got.PhoneNumber != "4444444444" {
+		t.Fatalf("expected blank phone to be ignored, got %q", got.PhoneNumber)
+	}
+}
+
+func TestUserBulkImportRepositoryLastWriteWinsIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	newer := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	users := []domain.User{{
+		ID:               "8d3c2b1a-0f9e-4d8c-b7a6-5e4d3c2b1a01",
+		Name:             "Dana",
+		Email:            "dana@example.com",
+		PhoneNumber:      "5555555555",
+		SourceModifiedAt: newer,
+		Addresses: []domain.Address{{
+			Street:  "1 Main",
+			City:    "Austin",
+			State:   "TX",
+			ZipCode: "78701",
+			Country: "USA",
+		}},
+	}}
+	if _, err := repo.ImportChunk(context.Background(), "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c01", domain.ImportOptions{}, users); err != nil {
+		t.Fatalf("seed import failed: %v", err)
+	}
+
+	users[0].Name = "Stale Dana"
+	users[0].SourceModifiedAt = newer.Add(-time.Hour)
+	users[0].Addresses = nil
+	// An undated duplicate loses the deduplication to the stale row and must be
+	// discarded with it rather than rewriting the user's addresses.
+	undated := users[0]
+	undated.SourceModifiedAt = time.Time{}
+	undated.Addresses = []domain.Address{{Street: "2 Side", City: "Austin", State: "TX", ZipCode: "78702", Country: "USA"}}
+	users = append(users, undated)
+	result, err := repo.ImportChunk(context.Background(), "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c02", domain.ImportOptions{}, users)
+	if err != nil {
+		t.Fatalf("stale import failed: %v", err)
+	}
+	if result.SkippedCount != 1 || result.UpdatedCount != 0 {
+		t.Fatalf("expected stale row to be skipped, got %+v", result)
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0].Reason != domain.SkipReasonStaleSourceTimestamp {
+		t.Fatalf("unexpected skipped rows: %+v", result.Skipped)
+	}
+
+	var name string
+	if err := gdb.Raw("SELECT name FROM users WHERE id = ?", users[0].ID).Scan(&name).Error; err != nil {
+		t.Fatalf("select user failed: %v", err)
+	}
+	if name != "Dana" {
+		t.Fatalf("expected stale row to be ignored, got name %q", name)
+	}
+
+	var streets []string
+	if err := gdb.Raw("SELECT street FROM addresses WHERE user_id = ?", users[0].ID).Scan(&streets).Error; err != nil {
+		t.Fatalf("select addresses failed: %v", err)
+	}
+	if len(streets) != 1 || streets[0] != "1 Main" {
+		t.Fatalf("expected stale rows to keep addresses, got %v", streets)
+	}
+}
+
+func TestUserBulkImportRepositoryConcurrentChunksKeepSourceOrderIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	const jobID = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c11"
+	options := domain.ImportOptions{ChunkConcurrency: 2}
+
+	later := []domain.User{{Name: "Newer Erin", Email: "erin@example.com", PhoneNumber: "5555555555", SourcePosition: 200}}
+	earlier := []domain.User{{Name: "Older Erin", Email: "erin@example.com", PhoneNumber: "5555555555", SourcePosition: 100}}
+
+	// The chunk later in the source commits first.
+	if _, err := repo.ImportChunk(context.Background(), jobID, options, later); err != nil {
+		t.Fatalf("later chunk failed: %v", err)
+	}
+	result, err := repo.ImportChunk(context.Background(), jobID, options, earlier)
+	if err != nil {
+		t.Fatalf("earlier chunk failed: %v", err)
+	}
+	if result.UpdatedCount != 0 || len(result.Skipped) != 1 || result.Skipped[0].Reason != domain.SkipReasonSupersededInSource {
+		t.Fatalf("expected earlier row to be superseded, got %+v", result)
+	}
+
+	var name string
+	if err := gdb.Raw("SELECT name FROM users WHERE email = ?", "erin@example.com").Scan(&name).Error; err != nil {
+		t.Fatalf("select user failed: %v", err)
+	}
+	if name != "Newer Erin" {
+		t.Fatalf("expected the row later in the source to win, got name %q", name)
+	}
+}
+
+func TestUserBulkImportRepositoryOrderedJobConflictPolicyIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	owner := domain.User{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2d01", Name: "Ivan", Email: "ivan@example.com", PhoneNumber: "2020202020"}
+	if _, err := repo.ImportChunk(context.Background(), "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c21", domain.ImportOptions{}, []domain.User{owner}); err != nil {
+		t.Fatalf("seed import failed: %v", err)
+	}
+
+	const jobID = "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c22"
+	options := domain.ImportOptions{Partitions: 2, ConflictPolicy: domain.IdentityConflictReject}
+
+	// The job updates the pre-existing owner, then a later row claims its email.
+	owner.Name = "Ivan Updated"
+	owner.SourcePosition = 100
+	if _, err := repo.ImportChunk(context.Background(), jobID, options, []domain.User{owner}); err != nil {
+		t.Fatalf("owner chunk failed: %v", err)
+	}
+	claimant := domain.User{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2d02", Name: "Heidi", Email: "ivan@example.com", PhoneNumber: "1010101010", SourcePosition: 200}
+	result, err := repo.ImportChunk(context.Background(), jobID, options, []domain.User{claimant})
+	if err != nil {
+		t.Fatalf("claimant chunk failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0].Resolution != domain.IdentityConflictReject || result.FailedCount != 1 {
+		t.Fatalf("expected the job policy for an owner that predates the job, got %+v", result)
+	}
+
+	var email string
+	if err := gdb.Raw("SELECT email FROM users WHERE id = ?", owner.ID).Scan(&email).Error; err != nil {
+		t.Fatalf("select email failed: %v", err)
+	}
+	if email != "ivan@example.com" {
+		t.Fatalf("expected owner to keep its email, got %q", email)
+	}
+}
+
+func TestUserBulkImportRepositoryExternalIDMappingIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	options := domain.ImportOptions{Source: "hr"}
+
+	users := []domain.User{{
+		ID:          "E-1001",
+		Name:        "Erin",
+		Email:       "erin@example.com",
+		PhoneNumber: "6666666666",
+	}}
+	result, err := repo.ImportChunk(context.Background(), "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c01", options, users)
+	if err != nil {
+		t.Fatalf("seed import failed: %v", err)
+	}
+	if result.ImportedCount != 1 {
+		t.Fatalf("expected imported=1, got %d", result.ImportedCount)
+	}
+
+	var userID string
+	if err := gdb.Raw("SELECT user_id FROM user_external_ids WHERE source = ? AND external_id = ?", "hr", "E-1001").Scan(&userID).Error; err != nil {
+		t.Fatalf("select mapping failed: %v", err)
+	}
+	if userID == "" {
+		t.Fatal("expected external id mapping to be created")
+	}
+
+	users[0].Email = "erin.new@example.com"
+	result, err = repo.ImportChunk(context.Background(), "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c02", options, users)
+	if err != nil {
+		t.Fatalf("email change import failed: %v", err)
+	}
+	if result.UpdatedCount != 1 || result.ImportedCount != 0 {
+		t.Fatalf("expected mapped user to be updated, got %+v", result)
+	}
+
+	var userCount int64
+	if err := gdb.Raw("SELECT COUNT(*) FROM users WHERE email IN (?, ?)", "erin@example.com", "erin.new@example.com").Scan(&userCount).Error; err != nil {
+		t.Fatalf("count users failed: %v", err)
+	}
+	if userCount != 1 {
+		t.Fatalf("expected email change to keep a single user, got %d", userCount)
+	}
+
+	// A new external id appearing twice in one chunk with different emails
+	// creates one user, with the email of its latest row.
+	duplicates := []domain.User{
+		{ID: "E-2002", Name: "Finn", Email: "finn@example.com", PhoneNumber: "7777777777"},
+		{ID: "E-2002", Name: "Finn", Email: "finn.new@example.com", PhoneNumber: "7777777777"},
+	}
+	result, err = repo.ImportChunk(context.Background(), "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c03", options, duplicates)
+	if err != nil {
+		t.Fatalf("duplicate external id import failed: %v", err)
+	}
+	if result.ImportedCount != 1 {
+		t.Fatalf("expected one user for the repeated external id, got %+v", result)
+	}
+
+	var finn struct {
+		Users  int64
+		Mapped string
+	}
+	if err := gdb.Raw(`
+SELECT
+  (SELECT COUNT(*) FROM users WHERE email IN ('finn@example.com', 'finn.new@example.com')) AS users,
+  (SELECT u.email FROM user_external_ids m JOIN users u ON u.id = m.user_id WHERE m.source = 'hr' AND m.external_id = 'E-2002') AS mapped
+`).Scan(&finn).Error; err != nil {
+		t.Fatalf("select repeated external id failed: %v", err)
+	}
+	if finn.Users != 1 || finn.Mapped != "finn.new@example.com" {
+		t.Fatalf("expected a single mapped user with the latest email, got %+v", finn)
+	}
+}
+
+func TestUserBulkImportRepositoryGeneratesUUIDv7Integration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{GenerateUUIDv7: true})
+
+	users := []domain.User{
+		{
+			ID:          "0190f2a4-7c3b-7d2e-9f1a-2b3c4d5e6f70",
+			Name:        "Frank",
+			Email:       "frank@example.com",
+			PhoneNumber: "7777777777",
+		},
+		{
+			Name:        "Grace",
+			Email:       "grace@example.com",
+			PhoneNumber: "8888888888",
+		},
+	}
+	result, err := repo.ImportChunk(context.Background(), "b2c3d4e5-f6a7-4b8c-9d0e-1f2a3b4c5d01", domain.ImportOptions{}, users)
+	if err != nil {
+		t.Fatalf("import chunk failed: %v", err)
+	}
+	if result.ImportedCount != 2 {
+		t.Fatalf("expected imported=2, got %d", result.ImportedCount)
+	}
+
+	var frankID string
+	if err := gdb.Raw("SELECT id::text FROM users WHERE email = ?", "frank@example.com").Scan(&frankID).Error; err != nil {
+		t.Fatalf("select user failed: %v", err)
+	}
+	if frankID != users[0].ID {
+		t.Fatalf("expected UUIDv7 external id to be kept, got %s", frankID)
+	}
+
+	var graceID string
+	if err := gdb.Raw("SELECT id::text FROM users WHERE email = ?", "grace@example.com").Scan(&graceID).Error; err != nil {
+		t.Fatalf("select user failed: %v", err)
+	}
+	if !domain.IsUUID(graceID) || graceID[14] != '7' {
+		t.Fatalf("expected generated UUIDv7, got %s", graceID)
+	}
+}
+
+func TestUserBulkImportRepositoryIdentityConflictsIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	seed := func(t *testing.T) {
+		t.Helper()
+		if err := gdb.Exec("DELETE FROM addresses; DELETE FROM users").Error; err != nil {
+			t.Fatalf("cleanup users failed: %v", err)
+		}
+		users := []domain.User{
+			{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2a01", Name: "Heidi", Email: "heidi@example.com", PhoneNumber: "1010101010"},
+			{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2a02", Name: "Ivan", Email: "ivan@example.com", PhoneNumber: "2020202020",
+				Addresses: []domain.Address{{Street: "9 Elm", City: "Boston", State: "MA", ZipCode: "02101", Country: "USA"}}},
+		}
+		if _, err := repo.ImportChunk(context.Background(), "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e01", domain.ImportOptions{}, users); err != nil {
+			t.Fatalf("seed import failed: %v", err)
+		}
+	}
+
+	conflicting := []domain.User{{
+		ID:          "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2a01",
+		Name:        "Heidi",
+		Email:       "ivan@example.com",
+		PhoneNumber: "1010101010",
+	}}
+
+	cases := []struct {
+		policy      domain.IdentityConflictPolicy
+		wantFailed  int
+		wantUsers   int64
+		wantEmailOf string
+	}{
+		{policy: domain.IdentityConflictReject, wantFailed: 1, wantUsers: 2, wantEmailOf: "heidi@example.com"},
+		{policy: domain.IdentityConflictReassignEmail, wantFailed: 0, wantUsers: 2, wantEmailOf: "ivan@example.com"},
+		{policy: domain.IdentityConflictMerge, wantFailed: 0, wantUsers: 1},
+	}
+
+	for _, tc := range cases {
+		seed(t)
+
+		result, err := repo.ImportChunk(context.Background(), "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e02", domain.ImportOptions{ConflictPolicy: tc.policy}, conflicting)
+		if err != nil {
+			t.Fatalf("%s: import chunk failed: %v", tc.policy, err)
+		}
+		if len(result.Conflicts) != 1 || result.Conflicts[0].Resolution != tc.policy {
+			t.Fatalf("%s: unexpected conflicts: %+v", tc.policy, result.Conflicts)
+		}
+		if int(result.FailedCount) != tc.wantFailed {
+			t.Fatalf("%s: expected failed=%d, got %d", tc.policy, tc.wantFailed, result.FailedCount)
+		}
+
+		var userCount int64
+		if err := gdb.Raw("SELECT COUNT(*) FROM users").Scan(&userCount).Error; err != nil {
+			t.Fatalf("count users failed: %v", err)
+		}
+		if userCount != tc.wantUsers {
+			t.Fatalf("%s: expected %d users, got %d", tc.policy, tc.wantUsers, userCount)
+		}
+
+		if tc.wantEmailOf != "" {
+			var email string
+			if err := gdb.Raw("SELECT email FROM users WHERE id = ?", conflicting[0].ID).Scan(&email).Error; err != nil {
+				t.Fatalf("select email failed: %v", err)
+			}
+			if email != tc.wantEmailOf {
+				t.Fatalf("%s: expected email %s, got %s", tc.policy, tc.wantEmailOf, email)
+			}
+		}
+	}
+}
+
+func TestUserBulkImportRepositoryFillEmptyEmailConflictIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	users := []domain.User{
+		{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2c01", Name: "Heidi", Email: "heidi@example.com", PhoneNumber: "1010101010"},
+		{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2c02", Name: "Ivan", Email: "ivan@example.com", PhoneNumber: "2020202020"},
+	}
+	if _, err := repo.ImportChunk(context.Background(), "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e21", domain.ImportOptions{}, users); err != nil {
+		t.Fatalf("seed import failed: %v", err)
+	}
+	// Heidi lost her email to an earlier reassign_email, so fill_empty would
+	// write Ivan's email to her.
+	if err := gdb.Exec("UPDATE users SET email = NULL, email_key = NULL WHERE id = ?", users[0].ID).Error; err != nil {
+		t.Fatalf("release email failed: %v", err)
+	}
+
+	options := domain.ImportOptions{
+		ConflictPolicy: domain.IdentityConflictReject,
+		UpdatePolicies: domain.FieldUpdatePolicies{Email: domain.FieldUpdateFillEmpty},
+	}
+	conflicting := []domain.User{{ID: users[0].ID, Name: "Heidi", Email: "ivan@example.com", PhoneNumber: "1010101010"}}
+	result, err := repo.ImportChunk(context.Background(), "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e22", options, conflicting)
+	if err != nil {
+		t.Fatalf("import chunk failed: %v", err)
+	}
+	if len(result.Conflicts) != 1 || result.FailedCount != 1 {
+		t.Fatalf("expected the empty email to conflict, got %+v", result)
+	}
+}
+
+func TestUserBulkImportRepositoryStagedIdentityConflictsIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	rows := []domain.User{
+		{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2b01", Name: "Judy", Email: "judy@example.com", PhoneNumber: "1010101010"},
+		{ID: "0b7f3a52-8f6e-4e55-9b61-5a1f8f0c2b02", Name: "Karl", Email: "judy@example.com", PhoneNumber: "2020202020"},
+	}
+
+	cases := []struct {
+		policy     domain.IdentityConflictPolicy
+		wantFailed int
+		wantUsers  int64
+		wantOwner  string
+		wantName   string
+	}{
+		{policy: domain.IdentityConflictReject, wantFailed: 1, wantUsers: 1, wantOwner: rows[0].ID, wantName: "Judy"},
+		{policy: domain.IdentityConflictReassignEmail, wantFailed: 0, wantUsers: 2, wantOwner: rows[1].ID, wantName: "Karl"},
+		{policy: domain.IdentityConflictMerge, wantFailed: 0, wantUsers: 1, wantOwner: rows[0].ID, wantName: "Karl"},
+	}
+
+	for _, tc := range cases {
+		if err := gdb.Exec("DELETE FROM addresses; DELETE FROM users").Error; err != nil {
+			t.Fatalf("cleanup users failed: %v", err)
+		}
+
+		result, err := repo.ImportChunk(context.Background(), "c3d4e5f6-a7b8-4c9d-8e0f-1a2b3c4d5e11", domain.ImportOptions{ConflictPolicy: tc.policy}, rows)
+		if err != nil {
+			t.Fatalf("%s: import chunk failed: %v", tc.policy, err)
+		}
+		if len(result.Conflicts) != 1 || result.Conflicts[0].RowIndex != 1 || result.Conflicts[0].EmailUserID != rows[0].ID || result.Conflicts[0].Resolution != tc.policy {
+			t.Fatalf("%s: unexpected conflicts: %+v", tc.policy, result.Conflicts)
+		}
+		if int(result.FailedCount) != tc.wantFailed {
+			t.Fatalf("%s: expected failed=%d, got %d", tc.policy, tc.wantFailed, result.FailedCount)
+		}
+
+		var userCount int64
+		if err := gdb.Raw("SELECT COUNT(*) FROM users").Scan(&userCount).Error; err != nil {
+			t.Fatalf("count users failed: %v", err)
+		}
+		if userCount != tc.wantUsers {
+			t.Fatalf("%s: expected %d users, got %d", tc.policy, tc.wantUsers, userCount)
+		}
+
+		var owner struct {
+			ID   string
+			Name string
+		}
+		if err := gdb.Raw("SELECT id::text AS id, name FROM users WHERE email = ?", "judy@example.com").Scan(&owner).Error; err != nil {
+			t.Fatalf("select email owner failed: %v", err)
+		}
+		if owner.ID != tc.wantOwner || owner.Name != tc.wantName {
+			t.Fatalf("%s: expected email owner %s (%s), got %+v", tc.policy, tc.wantOwner, tc.wantName, owner)
+		}
+	}
+}
+
+func TestUserBulkImportRepositoryNormalizedEmailIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	normalizer := domain.NewEmailNormalizer(domain.GmailEmailRule)
+
+	for i, email := range []string{"Judy.Smith@Gmail.com", "judysmith+crm@gmail.com", "JUDY.SMITH@gmail.com"} {
+		user, err := domain.NewUserWithEmailNormalizer(normalizer, "", "Judy", email, "+13030303030", nil)
+		if err != nil {
+			t.Fatalf("new user failed: %v", err)
+		}
+		result, err := repo.ImportChunk(context.Background(), "d4e5f6a7-b8c9-4d0e-8f1a-2b3c4d5e6f01", domain.ImportOptions{}, []domain.User{user})
+		if err != nil {
+			t.Fatalf("import chunk %d failed: %v", i, err)
+		}
+		if i > 0 && result.UpdatedCount != 1 {
+			t.Fatalf("import chunk %d: expected existing user to be updated, got %+v", i, result)
+		}
+	}
+
+	var userCount int64
+	if err := gdb.Raw("SELECT COUNT(*) FROM users WHERE email_key = ?", "judysmith@gmail.com").Scan(&userCount).Error; err != nil {
+		t.Fatalf("count users failed: %v", err)
+	}
+	if userCount != 1 {
+		t.Fatalf("expected a single user for normalized email, got %d", userCount)
+	}
+}
+
+func TestUserBulkImportRepositoryStoresRawPhoneNumberIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	user, err := domain.NewUser("", "Kim", "kim@example.com", "(512) 555-0199", []domain.Address{{
+		Street:  "4 Main",
+		City:    "Austin",
+		State:   "TX",
+		ZipCode: "78704",
+		Country: "USA",
+	}})
+	if err != nil {
+		t.Fatalf("new user failed: %v", err)
+	}
+	if _, err := repo.ImportChunk(context.Background(), "e5f6a7b8-c9d0-4e1f-8a2b-3c4d5e6f7a01", domain.ImportOptions{}, []domain.User{user}); err != nil {
+		t.Fatalf("import chunk failed: %v", err)
+	}
+
+	var stored struct {
+		PhoneNumber    string
+		PhoneNumberRaw string
+	}
+	if err := gdb.Raw("SELECT phone_number, phone_number_raw FROM users WHERE email = ?", "kim@example.com").Scan(&stored).Error; err != nil {
+		t.Fatalf("select user failed: %v", err)
+	}
+	if stored.PhoneNumber != "+15125550199" || stored.PhoneNumberRaw != "(512) 555-0199" {
+		t.Fatalf("unexpected stored phone number: %+v", stored)
+	}
+}
+
+func TestUserBulkImportRepositoryDefaultAddressIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+	const userID = "3c1d2f0a-7b4e-4d6a-9c8b-1e2f3a4b5c6d"
+
+	importAddresses := func(jobID string, strategy domain.AddressStrategy, addresses ...domain.Address) {
+		t.Helper()
+		user, err := domain.NewUser(userID, "Dana", "dana@example.com", "+15125550106", addresses)
+		if err != nil {
+			t.Fatalf("build user failed: %v", err)
+		}
+		if _, err := repo.ImportChunk(context.Background(), jobID, domain.ImportOptions{AddressStrategy: strategy}, []domain.User{user}); err != nil {
+			t.Fatalf("import %s failed: %v", jobID, err)
+		}
+	}
+	defaults := func() map[string]string {
+		t.Helper()
+		var rows []struct {
+			Type   string
+			Street string
+		}
+		if err := gdb.Raw("SELECT type, street FROM addresses WHERE user_id = ? AND is_default", userID).Scan(&rows).Error; err != nil {
+			t.Fatalf("select default addresses failed: %v", err)
+		}
+		byType := make(map[string]string, len(rows))
+		for _, row := range rows {
+			byType[row.Type] = row.Street
+		}
+		return byType
+	}
+	address := func(street, zipCode string, addressType domain.AddressType, isDefault bool) domain.Address {
+		return domain.Address{Street: street, City: "Austin", State: "TX", ZipCode: zipCode, Country: "US", Type: addressType, IsDefault: isDefault}
+	}
+
+	importAddresses("6c0d8b1f-1bae-4f1f-9e1c-7d2d2a6f3b01", domain.AddressStrategyReplace,
+		address("1 Main", "78701", domain.AddressTypeHome, false),
+		address("2 Side", "78701", domain.AddressTypeHome, false),
+		address("5 Office", "78702", domain.AddressTypeWork, false),
+	)
+	if got, want := defaults(), map[string]string{"home": "1 Main", "work": "5 Office"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the first address of each type to be promoted %v, got %v", want, got)
+	}
+
+	importAddresses("6c0d8b1f-1bae-4f1f-9e1c-7d2d2a6f3b02", domain.AddressStrategyAppend,
+		address("3 New", "78703", domain.AddressTypeHome, true),
+		address("9 Ship", "78703", domain.AddressTypeShipping, false),
+	)
+	if got, want := defaults(), map[string]string{"home": "3 New", "work": "5 Office", "shipping": "9 Ship"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected the marked address to replace the home default only %v, got %v", want, got)
+	}
+
+	importAddresses("6c0d8b1f-1bae-4f1f-9e1c-7d2d2a6f3b03", domain.AddressStrategyAppend,
+		address("7 Lake", "78704", domain.AddressTypeHome, false),
+	)
+	if got := defaults()["home"]; got != "3 New" {
+		t.Fatalf("expected an unmarked address to keep the stored home default, got %q", got)
+	}
+
+	// The stored work default becomes a billing address, and the home address
+	// merged as work is promoted in its place.
+	importAddresses("6c0d8b1f-1bae-4f1f-9e1c-7d2d2a6f3b04", domain.AddressStrategyMerge,
+		address("5 office", "78702", domain.AddressTypeBilling, true),
+		address("2 side", "78701", domain.AddressTypeWork, false),
+	)
+	if got, want := defaults(), map[string]string{"home": "3 New", "work": "2 side", "billing": "5 office", "shipping": "9 Ship"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected one default per type after the merge %v, got %v", want, got)
+	}
+}
+
+func TestUserBulkImportRepositoryAttributesIntegration(t *testing.T) {
+	gdb, pool := setupBulkImportIntegration(t)
+
+	repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{})
+
+	users := []domain.User{{
+		ID:          "7d2e3f4a-5b6c-4d7e-8f90-a1b2c3d4e5f6",
+		Name:        "Erin",
+		Email:       "erin@example.com",
+		PhoneNumber: "+15125550107",
+		Attributes:  map[string]any{"department": "Sales", "cost_center": 1234},
+	}}
+
+	attributes := func() map[string]any {
+		t.Helper()
+		var payload string
+		if err := gdb.Raw("SELECT attributes::text FROM users WHERE id = ?", users[0].ID).Scan(&payload).Error; err != nil {
+			t.Fatalf("select attributes failed: %v", err)
+		}
+		var got map[string]any
+		if err := json.Unmarshal([]byte(payload), &got); err != nil {
+			t.Fatalf("decode attributes failed: %v", err)
+		}
+		return got
+	}
+
+	if _, err := repo.ImportChunk(context.Background(), "8e1f9c2a-2cbf-4a2f-8f2d-8e3e3b7a4c01", domain.ImportOptions{}, users); err != nil {
+		t.Fatalf("seed import failed: %v", err)
+	}
+	if got := attributes(); got["department"] != "Sales" || got["cost_center"] != float64(1234) {
+		t.Fatalf("unexpected attributes after insert: %v", got)
+	}
+
+	users[0].Attributes = map[string]any{"employee_type": "contractor"}
+	if _, err := repo.ImportChunk(context.Background(), "8e1f9c2a-2cbf-4a2f-8f2d-8e3e3b7a4c02", domain.ImportOptions{AttributeStrategy: domain.AttributeStrategyMerge}, users); err != nil {
+		t.Fatalf("merge import failed: %v", err)
+	}
+	if got := attributes(); got["department"] != "Sales" || got["employee_type"] != "contractor" {
+		t.Fatalf("expected merged attributes, got %v", got)
+	}
+
+	if _, err := repo.ImportChunk(context.Background(), "8e1f9c2a-2cbf-4a2f-8f2d-8e3e3b7a4c03", domain.ImportOptions{AttributeStrategy: domain.AttributeStrategyReplace}, users); err != nil {
+		t.Fatalf("replace import failed: %v", err)
+	}
+	if got := attributes(); len(got) != 1 || got["employee_type"] != "contractor" {
+		t.Fatalf("expected replaced attributes, got %v", got)
+	}
+
+	query := repository.NewUserQueryRepository(gdb)
+	found, err := query.List(context.Background(), domain.UserFilter{Attributes: map[string]string{"employee_type": "contractor"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("list users failed: %v", err)
+	}
+	if len(found) != 1 || found[0].ID != users[0].ID {
+		t.Fatalf("expected attribute filter to find the user, got %+v", found)
+	}
+	found, err = query.List(context.Background(), domain.UserFilter{Attributes: map[string]string{"employee_type": "staff"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("list users failed: %v", err)
+	}
+	if len(found) != 0 {
+		t.Fatalf("expected no users, got %+v", found)
+	}
+}
@@ -0,0 +1,125 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	domain "github.com/mohammadpnp/user-import/internal/domain/user"
+	"github.com/mohammadpnp/user-import/internal/infrastructure/repository"
+)
+
+func stagingBenchmarkUsers(prefix string, chunk int64, size int) []domain.User {
+	users := make([]domain.User, 0, size)
+	for i := range size {
+		users = append(users, domain.User{
+			Name:        fmt.Sprintf("User %d-%d", chunk, i),
+			Email:       fmt.Sprintf("%s-%d-%d@example.com", prefix, chunk, i),
+			PhoneNumber: "+15125550100",
+			Addresses: []domain.Address{{
+				Street:    fmt.Sprintf("%d Main", i),
+				City:      "Austin",
+				State:     "TX",
+				ZipCode:   "78701",
+				Country:   "US",
+				IsDefault: true,
+			}},
+		})
+	}
+	return users
+}
+
+func stagingJobID(mode int, chunk int64) string {
+	return fmt.Sprintf("00000000-0000-4000-8%03d-%012d", mode, chunk)
+}
+
+func TestUserBulkImportRepositoryStagingTablesIntegration(t *testing.T) {
+	gdb, _ := setupBulkImportIntegration(t)
+
+	// A single connection makes every chunk reuse the session that created the
+	// previous chunk's temporary tables.
+	cfg, err := pgxpool.ParseConfig(os.Getenv("TEST_DATABASE_URL"))
+	if err != nil {
+		t.Fatalf("parse pool config failed: %v", err)
+	}
+	cfg.MaxConns = 1
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("failed to create pgx pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	for mode, staging := range []repository.StagingTables{repository.StagingTablesShared, repository.StagingTablesTemporary} {
+		repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{StagingTables: staging})
+
+		for chunk := int64(0); chunk < 2; chunk++ {
+			users := stagingBenchmarkUsers(string(staging), chunk, 3)
+			result, err := repo.ImportChunk(context.Background(), stagingJobID(mode, chunk), domain.ImportOptions{}, users)
+			if err != nil {
+				t.Fatalf("%s: import chunk %d failed: %v", staging, chunk, err)
+			}
+			if result.ImportedCount != 3 {
+				t.Fatalf("%s: expected imported=3 for chunk %d, got %+v", staging, chunk, result)
+			}
+		}
+
+		var addresses int64
+		if err := gdb.Raw(`
+SELECT COUNT(*)
+FROM addresses a
+JOIN users u ON u.id = a.user_id
+WHERE u.email LIKE ? AND a.is_default
+`, string(staging)+"-%").Scan(&addresses).Error; err != nil {
+			t.Fatalf("%s: count addresses failed: %v", staging, err)
+		}
+		if addresses != 6 {
+			t.Fatalf("%s: expected 6 default addresses, got %d", staging, addresses)
+		}
+
+		var leftover struct {
+			Users     int64
+			Addresses int64
+			Temporary bool
+		}
+		if err := pool.QueryRow(context.Background(), `
+SELECT
+  (SELECT COUNT(*) FROM stg_users),
+  (SELECT COUNT(*) FROM stg_addresses),
+  to_regclass('pg_temp.stg_users') IS NOT NULL
+`).Scan(&leftover.Users, &leftover.Addresses, &leftover.Temporary); err != nil {
+			t.Fatalf("%s: inspect staging failed: %v", staging, err)
+		}
+		if leftover.Users != 0 || leftover.Addresses != 0 || leftover.Temporary {
+			t.Fatalf("%s: expected staging to be empty and temporary tables dropped, got %+v", staging, leftover)
+		}
+	}
+}
+
+// BenchmarkUserBulkImportStagingIntegration compares shared unlogged staging
+// tables with per-chunk temporary tables under concurrent chunk writers.
+func BenchmarkUserBulkImportStagingIntegration(b *testing.B) {
+	_, pool := setupBulkImportIntegration(b)
+
+	const chunkSize = 1000
+	for mode, staging := range []repository.StagingTables{repository.StagingTablesShared, repository.StagingTablesTemporary} {
+		repo := repository.NewUserBulkImportRepository(pool, repository.UserBulkImportConfig{StagingTables: staging})
+		var chunks atomic.Int64
+
+		b.Run(string(staging), func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					chunk := chunks.Add(1)
+					users := stagingBenchmarkUsers("bench-"+string(staging), chunk, chunkSize)
+					if _, err := repo.ImportChunk(context.Background(), stagingJobID(mode, chunk), domain.ImportOptions{}, users); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.ReportMetric(float64(b.N*chunkSize)/b.Elapsed().Seconds(), "rows/s")
+		})
+	}
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
 
 	domain "github.com/mohammadpnp/user-import/internal/domain/user"
 	"github.com/mohammadpnp/user-import/internal/infrastructure/db/models"
@@ -22,7 +24,7 @@ func (r *UserQueryRepository) GetByID(ctx context.Context, userID string) (*doma
 	var row models.User
 
 	err := r.db.WithContext(ctx).
-		Preload("Addresses").
+		Preload("Addresses", orderAddresses).
 		First(&row, "id = ?", userID).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -31,6 +33,73 @@ func (r *UserQueryRepository) GetByID(ctx context.Context, userID string) (*doma
 		return nil, fmt.Errorf("get user by id: %w", err)
 	}
 
+	userAggregate := toDomainUser(row)
+	return &userAggregate, nil
+}
+
+func (r *UserQueryRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Addresses", orderAddresses).
+		Order("id").
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+
+	keys := make([]string, 0, len(filter.Attributes))
+	for key := range filter.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		candidates, err := attributeContainments(key, filter.Attributes[key])
+		if err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		if len(candidates) == 1 {
+			query = query.Where("attributes @> ?::jsonb", candidates[0])
+		} else {
+			query = query.Where("(attributes @> ?::jsonb OR attributes @> ?::jsonb)", candidates[0], candidates[1])
+		}
+	}
+
+	var rows []models.User
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+
+	users := make([]domain.User, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, toDomainUser(row))
+	}
+	return users, nil
+}
+
+func attributeContainments(key, value string) ([]string, error) {
+	asString, err := json.Marshal(map[string]string{key: value})
+	if err != nil {
+		return nil, err
+	}
+
+	var scalar any
+	if err := json.Unmarshal([]byte(value), &scalar); err != nil {
+		return []string{string(asString)}, nil
+	}
+	switch scalar.(type) {
+	case float64, bool:
+		asScalar, err := json.Marshal(map[string]json.RawMessage{key: json.RawMessage(value)})
+		if err != nil {
+			return nil, err
+		}
+		return []string{string(asString), string(asScalar)}, nil
+	default:
+		return []string{string(asString)}, nil
+	}
+}
+
+func orderAddresses(db *gorm.DB) *gorm.DB {
+	return db.Order("is_default DESC, id")
+}
+
+func toDomainUser(row models.User) domain.User {
 	addresses := make([]domain.Address, 0, len(row.Addresses))
 	for _, address := range row.Addresses {
 		addresses = append(addresses, domain.Address{
@@ -39,16 +108,26 @@ func (r *UserQueryRepository) GetByID(ctx context.Context, userID string) (*doma
 			State:   address.State,
 			ZipCode: address.ZipCode,
 			Country: address.Country,
+
+			Type:      domain.AddressType(address.Type),
+			IsDefault: address.IsDefault,
 		})
 	}
 
-	userAggregate := &domain.User{
+	userAggregate := domain.User{
 		ID:          row.ID,
 		Name:        row.Name,
 		Email:       row.Email,
+		EmailKey:    row.EmailKey,
 		PhoneNumber: row.PhoneNumber,
 		Addresses:   addresses,
+		Attributes:  row.Attributes,
 	}
-
-	return userAggregate, nil
+	if row.PhoneNumberRaw != nil {
+		userAggregate.PhoneNumberRaw = *row.PhoneNumberRaw
+	}
+	if row.SourceModifiedAt != nil {
+		userAggregate.SourceModifiedAt = *row.SourceModifiedAt
+	}
+	return userAggregate
 }
@@ -29,6 +29,7 @@ func TestUserQueryRepositoryGetByIDIntegration(t *testing.T) {
       name VARCHAR(255) NOT NULL,
       email VARCHAR(320) NOT NULL UNIQUE,
       phone_number VARCHAR(32) NOT NULL,
+      source_modified_at TIMESTAMPTZ,
       created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
     );
@@ -43,6 +44,12 @@ func TestUserQueryRepositoryGetByIDIntegration(t *testing.T) {
       created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
     );
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS source_modified_at TIMESTAMPTZ;
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS email_key VARCHAR(320);
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number_raw TEXT;
+    ALTER TABLE addresses ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'home';
+    ALTER TABLE addresses ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
+    ALTER TABLE users ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
     `
 	if err := db.Exec(schemaSQL).Error; err != nil {
 		t.Fatalf("failed schema setup: %v", err)
@@ -65,12 +72,12 @@ func TestUserQueryRepositoryGetByIDIntegration(t *testing.T) {
 	}
 
 	insertAddressSQL := `
-    INSERT INTO addresses (user_id, street, city, state, zip_code, country)
-    VALUES (?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?)
+    INSERT INTO addresses (user_id, street, city, state, zip_code, country, type, is_default)
+    VALUES (?, ?, ?, ?, ?, ?, ?, ?), (?, ?, ?, ?, ?, ?, ?, ?)
     `
 	if err := db.Exec(insertAddressSQL,
-		userID, "1 Main", "Austin", "TX", "78701", "USA",
-		userID, "2 Main", "Austin", "TX", "78702", "USA",
+		userID, "1 Main", "Austin", "TX", "78701", "USA", "home", false,
+		userID, "2 Main", "Austin", "TX", "78702", "USA", "work", true,
 	).Error; err != nil {
 		t.Fatalf("insert addresses failed: %v", err)
 	}
@@ -87,6 +94,9 @@ func TestUserQueryRepositoryGetByIDIntegration(t *testing.T) {
 	if len(got.Addresses) != 2 {
 		t.Fatalf("expected 2 addresses, got %d", len(got.Addresses))
 	}
+	if !got.Addresses[0].IsDefault || got.Addresses[0].Type != domain.AddressTypeWork {
+		t.Fatalf("expected default work address first, got %+v", got.Addresses[0])
+	}
 
 	_, err = repo.GetByID(context.Background(), "11111111-1111-1111-1111-111111111111")
 	if err == nil {
@@ -12,8 +12,68 @@ type ImportHandler struct {
 	useCase app.StartImportUsersFromJSON
 }
 
+type updatePoliciesRequest struct {
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+type addressValidationRequest struct {
+	Country     string `json:"country"`
+	PostalCode  string `json:"postal_code"`
+	Subdivision string `json:"subdivision"`
+	Normalize   *bool  `json:"normalize"`
+}
+
+type fieldTransformRequest struct {
+	Target    string            `json:"target"`
+	Source    string            `json:"source"`
+	Sources   []string          `json:"sources"`
+	Separator string            `json:"separator"`
+	Split     string            `json:"split"`
+	Index     int               `json:"index"`
+	Trim      bool              `json:"trim"`
+	Case      string            `json:"case"`
+	Lookup    map[string]string `json:"lookup"`
+	Default   *string           `json:"default"`
+}
+
 type importUsersRequest struct {
-	SourcePath string `json:"source_path"`
+	SourcePath      string                `json:"source_path"`
+	AddressStrategy string                `json:"address_strategy"`
+	UpdatePolicies  updatePoliciesRequest `json:"update_policies"`
+	Source          string                `json:"source"`
+	ConflictPolicy  string                `json:"conflict_policy"`
+	Profile         string                `json:"profile"`
+
+	AddressValidation addressValidationRequest `json:"address_validation"`
+	OversizePolicy    string                   `json:"oversize_policy"`
+	PhoneRegion       string                   `json:"phone_region"`
+	AttributeStrategy string                   `json:"attribute_strategy"`
+	AttributeFields   []string                 `json:"attribute_fields"`
+	SchemaMode        string                   `json:"schema_mode"`
+
+	Format           string                  `json:"format"`
+	Encoding         string                  `json:"encoding"`
+	Sheet            string                  `json:"sheet"`
+	XML              xmlMappingRequest       `json:"xml"`
+	FieldMapping     map[string]string       `json:"field_mapping"`
+	Transforms       []fieldTransformRequest `json:"field_transforms"`
+	ChunkSize        int                     `json:"chunk_size"`
+	ChunkConcurrency int                     `json:"chunk_concurrency"`
+	Partitions       int                     `json:"partitions"`
+	MaxAttempts      int                     `json:"max_attempts"`
+
+	RecordsPointer string            `json:"records_pointer"`
+	Metadata       map[string]string `json:"metadata"`
+}
+
+type xmlMappingRequest struct {
+	RecordPath    string            `json:"record_path"`
+	Fields        map[string]string `json:"fields"`
+	AddressPath   string            `json:"address_path"`
+	AddressFields map[string]string `json:"address_fields"`
+	Namespaces    map[string]string `json:"namespaces"`
 }
 
 type errorBody struct {
@@ -40,13 +100,51 @@ func (h *ImportHandler) ImportUsers(c echo.Context) error {
 	}
 
 	out, err := h.useCase.Execute(c.Request().Context(), app.StartImportUsersFromJSONInput{
-		SourcePath: req.SourcePath,
+		SourcePath:      req.SourcePath,
+		AddressStrategy: req.AddressStrategy,
+		Source:          req.Source,
+		ConflictPolicy:  req.ConflictPolicy,
+		Profile:         req.Profile,
+		UpdatePolicies: app.FieldUpdatePoliciesInput{
+			Name:        req.UpdatePolicies.Name,
+			Email:       req.UpdatePolicies.Email,
+			PhoneNumber: req.UpdatePolicies.PhoneNumber,
+		},
+		AddressValidation: app.AddressValidationInput{
+			Country:     req.AddressValidation.Country,
+			PostalCode:  req.AddressValidation.PostalCode,
+			Subdivision: req.AddressValidation.Subdivision,
+			Normalize:   req.AddressValidation.Normalize,
+		},
+		OversizePolicy:    req.OversizePolicy,
+		PhoneRegion:       req.PhoneRegion,
+		AttributeStrategy: req.AttributeStrategy,
+		AttributeFields:   req.AttributeFields,
+		SchemaMode:        req.SchemaMode,
+		Format:            req.Format,
+		Encoding:          req.Encoding,
+		Sheet:             req.Sheet,
+		XML:               app.XMLMappingInput(req.XML),
+		FieldMapping:      req.FieldMapping,
+		Transforms:        toFieldTransformInputs(req.Transforms),
+		ChunkSize:         req.ChunkSize,
+		ChunkConcurrency:  req.ChunkConcurrency,
+		Partitions:        req.Partitions,
+		MaxAttempts:       req.MaxAttempts,
+		RecordsPointer:    req.RecordsPointer,
+		Metadata:          req.Metadata,
 	})
 	if err != nil {
 		if errors.Is(err, app.ErrInvalidImportSource) {
 			return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
 				Code:    "invalid_source",
-				Message: "source_path must be a .json file",
+				Message: "source_path extension must match the import format",
+			}})
+		}
+		if errors.Is(err, app.ErrInvalidImportOptions) {
+			return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+				Code:    "invalid_options",
+				Message: err.Error(),
 			}})
 		}
 		return c.JSON(http.StatusInternalServerError, apiResponse{Error: &errorBody{
@@ -57,3 +155,26 @@ func (h *ImportHandler) ImportUsers(c echo.Context) error {
 
 	return c.JSON(http.StatusAccepted, apiResponse{Data: out})
 }
+
+func toFieldTransformInputs(transforms []fieldTransformRequest) []app.FieldTransformInput {
+	if transforms == nil {
+		return nil
+	}
+
+	out := make([]app.FieldTransformInput, 0, len(transforms))
+	for _, transform := range transforms {
+		out = append(out, app.FieldTransformInput{
+			Target:    transform.Target,
+			Source:    transform.Source,
+			Sources:   transform.Sources,
+			Separator: transform.Separator,
+			Split:     transform.Split,
+			Index:     transform.Index,
+			Trim:      transform.Trim,
+			Case:      transform.Case,
+			Lookup:    transform.Lookup,
+			Default:   transform.Default,
+		})
+	}
+	return out
+}
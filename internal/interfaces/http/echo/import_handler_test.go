@@ -34,7 +34,7 @@ func TestImportHandlerSuccess(t *testing.T) {
 		JobID:  "job-1",
 		Status: "queued",
 	}})
-	httpecho.RegisterRoutes(e, handler, nil)
+	httpecho.RegisterRoutes(e, handler, nil, nil, nil)
 
 	body := []byte(`{"source_path":"users_data.json"}`)
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/users", bytes.NewReader(body))
@@ -66,7 +66,7 @@ func TestImportHandlerBadJSON(t *testing.T) {
 
 	e := echo.New()
 	handler := httpecho.NewImportHandler(&fakeImportUseCase{})
-	httpecho.RegisterRoutes(e, handler, nil)
+	httpecho.RegisterRoutes(e, handler, nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/users", bytes.NewReader([]byte(`{"source_path":`)))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -84,7 +84,7 @@ func TestImportHandlerInvalidSource(t *testing.T) {
 
 	e := echo.New()
 	handler := httpecho.NewImportHandler(&fakeImportUseCase{err: app.ErrInvalidImportSource})
-	httpecho.RegisterRoutes(e, handler, nil)
+	httpecho.RegisterRoutes(e, handler, nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/users", bytes.NewReader([]byte(`{"source_path":""}`)))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -97,12 +97,30 @@ func TestImportHandlerInvalidSource(t *testing.T) {
 	}
 }
 
+func TestImportHandlerInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	handler := httpecho.NewImportHandler(&fakeImportUseCase{err: app.ErrInvalidImportOptions})
+	httpecho.RegisterRoutes(e, handler, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/users", bytes.NewReader([]byte(`{"source_path":"users_data.json","address_strategy":"overwrite"}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
 func TestImportHandlerInternalError(t *testing.T) {
 	t.Parallel()
 
 	e := echo.New()
 	handler := httpecho.NewImportHandler(&fakeImportUseCase{err: errors.New("boom")})
-	httpecho.RegisterRoutes(e, handler, nil)
+	httpecho.RegisterRoutes(e, handler, nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/imports/users", bytes.NewReader([]byte(`{"source_path":"users_data.json"}`)))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -0,0 +1,85 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+)
+
+type ImportJobHandler struct {
+	getJob        app.GetImportJob
+	listConflicts app.ListImportConflicts
+}
+
+func NewImportJobHandler(getJob app.GetImportJob, listConflicts app.ListImportConflicts) *ImportJobHandler {
+	return &ImportJobHandler{getJob: getJob, listConflicts: listConflicts}
+}
+
+func (h *ImportJobHandler) GetImportJob(c echo.Context) error {
+	out, err := h.getJob.Execute(c.Request().Context(), app.GetImportJobInput{
+		ID: c.Param("id"),
+	})
+	if err != nil {
+		return importJobError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, apiResponse{Data: out})
+}
+
+func (h *ImportJobHandler) ListConflicts(c echo.Context) error {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "limit must be an integer",
+		}})
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "offset must be an integer",
+		}})
+	}
+
+	out, err := h.listConflicts.Execute(c.Request().Context(), app.ListImportConflictsInput{
+		JobID:  c.Param("id"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return importJobError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, apiResponse{Data: out})
+}
+
+func importJobError(c echo.Context, err error) error {
+	if errors.Is(err, app.ErrInvalidImportJobID) {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "invalid_job_id",
+			Message: "id must be a valid UUID",
+		}})
+	}
+	if errors.Is(err, app.ErrImportJobNotFound) {
+		return c.JSON(http.StatusNotFound, apiResponse{Error: &errorBody{
+			Code:    "not_found",
+			Message: "import job not found",
+		}})
+	}
+	return c.JSON(http.StatusInternalServerError, apiResponse{Error: &errorBody{
+		Code:    "internal_error",
+		Message: "failed to get import job",
+	}})
+}
+
+func queryInt(c echo.Context, name string) (int, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(raw)
+}
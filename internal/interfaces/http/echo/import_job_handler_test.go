@@ -0,0 +1,173 @@
+package echo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+	httpecho "github.com/mohammadpnp/user-import/internal/interfaces/http/echo"
+)
+
+type fakeGetImportJobUseCase struct {
+	out app.GetImportJobOutput
+	err error
+}
+
+func (f *fakeGetImportJobUseCase) Execute(ctx context.Context, in app.GetImportJobInput) (app.GetImportJobOutput, error) {
+	if f.err != nil {
+		return app.GetImportJobOutput{}, f.err
+	}
+	return f.out, nil
+}
+
+type fakeListImportConflictsUseCase struct {
+	out app.ListImportConflictsOutput
+	err error
+	in  app.ListImportConflictsInput
+}
+
+func (f *fakeListImportConflictsUseCase) Execute(ctx context.Context, in app.ListImportConflictsInput) (app.ListImportConflictsOutput, error) {
+	f.in = in
+	if f.err != nil {
+		return app.ListImportConflictsOutput{}, f.err
+	}
+	return f.out, nil
+}
+
+func TestGetImportJobHandlerSuccess(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	handler := httpecho.NewImportJobHandler(&fakeGetImportJobUseCase{out: app.GetImportJobOutput{
+		ID:     "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+		Status: "running",
+	}}, &fakeListImportConflictsUseCase{})
+	httpecho.RegisterRoutes(e, nil, nil, handler, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/imports/4955eb4d-c7f2-42f6-80ca-33838ce37c31", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected json: %v", err)
+	}
+	data := got["data"].(map[string]any)
+	if data["status"] != "running" {
+		t.Fatalf("unexpected status: %#v", data["status"])
+	}
+}
+
+func TestGetImportJobHandlerReturnsRuleViolations(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	handler := httpecho.NewImportJobHandler(&fakeGetImportJobUseCase{out: app.GetImportJobOutput{
+		ID:     "4955eb4d-c7f2-42f6-80ca-33838ce37c31",
+		Status: "succeeded",
+		Failures: []app.ImportFailureOutput{{
+			RowIndex: 4,
+			Locator:  app.RecordLocatorOutput{Row: 5, Offset: 812},
+			Reason:   "rule_violation",
+			RuleID:   "phone-required",
+			Field:    "phone_number",
+		}},
+	}}, &fakeListImportConflictsUseCase{})
+	httpecho.RegisterRoutes(e, nil, nil, handler, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/imports/4955eb4d-c7f2-42f6-80ca-33838ce37c31", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var got struct {
+		Data struct {
+			Failures []map[string]any `json:"failures"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected json: %v", err)
+	}
+	if len(got.Data.Failures) != 1 {
+		t.Fatalf("expected one failure, got %s", rec.Body.String())
+	}
+	failure := got.Data.Failures[0]
+	if failure["rule_id"] != "phone-required" || failure["field"] != "phone_number" || failure["row_index"] != float64(4) {
+		t.Fatalf("unexpected failure: %#v", failure)
+	}
+	if locator, _ := failure["locator"].(map[string]any); locator["row"] != float64(5) || locator["offset"] != float64(812) {
+		t.Fatalf("unexpected locator: %#v", failure["locator"])
+	}
+}
+
+func TestGetImportJobHandlerErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[error]int{
+		app.ErrInvalidImportJobID: http.StatusBadRequest,
+		app.ErrImportJobNotFound:  http.StatusNotFound,
+		errors.New("boom"):        http.StatusInternalServerError,
+	}
+	for useCaseErr, want := range cases {
+		e := echo.New()
+		handler := httpecho.NewImportJobHandler(&fakeGetImportJobUseCase{err: useCaseErr}, &fakeListImportConflictsUseCase{})
+		httpecho.RegisterRoutes(e, nil, nil, handler, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/imports/job-1", nil)
+		rec := httptest.NewRecorder()
+
+		e.ServeHTTP(rec, req)
+
+		if rec.Code != want {
+			t.Fatalf("%v: expected %d, got %d", useCaseErr, want, rec.Code)
+		}
+	}
+}
+
+func TestListImportConflictsHandler(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	listConflicts := &fakeListImportConflictsUseCase{out: app.ListImportConflictsOutput{
+		Conflicts: []app.ImportConflictOutput{{RowIndex: 3, Resolution: "reject"}},
+		Limit:     10,
+		Offset:    20,
+	}}
+	handler := httpecho.NewImportJobHandler(&fakeGetImportJobUseCase{}, listConflicts)
+	httpecho.RegisterRoutes(e, nil, nil, handler, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/imports/4955eb4d-c7f2-42f6-80ca-33838ce37c31/conflicts?limit=10&offset=20", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if listConflicts.in.Limit != 10 || listConflicts.in.Offset != 20 {
+		t.Fatalf("unexpected paging input: %+v", listConflicts.in)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/imports/4955eb4d-c7f2-42f6-80ca-33838ce37c31/conflicts?limit=ten", nil)
+	rec = httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,227 @@
+package echo
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+)
+
+type ImportProfileHandler struct {
+	create app.CreateImportProfile
+	get    app.GetImportProfile
+	list   app.ListImportProfiles
+	update app.UpdateImportProfile
+	delete app.DeleteImportProfile
+}
+
+type ruleConditionRequest struct {
+	Field  string   `json:"field"`
+	Equals []string `json:"equals"`
+}
+
+type validationRuleRequest struct {
+	ID      string                `json:"id"`
+	Type    string                `json:"type"`
+	Field   string                `json:"field"`
+	Pattern string                `json:"pattern"`
+	Min     *int                  `json:"min"`
+	Max     *int                  `json:"max"`
+	Values  []string              `json:"values"`
+	When    *ruleConditionRequest `json:"when"`
+}
+
+type importProfileRequest struct {
+	Name             string                  `json:"name"`
+	Rules            []validationRuleRequest `json:"rules"`
+	Format           string                  `json:"format"`
+	Encoding         string                  `json:"encoding"`
+	Sheet            string                  `json:"sheet"`
+	XML              xmlMappingRequest       `json:"xml"`
+	FieldMapping     map[string]string       `json:"field_mapping"`
+	Transforms       []fieldTransformRequest `json:"field_transforms"`
+	RecordsPointer   string                  `json:"records_pointer"`
+	Metadata         map[string]string       `json:"metadata"`
+	ChunkSize        int                     `json:"chunk_size"`
+	ChunkConcurrency int                     `json:"chunk_concurrency"`
+	Partitions       int                     `json:"partitions"`
+	MaxAttempts      int                     `json:"max_attempts"`
+	AddressStrategy  string                  `json:"address_strategy"`
+	UpdatePolicies   updatePoliciesRequest   `json:"update_policies"`
+	Source           string                  `json:"source"`
+	ConflictPolicy   string                  `json:"conflict_policy"`
+
+	AddressValidation addressValidationRequest `json:"address_validation"`
+	OversizePolicy    string                   `json:"oversize_policy"`
+	PhoneRegion       string                   `json:"phone_region"`
+	AttributeStrategy string                   `json:"attribute_strategy"`
+	AttributeFields   []string                 `json:"attribute_fields"`
+	SchemaMode        string                   `json:"schema_mode"`
+}
+
+func NewImportProfileHandler(
+	create app.CreateImportProfile,
+	get app.GetImportProfile,
+	list app.ListImportProfiles,
+	update app.UpdateImportProfile,
+	delete app.DeleteImportProfile,
+) *ImportProfileHandler {
+	return &ImportProfileHandler{create: create, get: get, list: list, update: update, delete: delete}
+}
+
+func (h *ImportProfileHandler) CreateImportProfile(c echo.Context) error {
+	var req importProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "invalid request body",
+		}})
+	}
+
+	out, err := h.create.Execute(c.Request().Context(), req.toInput(req.Name))
+	if err != nil {
+		return importProfileError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, apiResponse{Data: out})
+}
+
+func (h *ImportProfileHandler) GetImportProfile(c echo.Context) error {
+	out, err := h.get.Execute(c.Request().Context(), app.GetImportProfileInput{Name: c.Param("name")})
+	if err != nil {
+		return importProfileError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, apiResponse{Data: out})
+}
+
+func (h *ImportProfileHandler) ListImportProfiles(c echo.Context) error {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "limit must be an integer",
+		}})
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "offset must be an integer",
+		}})
+	}
+
+	out, err := h.list.Execute(c.Request().Context(), app.ListImportProfilesInput{Limit: limit, Offset: offset})
+	if err != nil {
+		return importProfileError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, apiResponse{Data: out})
+}
+
+func (h *ImportProfileHandler) UpdateImportProfile(c echo.Context) error {
+	var req importProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "invalid request body",
+		}})
+	}
+
+	out, err := h.update.Execute(c.Request().Context(), req.toInput(c.Param("name")))
+	if err != nil {
+		return importProfileError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, apiResponse{Data: out})
+}
+
+func (h *ImportProfileHandler) DeleteImportProfile(c echo.Context) error {
+	if err := h.delete.Execute(c.Request().Context(), app.DeleteImportProfileInput{Name: c.Param("name")}); err != nil {
+		return importProfileError(c, err)
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (req importProfileRequest) toInput(name string) app.ImportProfileInput {
+	rules := make([]app.ValidationRuleInput, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		var when *app.RuleConditionInput
+		if rule.When != nil {
+			when = &app.RuleConditionInput{Field: rule.When.Field, Equals: rule.When.Equals}
+		}
+		rules = append(rules, app.ValidationRuleInput{
+			ID:      rule.ID,
+			Type:    rule.Type,
+			Field:   rule.Field,
+			Pattern: rule.Pattern,
+			Min:     rule.Min,
+			Max:     rule.Max,
+			Values:  rule.Values,
+			When:    when,
+		})
+	}
+
+	return app.ImportProfileInput{
+		Name:             name,
+		Rules:            rules,
+		Format:           req.Format,
+		Encoding:         req.Encoding,
+		Sheet:            req.Sheet,
+		XML:              app.XMLMappingInput(req.XML),
+		FieldMapping:     req.FieldMapping,
+		Transforms:       toFieldTransformInputs(req.Transforms),
+		RecordsPointer:   req.RecordsPointer,
+		Metadata:         req.Metadata,
+		ChunkSize:        req.ChunkSize,
+		ChunkConcurrency: req.ChunkConcurrency,
+		Partitions:       req.Partitions,
+		MaxAttempts:      req.MaxAttempts,
+		AddressStrategy:  req.AddressStrategy,
+		UpdatePolicies: app.FieldUpdatePoliciesInput{
+			Name:        req.UpdatePolicies.Name,
+			Email:       req.UpdatePolicies.Email,
+			PhoneNumber: req.UpdatePolicies.PhoneNumber,
+		},
+		Source:         req.Source,
+		ConflictPolicy: req.ConflictPolicy,
+		AddressValidation: app.AddressValidationInput{
+			Country:     req.AddressValidation.Country,
+			PostalCode:  req.AddressValidation.PostalCode,
+			Subdivision: req.AddressValidation.Subdivision,
+			Normalize:   req.AddressValidation.Normalize,
+		},
+		OversizePolicy:    req.OversizePolicy,
+		PhoneRegion:       req.PhoneRegion,
+		AttributeStrategy: req.AttributeStrategy,
+		AttributeFields:   req.AttributeFields,
+		SchemaMode:        req.SchemaMode,
+	}
+}
+
+func importProfileError(c echo.Context, err error) error {
+	if errors.Is(err, app.ErrInvalidImportProfile) {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "invalid_profile",
+			Message: err.Error(),
+		}})
+	}
+	if errors.Is(err, app.ErrImportProfileNotFound) {
+		return c.JSON(http.StatusNotFound, apiResponse{Error: &errorBody{
+			Code:    "not_found",
+			Message: "import profile not found",
+		}})
+	}
+	if errors.Is(err, app.ErrImportProfileExists) {
+		return c.JSON(http.StatusConflict, apiResponse{Error: &errorBody{
+			Code:    "conflict",
+			Message: "import profile already exists",
+		}})
+	}
+	return c.JSON(http.StatusInternalServerError, apiResponse{Error: &errorBody{
+		Code:    "internal_error",
+		Message: "failed to process import profile",
+	}})
+}
@@ -0,0 +1,185 @@
+package echo_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	app "github.com/mohammadpnp/user-import/internal/application/user"
+	httpecho "github.com/mohammadpnp/user-import/internal/interfaces/http/echo"
+)
+
+type fakeSaveImportProfileUseCase struct {
+	out app.ImportProfileOutput
+	err error
+	in  app.ImportProfileInput
+}
+
+func (f *fakeSaveImportProfileUseCase) Execute(ctx context.Context, in app.ImportProfileInput) (app.ImportProfileOutput, error) {
+	f.in = in
+	if f.err != nil {
+		return app.ImportProfileOutput{}, f.err
+	}
+	return f.out, nil
+}
+
+type fakeGetImportProfileUseCase struct {
+	out app.ImportProfileOutput
+	err error
+}
+
+func (f *fakeGetImportProfileUseCase) Execute(ctx context.Context, in app.GetImportProfileInput) (app.ImportProfileOutput, error) {
+	if f.err != nil {
+		return app.ImportProfileOutput{}, f.err
+	}
+	return f.out, nil
+}
+
+type fakeListImportProfilesUseCase struct {
+	out app.ListImportProfilesOutput
+	in  app.ListImportProfilesInput
+}
+
+func (f *fakeListImportProfilesUseCase) Execute(ctx context.Context, in app.ListImportProfilesInput) (app.ListImportProfilesOutput, error) {
+	f.in = in
+	return f.out, nil
+}
+
+type fakeDeleteImportProfileUseCase struct {
+	err error
+	in  app.DeleteImportProfileInput
+}
+
+func (f *fakeDeleteImportProfileUseCase) Execute(ctx context.Context, in app.DeleteImportProfileInput) error {
+	f.in = in
+	return f.err
+}
+
+func TestCreateImportProfileHandler(t *testing.T) {
+	t.Parallel()
+
+	create := &fakeSaveImportProfileUseCase{out: app.ImportProfileOutput{Name: "crm", Format: "json"}}
+	e := echo.New()
+	handler := httpecho.NewImportProfileHandler(create, &fakeGetImportProfileUseCase{}, &fakeListImportProfilesUseCase{}, &fakeSaveImportProfileUseCase{}, &fakeDeleteImportProfileUseCase{})
+	httpecho.RegisterRoutes(e, nil, nil, nil, handler)
+
+	body := `{
+      "name":"crm",
+      "chunk_size":500,
+      "max_attempts":2,
+      "field_mapping":{"fullName":"name"},
+      "rules":[{"id":"us-zip","type":"regex","field":"addresses.zip_code","pattern":"^\\d{5}$","when":{"field":"addresses.country","equals":["US"]}}]
+    }`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/import-profiles", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", rec.Code)
+	}
+	if create.in.Name != "crm" || create.in.ChunkSize != 500 || create.in.MaxAttempts != 2 || create.in.FieldMapping["fullName"] != "name" {
+		t.Fatalf("unexpected input: %+v", create.in)
+	}
+	if len(create.in.Rules) != 1 || create.in.Rules[0].When == nil || create.in.Rules[0].When.Equals[0] != "US" {
+		t.Fatalf("unexpected rules: %+v", create.in.Rules)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected json: %v", err)
+	}
+	if got["data"].(map[string]any)["name"] != "crm" {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestUpdateImportProfileHandlerUsesPathName(t *testing.T) {
+	t.Parallel()
+
+	update := &fakeSaveImportProfileUseCase{}
+	e := echo.New()
+	handler := httpecho.NewImportProfileHandler(&fakeSaveImportProfileUseCase{}, &fakeGetImportProfileUseCase{}, &fakeListImportProfilesUseCase{}, update, &fakeDeleteImportProfileUseCase{})
+	httpecho.RegisterRoutes(e, nil, nil, nil, handler)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/import-profiles/crm", strings.NewReader(`{"name":"other","schema_mode":"strict"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if update.in.Name != "crm" || update.in.SchemaMode != "strict" {
+		t.Fatalf("unexpected input: %+v", update.in)
+	}
+}
+
+func TestImportProfileHandlerErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := map[error]int{
+		app.ErrInvalidImportProfile:  http.StatusBadRequest,
+		app.ErrImportProfileNotFound: http.StatusNotFound,
+		app.ErrImportProfileExists:   http.StatusConflict,
+		errors.New("boom"):           http.StatusInternalServerError,
+	}
+	for useCaseErr, want := range cases {
+		e := echo.New()
+		handler := httpecho.NewImportProfileHandler(&fakeSaveImportProfileUseCase{err: useCaseErr}, &fakeGetImportProfileUseCase{err: useCaseErr}, &fakeListImportProfilesUseCase{}, &fakeSaveImportProfileUseCase{}, &fakeDeleteImportProfileUseCase{})
+		httpecho.RegisterRoutes(e, nil, nil, nil, handler)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/import-profiles", strings.NewReader(`{"name":"crm"}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("create with %v: expected %d, got %d", useCaseErr, want, rec.Code)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/import-profiles/crm", nil)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("get with %v: expected %d, got %d", useCaseErr, want, rec.Code)
+		}
+	}
+}
+
+func TestListAndDeleteImportProfileHandler(t *testing.T) {
+	t.Parallel()
+
+	list := &fakeListImportProfilesUseCase{out: app.ListImportProfilesOutput{Limit: 10}}
+	remove := &fakeDeleteImportProfileUseCase{}
+	e := echo.New()
+	handler := httpecho.NewImportProfileHandler(&fakeSaveImportProfileUseCase{}, &fakeGetImportProfileUseCase{}, list, &fakeSaveImportProfileUseCase{}, remove)
+	httpecho.RegisterRoutes(e, nil, nil, nil, handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/import-profiles?limit=10&offset=20", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || list.in.Limit != 10 || list.in.Offset != 20 {
+		t.Fatalf("unexpected list result: code=%d in=%+v", rec.Code, list.in)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/import-profiles?limit=ten", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for invalid limit, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/v1/import-profiles/crm", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNoContent || remove.in.Name != "crm" {
+		t.Fatalf("unexpected delete result: code=%d in=%+v", rec.Code, remove.in)
+	}
+}
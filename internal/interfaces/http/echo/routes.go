@@ -2,11 +2,23 @@ package echo
 
 import e "github.com/labstack/echo/v4"
 
-func RegisterRoutes(server *e.Echo, importHandler *ImportHandler, userHandler *UserHandler) {
+func RegisterRoutes(server *e.Echo, importHandler *ImportHandler, userHandler *UserHandler, importJobHandler *ImportJobHandler, importProfileHandler *ImportProfileHandler) {
 	if importHandler != nil {
 		server.POST("/api/v1/imports/users", importHandler.ImportUsers)
 	}
+	if importJobHandler != nil {
+		server.GET("/api/v1/imports/:id", importJobHandler.GetImportJob)
+		server.GET("/api/v1/imports/:id/conflicts", importJobHandler.ListConflicts)
+	}
+	if importProfileHandler != nil {
+		server.POST("/api/v1/import-profiles", importProfileHandler.CreateImportProfile)
+		server.GET("/api/v1/import-profiles", importProfileHandler.ListImportProfiles)
+		server.GET("/api/v1/import-profiles/:name", importProfileHandler.GetImportProfile)
+		server.PUT("/api/v1/import-profiles/:name", importProfileHandler.UpdateImportProfile)
+		server.DELETE("/api/v1/import-profiles/:name", importProfileHandler.DeleteImportProfile)
+	}
 	if userHandler != nil {
+		server.GET("/api/v1/users", userHandler.ListUsers)
 		server.GET("/api/v1/users/:id", userHandler.GetUserByID)
 	}
 }
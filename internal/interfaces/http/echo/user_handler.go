@@ -3,17 +3,21 @@ package echo
 import (
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	app "github.com/mohammadpnp/user-import/internal/application/user"
 )
 
+const attributeQueryPrefix = "attr."
+
 type UserHandler struct {
-	useCase app.GetUserByID
+	useCase   app.GetUserByID
+	listUsers app.ListUsers
 }
 
-func NewUserHandler(useCase app.GetUserByID) *UserHandler {
-	return &UserHandler{useCase: useCase}
+func NewUserHandler(useCase app.GetUserByID, listUsers app.ListUsers) *UserHandler {
+	return &UserHandler{useCase: useCase, listUsers: listUsers}
 }
 
 func (h *UserHandler) GetUserByID(c echo.Context) error {
@@ -42,3 +46,48 @@ func (h *UserHandler) GetUserByID(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, apiResponse{Data: out})
 }
+
+func (h *UserHandler) ListUsers(c echo.Context) error {
+	limit, err := queryInt(c, "limit")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "limit must be an integer",
+		}})
+	}
+	offset, err := queryInt(c, "offset")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+			Code:    "bad_request",
+			Message: "offset must be an integer",
+		}})
+	}
+
+	attributes := make(map[string]string)
+	for name, values := range c.QueryParams() {
+		if key, ok := strings.CutPrefix(name, attributeQueryPrefix); ok && len(values) > 0 {
+			attributes[key] = values[0]
+		}
+	}
+
+	out, err := h.listUsers.Execute(c.Request().Context(), app.ListUsersInput{
+		Attributes: attributes,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		if errors.Is(err, app.ErrInvalidUserFilter) {
+			return c.JSON(http.StatusBadRequest, apiResponse{Error: &errorBody{
+				Code:    "invalid_filter",
+				Message: "attribute filters must be attr.<key>=<value> with keys of letters, digits, '_', '.' or '-'",
+			}})
+		}
+
+		return c.JSON(http.StatusInternalServerError, apiResponse{Error: &errorBody{
+			Code:    "internal_error",
+			Message: "failed to list users",
+		}})
+	}
+
+	return c.JSON(http.StatusOK, apiResponse{Data: out})
+}
@@ -41,8 +41,8 @@ func TestGetUserByIDHandlerSuccess(t *testing.T) {
 			ZipCode: "78701",
 			Country: "USA",
 		}},
-	}})
-	httpecho.RegisterRoutes(e, nil, userHandler)
+	}}, nil)
+	httpecho.RegisterRoutes(e, nil, userHandler, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/a3f91a91-7fdd-43bf-bfd2-00bc02f6c53e", nil)
 	rec := httptest.NewRecorder()
@@ -68,8 +68,8 @@ func TestGetUserByIDHandlerInvalidID(t *testing.T) {
 	t.Parallel()
 
 	e := echo.New()
-	userHandler := httpecho.NewUserHandler(&fakeGetUserUseCase{err: app.ErrInvalidUserID})
-	httpecho.RegisterRoutes(e, nil, userHandler)
+	userHandler := httpecho.NewUserHandler(&fakeGetUserUseCase{err: app.ErrInvalidUserID}, nil)
+	httpecho.RegisterRoutes(e, nil, userHandler, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/not-uuid", nil)
 	rec := httptest.NewRecorder()
@@ -85,8 +85,8 @@ func TestGetUserByIDHandlerNotFound(t *testing.T) {
 	t.Parallel()
 
 	e := echo.New()
-	userHandler := httpecho.NewUserHandler(&fakeGetUserUseCase{err: app.ErrUserNotFound})
-	httpecho.RegisterRoutes(e, nil, userHandler)
+	userHandler := httpecho.NewUserHandler(&fakeGetUserUseCase{err: app.ErrUserNotFound}, nil)
+	httpecho.RegisterRoutes(e, nil, userHandler, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/a3f91a91-7fdd-43bf-bfd2-00bc02f6c53e", nil)
 	rec := httptest.NewRecorder()
@@ -102,8 +102,8 @@ func TestGetUserByIDHandlerInternalError(t *testing.T) {
 	t.Parallel()
 
 	e := echo.New()
-	userHandler := httpecho.NewUserHandler(&fakeGetUserUseCase{err: errors.New("boom")})
-	httpecho.RegisterRoutes(e, nil, userHandler)
+	userHandler := httpecho.NewUserHandler(&fakeGetUserUseCase{err: errors.New("boom")}, nil)
+	httpecho.RegisterRoutes(e, nil, userHandler, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/a3f91a91-7fdd-43bf-bfd2-00bc02f6c53e", nil)
 	rec := httptest.NewRecorder()
@@ -114,3 +114,67 @@ func TestGetUserByIDHandlerInternalError(t *testing.T) {
 		t.Fatalf("expected 500, got %d", rec.Code)
 	}
 }
+
+type fakeListUsersUseCase struct {
+	got app.ListUsersInput
+	out app.ListUsersOutput
+	err error
+}
+
+func (f *fakeListUsersUseCase) Execute(ctx context.Context, in app.ListUsersInput) (app.ListUsersOutput, error) {
+	f.got = in
+	if f.err != nil {
+		return app.ListUsersOutput{}, f.err
+	}
+	return f.out, nil
+}
+
+func TestListUsersHandlerFiltersByAttributes(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	listUsers := &fakeListUsersUseCase{out: app.ListUsersOutput{
+		Users: []app.GetUserByIDOutput{{ID: "a3f91a91-7fdd-43bf-bfd2-00bc02f6c53e", Attributes: map[string]any{"department": "Sales"}}},
+		Limit: 10,
+	}}
+	httpecho.RegisterRoutes(e, nil, httpecho.NewUserHandler(&fakeGetUserUseCase{}, listUsers), nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users?attr.department=Sales&limit=10", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if listUsers.got.Attributes["department"] != "Sales" || listUsers.got.Limit != 10 {
+		t.Fatalf("unexpected input: %+v", listUsers.got)
+	}
+
+	var got struct {
+		Data app.ListUsersOutput `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected json: %v", err)
+	}
+	if len(got.Data.Users) != 1 || got.Data.Users[0].Attributes["department"] != "Sales" {
+		t.Fatalf("unexpected body: %s", rec.Body.String())
+	}
+}
+
+func TestListUsersHandlerInvalidFilter(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	listUsers := &fakeListUsersUseCase{err: app.ErrInvalidUserFilter}
+	httpecho.RegisterRoutes(e, nil, httpecho.NewUserHandler(&fakeGetUserUseCase{}, listUsers), nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users?attr.cost%20center=1", nil)
+	rec := httptest.NewRecorder()
+
+	e.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
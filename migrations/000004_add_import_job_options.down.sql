@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_addresses_user_id_normalized;
+DROP FUNCTION IF EXISTS normalize_address_part(TEXT);
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS options;
@@ -0,0 +1,18 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+CREATE OR REPLACE FUNCTION normalize_address_part(value TEXT)
+RETURNS TEXT
+LANGUAGE SQL
+IMMUTABLE
+PARALLEL SAFE
+AS $$
+    SELECT lower(btrim(regexp_replace(value, '\s+', ' ', 'g')))
+$$;
+
+CREATE INDEX IF NOT EXISTS idx_addresses_user_id_normalized
+    ON addresses (
+        user_id,
+        normalize_address_part(street),
+        normalize_address_part(city),
+        normalize_address_part(zip_code)
+    );
@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS apply_field_update_policy(TEXT, TEXT, TEXT);
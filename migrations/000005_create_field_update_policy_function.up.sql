@@ -0,0 +1,15 @@
+CREATE OR REPLACE FUNCTION apply_field_update_policy(policy TEXT, current_value TEXT, incoming_value TEXT)
+RETURNS TEXT
+LANGUAGE SQL
+IMMUTABLE
+PARALLEL SAFE
+AS $$
+    SELECT CASE policy
+        WHEN 'never' THEN current_value
+        WHEN 'fill_empty' THEN
+            CASE WHEN current_value IS NULL OR btrim(current_value) = '' THEN incoming_value ELSE current_value END
+        WHEN 'if_present' THEN
+            CASE WHEN incoming_value IS NULL OR btrim(incoming_value) = '' THEN current_value ELSE incoming_value END
+        ELSE incoming_value
+    END
+$$;
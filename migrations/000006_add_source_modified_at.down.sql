@@ -0,0 +1,2 @@
+ALTER TABLE stg_users DROP COLUMN IF EXISTS source_modified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS source_modified_at;
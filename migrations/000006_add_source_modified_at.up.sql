@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS source_modified_at TIMESTAMPTZ;
+ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS source_modified_at TIMESTAMPTZ;
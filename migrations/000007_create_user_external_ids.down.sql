@@ -0,0 +1,2 @@
+ALTER TABLE stg_users DROP COLUMN IF EXISTS user_id;
+DROP TABLE IF EXISTS user_external_ids;
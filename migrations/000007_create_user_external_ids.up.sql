@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS user_external_ids (
+    source TEXT NOT NULL,
+    external_id TEXT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source, external_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_external_ids_user_id ON user_external_ids (user_id);
+
+ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS user_id UUID;
@@ -0,0 +1,15 @@
+CREATE OR REPLACE FUNCTION uuid_generate_v7()
+RETURNS UUID
+LANGUAGE plpgsql
+VOLATILE
+AS $$
+DECLARE
+    uuid_bytes BYTEA;
+BEGIN
+    uuid_bytes := substring(int8send(floor(extract(epoch FROM clock_timestamp()) * 1000)::BIGINT) FROM 3)
+        || substring(uuid_send(gen_random_uuid()) FROM 7);
+    uuid_bytes := set_byte(uuid_bytes, 6, (b'0111' || get_byte(uuid_bytes, 6)::BIT(4))::BIT(8)::INT);
+    uuid_bytes := set_byte(uuid_bytes, 8, (b'10' || get_byte(uuid_bytes, 8)::BIT(6))::BIT(8)::INT);
+    RETURN encode(uuid_bytes, 'hex')::UUID;
+END
+$$;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS import_conflicts;
+UPDATE users SET email = id::text || '@email-released.invalid' WHERE email IS NULL;
+ALTER TABLE users ALTER COLUMN email SET NOT NULL;
@@ -0,0 +1,14 @@
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
+
+CREATE TABLE IF NOT EXISTS import_conflicts (
+    id BIGSERIAL PRIMARY KEY,
+    job_id UUID NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    row_index BIGINT NOT NULL,
+    external_id TEXT NOT NULL,
+    id_user_id UUID NOT NULL,
+    email_user_id UUID NOT NULL,
+    resolution TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (job_id, row_index),
+    CHECK (resolution IN ('reject', 'reassign_email', 'merge'))
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_users_email_key;
+DROP TABLE IF EXISTS user_email_key_collisions;
+ALTER TABLE stg_users DROP COLUMN IF EXISTS email_key;
+ALTER TABLE users DROP COLUMN IF EXISTS email_key;
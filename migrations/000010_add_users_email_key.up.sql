@@ -0,0 +1,43 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_key VARCHAR(320);
+ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS email_key TEXT;
+
+UPDATE users
+SET email_key = lower(btrim(email))
+WHERE email IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_email_key_collisions (
+    email_key VARCHAR(320) PRIMARY KEY,
+    user_ids UUID[] NOT NULL,
+    emails TEXT[] NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO user_email_key_collisions (email_key, user_ids, emails)
+SELECT email_key, array_agg(id ORDER BY created_at, id), array_agg(email ORDER BY created_at, id)
+FROM users
+WHERE email_key IS NOT NULL
+GROUP BY email_key
+HAVING COUNT(*) > 1
+ON CONFLICT (email_key) DO UPDATE
+  SET user_ids = EXCLUDED.user_ids,
+      emails = EXCLUDED.emails,
+      detected_at = NOW();
+
+DO $$
+DECLARE
+    collision_count BIGINT;
+BEGIN
+    SELECT COUNT(*) INTO collision_count FROM user_email_key_collisions;
+    IF collision_count > 0 THEN
+        RAISE WARNING '% normalized email collision(s) found, see user_email_key_collisions', collision_count;
+    END IF;
+END
+$$;
+
+UPDATE users u
+SET email_key = NULL
+FROM user_email_key_collisions c
+WHERE u.email_key = c.email_key
+  AND u.id <> c.user_ids[1];
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_key ON users (email_key);
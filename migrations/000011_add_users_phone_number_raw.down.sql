@@ -0,0 +1,2 @@
+ALTER TABLE stg_users DROP COLUMN IF EXISTS phone_number_raw;
+ALTER TABLE users DROP COLUMN IF EXISTS phone_number_raw;
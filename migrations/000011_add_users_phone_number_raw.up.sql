@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_number_raw TEXT;
+ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS phone_number_raw TEXT;
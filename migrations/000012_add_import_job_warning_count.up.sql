@@ -0,0 +1 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS warning_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE stg_addresses DROP COLUMN IF EXISTS is_default;
+ALTER TABLE stg_addresses DROP COLUMN IF EXISTS type;
+
+DROP INDEX IF EXISTS idx_addresses_user_default;
+ALTER TABLE addresses DROP COLUMN IF EXISTS is_default;
+ALTER TABLE addresses DROP COLUMN IF EXISTS type;
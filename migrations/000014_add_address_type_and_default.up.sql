@@ -0,0 +1,17 @@
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS type VARCHAR(20) NOT NULL DEFAULT 'home'
+    CHECK (type IN ('home', 'work', 'billing', 'shipping'));
+ALTER TABLE addresses ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE addresses a
+SET is_default = TRUE
+FROM (
+    SELECT DISTINCT ON (user_id) id
+    FROM addresses
+    ORDER BY user_id, id
+) first_address
+WHERE a.id = first_address.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_user_default ON addresses (user_id) WHERE is_default;
+
+ALTER TABLE stg_addresses ADD COLUMN IF NOT EXISTS type TEXT NOT NULL DEFAULT 'home';
+ALTER TABLE stg_addresses ADD COLUMN IF NOT EXISTS is_default BOOLEAN NOT NULL DEFAULT FALSE;
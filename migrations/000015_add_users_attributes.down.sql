@@ -0,0 +1,6 @@
+DROP FUNCTION IF EXISTS apply_attribute_strategy(TEXT, JSONB, JSONB);
+
+ALTER TABLE stg_users DROP COLUMN IF EXISTS attributes;
+
+DROP INDEX IF EXISTS idx_users_attributes;
+ALTER TABLE users DROP COLUMN IF EXISTS attributes;
@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}'::jsonb;
+CREATE INDEX IF NOT EXISTS idx_users_attributes ON users USING GIN (attributes jsonb_path_ops);
+
+ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS attributes JSONB;
+
+CREATE OR REPLACE FUNCTION apply_attribute_strategy(strategy TEXT, current_value JSONB, incoming_value JSONB)
+RETURNS JSONB
+LANGUAGE SQL
+IMMUTABLE
+PARALLEL SAFE
+AS $$
+    SELECT CASE strategy
+        WHEN 'replace' THEN COALESCE(incoming_value, '{}'::jsonb)
+        ELSE COALESCE(current_value, '{}'::jsonb) || COALESCE(incoming_value, '{}'::jsonb)
+    END
+$$;
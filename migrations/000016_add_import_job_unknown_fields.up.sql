@@ -0,0 +1 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS unknown_fields JSONB NOT NULL DEFAULT '{}'::jsonb;
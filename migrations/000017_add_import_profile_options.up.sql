@@ -0,0 +1 @@
+ALTER TABLE import_profiles ADD COLUMN IF NOT EXISTS options JSONB NOT NULL DEFAULT '{}'::jsonb;
@@ -0,0 +1,2 @@
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS expected_count;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS metadata;
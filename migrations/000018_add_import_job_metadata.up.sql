@@ -0,0 +1,2 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS expected_count BIGINT;
@@ -0,0 +1,23 @@
+DELETE FROM stg_users WHERE email IS NULL;
+ALTER TABLE stg_users ALTER COLUMN email SET NOT NULL;
+ALTER TABLE stg_users DROP COLUMN IF EXISTS source_position;
+
+ALTER TABLE users DROP COLUMN IF EXISTS last_import_position;
+ALTER TABLE users DROP COLUMN IF EXISTS last_import_job_id;
+
+DELETE FROM import_conflicts WHERE resolution = 'superseded';
+ALTER TABLE import_conflicts DROP CONSTRAINT IF EXISTS import_conflicts_resolution_check;
+ALTER TABLE import_conflicts ADD CONSTRAINT import_conflicts_resolution_check
+    CHECK (resolution IN ('reject', 'reassign_email', 'merge'));
+
+DELETE FROM import_jobs WHERE parent_id IS NOT NULL;
+UPDATE import_jobs SET status = 'failed', finished_at = COALESCE(finished_at, NOW()) WHERE status = 'waiting';
+DROP INDEX IF EXISTS idx_import_jobs_parent_id;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS range_end;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS range_start;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS partition_index;
+ALTER TABLE import_jobs DROP COLUMN IF EXISTS parent_id;
+
+ALTER TABLE import_jobs DROP CONSTRAINT IF EXISTS import_jobs_status_check;
+ALTER TABLE import_jobs ADD CONSTRAINT import_jobs_status_check
+    CHECK (status IN ('queued', 'running', 'succeeded', 'failed'));
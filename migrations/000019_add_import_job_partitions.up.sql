@@ -0,0 +1,19 @@
+ALTER TABLE import_jobs DROP CONSTRAINT IF EXISTS import_jobs_status_check;
+ALTER TABLE import_jobs ADD CONSTRAINT import_jobs_status_check
+    CHECK (status IN ('queued', 'running', 'waiting', 'succeeded', 'failed'));
+
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES import_jobs(id) ON DELETE CASCADE;
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS partition_index INT;
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS range_start BIGINT;
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS range_end BIGINT;
+CREATE INDEX IF NOT EXISTS idx_import_jobs_parent_id ON import_jobs (parent_id) WHERE parent_id IS NOT NULL;
+
+ALTER TABLE import_conflicts DROP CONSTRAINT IF EXISTS import_conflicts_resolution_check;
+ALTER TABLE import_conflicts ADD CONSTRAINT import_conflicts_resolution_check
+    CHECK (resolution IN ('reject', 'reassign_email', 'merge', 'superseded'));
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_import_job_id UUID;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_import_position BIGINT;
+
+ALTER TABLE stg_users ADD COLUMN IF NOT EXISTS source_position BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE stg_users ALTER COLUMN email DROP NOT NULL;
@@ -0,0 +1 @@
+ALTER TABLE import_jobs ADD COLUMN IF NOT EXISTS chunk_stats JSONB NOT NULL DEFAULT '{}'::jsonb;
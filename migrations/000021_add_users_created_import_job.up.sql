@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS created_import_job_id UUID;
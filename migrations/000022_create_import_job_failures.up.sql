@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS import_job_failures (
+    job_id UUID NOT NULL REFERENCES import_jobs(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    row_index BIGINT NOT NULL,
+    reason TEXT NOT NULL,
+    rule_id TEXT NOT NULL DEFAULT '',
+    field TEXT NOT NULL DEFAULT '',
+    locator_row BIGINT NOT NULL DEFAULT 0,
+    locator_line BIGINT NOT NULL DEFAULT 0,
+    locator_offset BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (job_id, kind, row_index, reason, rule_id, field),
+    CHECK (kind IN ('failure', 'skip', 'warning'))
+);
@@ -0,0 +1,4 @@
+ALTER TABLE import_conflicts
+    DROP COLUMN IF EXISTS locator_offset,
+    DROP COLUMN IF EXISTS locator_line,
+    DROP COLUMN IF EXISTS locator_row;
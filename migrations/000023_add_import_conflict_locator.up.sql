@@ -0,0 +1,4 @@
+ALTER TABLE import_conflicts
+    ADD COLUMN IF NOT EXISTS locator_row BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS locator_line BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS locator_offset BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Users are identified by email_key. The raw email may differ only in case or
+-- provider rules from the email of a user left without a key by a collision.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
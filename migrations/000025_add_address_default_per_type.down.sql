@@ -0,0 +1,15 @@
+ALTER TABLE stg_addresses DROP COLUMN IF EXISTS default_promoted;
+
+DROP INDEX IF EXISTS idx_addresses_user_type_default;
+
+UPDATE addresses a
+SET is_default = FALSE
+WHERE a.is_default
+  AND a.id NOT IN (
+    SELECT DISTINCT ON (user_id) id
+    FROM addresses
+    WHERE is_default
+    ORDER BY user_id, id
+  );
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_user_default ON addresses (user_id) WHERE is_default;
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_addresses_user_default;
+
+UPDATE addresses a
+SET is_default = TRUE
+FROM (
+    SELECT DISTINCT ON (e.user_id, e.type) e.id
+    FROM addresses e
+    WHERE NOT EXISTS (
+        SELECT 1 FROM addresses d WHERE d.user_id = e.user_id AND d.type = e.type AND d.is_default
+    )
+    ORDER BY e.user_id, e.type, e.id
+) first_address
+WHERE a.id = first_address.id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addresses_user_type_default ON addresses (user_id, type) WHERE is_default;
+
+ALTER TABLE stg_addresses ADD COLUMN IF NOT EXISTS default_promoted BOOLEAN NOT NULL DEFAULT FALSE;